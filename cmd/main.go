@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"go-electroshop/config"
 	"go-electroshop/internal/router"
+	"go-electroshop/internal/scheduler"
 	"go-electroshop/internal/utility"
 	"go-electroshop/middleware"
 	"log"
@@ -63,6 +65,12 @@ func main() {
 	// setup router
 	router.InitRoutes(r, db)
 
+	// start background jobs
+	jobScheduler := scheduler.New()
+	scheduler.RegisterJobs(jobScheduler, db)
+	jobScheduler.Start(context.Background())
+	defer jobScheduler.Stop()
+
 	serverPort := os.Getenv("SERVER_PORT")
 	if serverPort == "" {
 		serverPort = "8080"
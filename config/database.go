@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/utility"
 	"os"
 
 	"github.com/sirupsen/logrus"
@@ -19,7 +20,8 @@ func ConnectDB() *gorm.DB {
 		port     = os.Getenv("DB_PORT")
 	)
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Shanghai", host, user, password, dbname, port)
+	// koneksi selalu UTC, timezone user diterapkan di aplikasi (lihat entity.User.Timezone)
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC", host, user, password, dbname, port)
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		logrus.Errorf("Failed connect to the database: %v", err)
@@ -27,13 +29,77 @@ func ConnectDB() *gorm.DB {
 
 	if err = db.AutoMigrate(
 		&entity.User{},
+		&entity.Ledger{},
+		&entity.LedgerMember{},
+		&entity.LedgerInvitation{},
 		&entity.Category{},
+		&entity.CategoryRule{},
+		&entity.Tag{},
+		&entity.Account{},
 		&entity.Transaction{},
+		&entity.TransactionSplit{},
+		&entity.TransactionAttachment{},
+		&entity.Transfer{},
+		&entity.ExchangeRate{},
+		&entity.ReportSchedule{},
+		&entity.Insight{},
 		&entity.Product{},
 		&entity.CartItem{},
+		&entity.ProductView{},
+		&entity.ProductViewStat{},
+		&entity.ProductPriceHistory{},
+		&entity.PriceAlert{},
+		&entity.StockSubscription{},
+		&entity.LoyaltyAccount{},
+		&entity.LoyaltyLedgerEntry{},
+		&entity.GiftCard{},
+		&entity.GiftCardUsage{},
+		&entity.Budget{},
+		&entity.SavingsGoal{},
+		&entity.SavingsContribution{},
+		&entity.RecurringTransaction{},
+		&entity.RecurringOccurrence{},
 	); err != nil {
 		logrus.Fatal("Auto migration failed:", err)
 	}
 
+	// GORM tidak bisa membuat index ekspresi yang mengandung koma lewat tag struct
+	if err = db.Exec("CREATE INDEX IF NOT EXISTS idx_transactions_description_search ON transactions USING gin (" +
+		utility.TransactionSearchVectorSQL + ")").Error; err != nil {
+		logrus.Fatal("Failed to create transaction search index:", err)
+	}
+
+	if err = migratePersonalLedgers(db); err != nil {
+		logrus.Fatal("Failed to migrate personal ledgers:", err)
+	}
+
 	return db
 }
+
+// migratePersonalLedgers membuat personal ledger untuk setiap user dan memindahkan kategori
+// serta transaksi yang belum punya ledger ke personal ledger pembuatnya. Aman dijalankan
+// berulang kali, kolom ledger_id dibiarkan nullable supaya AutoMigrate bisa menambahkannya ke
+// tabel yang sudah berisi data.
+func migratePersonalLedgers(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			"CREATE UNIQUE INDEX IF NOT EXISTS idx_ledgers_personal_owner ON ledgers (owner_id) WHERE personal AND deleted_at IS NULL",
+			"INSERT INTO ledgers (name, owner_id, personal, created_at, updated_at) " +
+				"SELECT 'Personal', users.id, true, NOW(), NOW() FROM users WHERE NOT EXISTS " +
+				"(SELECT 1 FROM ledgers WHERE ledgers.owner_id = users.id AND ledgers.personal AND ledgers.deleted_at IS NULL)",
+			"INSERT INTO ledger_members (ledger_id, user_id, role, created_at, updated_at) " +
+				"SELECT ledgers.id, ledgers.owner_id, '" + entity.LedgerRoleOwner + "', NOW(), NOW() FROM ledgers WHERE ledgers.personal AND NOT EXISTS " +
+				"(SELECT 1 FROM ledger_members WHERE ledger_members.ledger_id = ledgers.id AND ledger_members.user_id = ledgers.owner_id)",
+			"UPDATE categories SET ledger_id = ledgers.id FROM ledgers " +
+				"WHERE COALESCE(categories.ledger_id, 0) = 0 AND ledgers.owner_id = categories.user_id AND ledgers.personal AND ledgers.deleted_at IS NULL",
+			"UPDATE transactions SET ledger_id = ledgers.id FROM ledgers " +
+				"WHERE COALESCE(transactions.ledger_id, 0) = 0 AND ledgers.owner_id = transactions.user_id AND ledgers.personal AND ledgers.deleted_at IS NULL",
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountController struct {
+	AccountService  *service.AccountService
+	TransferService *service.TransferService
+}
+
+func NewAccountController(accountService *service.AccountService, transferService *service.TransferService) *AccountController {
+	return &AccountController{
+		AccountService:  accountService,
+		TransferService: transferService,
+	}
+}
+
+// GetAccountsHandler godoc
+// @Summary 	Get accounts
+// @Description Get all accounts (cash, bank, e-wallet, credit card) with their current balance
+// @Tags 		accounts
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=[]response.AccountResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/accounts [get]
+func (c *AccountController) GetAccountsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	accounts, err := c.AccountService.GetAccounts(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get accounts", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get accounts successful",
+		Data:            accounts,
+	})
+}
+
+// CreateAccountHandler godoc
+// @Summary 	Create account
+// @Description Create an account with an opening balance
+// @Tags 		accounts
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.AccountRequest true "Account data"
+// @Success 	201 {object} response.SuccessResponse{data=response.AccountResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/accounts [post]
+func (c *AccountController) CreateAccountHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.AccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	account, err := c.AccountService.CreateAccount(userID, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Account created",
+		Data:            account,
+	})
+}
+
+// UpdateAccountHandler godoc
+// @Summary 	Update account
+// @Description Update account name, type or opening balance
+// @Tags 		accounts
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Account ID"
+// @Param 		request body request.AccountRequest true "Account data"
+// @Success 	200 {object} response.SuccessResponse{data=response.AccountResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/accounts/{id} [put]
+func (c *AccountController) UpdateAccountHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid account ID", nil)
+		return
+	}
+
+	var req request.AccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	account, err := c.AccountService.UpdateAccount(userID, uint(id), req)
+	if err != nil {
+		if errors.Is(err, service.ErrAccountNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Account updated",
+		Data:            account,
+	})
+}
+
+// DeleteAccountHandler godoc
+// @Summary 	Delete account
+// @Description Delete an account that has no transactions or transfers
+// @Tags 		accounts
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Account ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/accounts/{id} [delete]
+func (c *AccountController) DeleteAccountHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid account ID", nil)
+		return
+	}
+
+	if err := c.AccountService.DeleteAccount(userID, uint(id)); err != nil {
+		if errors.Is(err, service.ErrAccountNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Account deleted",
+		Data:            nil,
+	})
+}
+
+// GetTransfersHandler godoc
+// @Summary 	Get transfers
+// @Description Get transfers between accounts with pagination
+// @Tags 		accounts
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		account_id 	query 	int 	false 	"Only transfers from or to this account"
+// @Param 		page 		query 	int 	false 	"Page number"
+// @Param 		limit 		query 	int 	false 	"Limit per page"
+// @Success 	200 {object} response.SuccessResponse{data=response.TransferListResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/transfers [get]
+func (c *AccountController) GetTransfersHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.TransferFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid filter parameters: "+err.Error(), nil)
+		return
+	}
+
+	transfers, err := c.TransferService.GetTransfers(userID, filter)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get transfers", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get transfers successful",
+		Data:            transfers,
+	})
+}
+
+// CreateTransferHandler godoc
+// @Summary 	Create transfer
+// @Description Move money between two accounts. Transfers are not counted as income or expense.
+// @Tags 		accounts
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.TransferRequest true "Transfer data"
+// @Success 	201 {object} response.SuccessResponse{data=response.TransferResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/transfers [post]
+func (c *AccountController) CreateTransferHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.TransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	transfer, err := c.TransferService.CreateTransfer(userID, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Transfer created",
+		Data:            transfer,
+	})
+}
+
+// DeleteTransferHandler godoc
+// @Summary 	Delete transfer
+// @Description Delete a transfer between accounts
+// @Tags 		accounts
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Transfer ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/transfers/{id} [delete]
+func (c *AccountController) DeleteTransferHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid transfer ID", nil)
+		return
+	}
+
+	if err := c.TransferService.DeleteTransfer(userID, uint(id)); err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Transfer deleted",
+		Data:            nil,
+	})
+}
@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type AttachmentController struct {
+	AttachmentService *service.AttachmentService
+}
+
+func NewAttachmentController(attachmentService *service.AttachmentService) *AttachmentController {
+	return &AttachmentController{AttachmentService: attachmentService}
+}
+
+// GetAttachmentsHandler godoc
+// @Summary 	Get transaction attachments
+// @Description Get receipt and attachment metadata of a transaction
+// @Tags 		transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Transaction ID"
+// @Success 	200 {object} response.SuccessResponse{data=[]response.AttachmentResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/transaction/{id}/attachments [get]
+func (c *AttachmentController) GetAttachmentsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	transactionID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid transaction ID", nil)
+		return
+	}
+
+	attachments, err := c.AttachmentService.GetAttachments(userID, uint(transactionID))
+	if err != nil {
+		if errors.Is(err, service.ErrTransactionNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to get attachments", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get attachments successful",
+		Data:            attachments,
+	})
+}
+
+// UploadAttachmentHandler godoc
+// @Summary 	Upload transaction attachment
+// @Description Attach a receipt image (JPEG/PNG/WEBP) or PDF of max 5MB to a transaction
+// @Tags 		transactions
+// @Accept 		multipart/form-data
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id 		path 		int 	true 	"Transaction ID"
+// @Param 		file 	formData 	file 	true 	"Attachment file"
+// @Success 	201 {object} response.SuccessResponse{data=response.AttachmentResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/transaction/{id}/attachments [post]
+func (c *AttachmentController) UploadAttachmentHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	transactionID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid transaction ID", nil)
+		return
+	}
+
+	// batasi body request supaya upload besar ditolak sebelum diparsing
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, service.MaxAttachmentSize+(1<<20))
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "File is required and must not exceed 5MB", nil)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logrus.Errorf("Error opening attachment: %v", err)
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Failed to read file", nil)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := c.AttachmentService.UploadAttachment(ctx.Request.Context(), userID, uint(transactionID), fileHeader.Filename, fileHeader.Size, file)
+	if err != nil {
+		if errors.Is(err, service.ErrTransactionNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Attachment uploaded",
+		Data:            attachment,
+	})
+}
+
+// DownloadAttachmentHandler godoc
+// @Summary 	Download transaction attachment
+// @Description Download the original attachment file
+// @Tags 		transactions
+// @Produce 	application/octet-stream
+// @Security 	BearerAuth
+// @Param 		id 				path 	int 	true 	"Transaction ID"
+// @Param 		attachmentId 	path 	int 	true 	"Attachment ID"
+// @Success 	200 {file} file "Attachment file"
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/transaction/{id}/attachments/{attachmentId} [get]
+func (c *AttachmentController) DownloadAttachmentHandler(ctx *gin.Context) {
+	userID, transactionID, attachmentID, ok := attachmentParams(ctx)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := c.AttachmentService.OpenAttachment(ctx.Request.Context(), userID, transactionID, attachmentID)
+	if err != nil {
+		if errors.Is(err, service.ErrAttachmentNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to download attachment", err)
+		return
+	}
+	defer content.Close()
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", attachment.FileName))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, nil)
+}
+
+// DeleteAttachmentHandler godoc
+// @Summary 	Delete transaction attachment
+// @Description Delete an attachment and its stored file
+// @Tags 		transactions
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id 				path 	int 	true 	"Transaction ID"
+// @Param 		attachmentId 	path 	int 	true 	"Attachment ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/transaction/{id}/attachments/{attachmentId} [delete]
+func (c *AttachmentController) DeleteAttachmentHandler(ctx *gin.Context) {
+	userID, transactionID, attachmentID, ok := attachmentParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.AttachmentService.DeleteAttachment(ctx.Request.Context(), userID, transactionID, attachmentID); err != nil {
+		if errors.Is(err, service.ErrAttachmentNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Attachment deleted",
+		Data:            nil,
+	})
+}
+
+func attachmentParams(ctx *gin.Context) (uint, uint, uint, bool) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return 0, 0, 0, false
+	}
+
+	transactionID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid transaction ID", nil)
+		return 0, 0, 0, false
+	}
+
+	attachmentID, err := strconv.ParseUint(ctx.Param("attachmentId"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid attachment ID", nil)
+		return 0, 0, 0, false
+	}
+
+	return userID, uint(transactionID), uint(attachmentID), true
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BudgetController struct {
+	BudgetService *service.BudgetService
+}
+
+func NewBudgetController(budgetService *service.BudgetService) *BudgetController {
+	return &BudgetController{BudgetService: budgetService}
+}
+
+// GetBudgetsHandler godoc
+// @Summary 	Get budgets
+// @Description Get monthly budgets per category with spent, limit and remaining amount for the current month
+// @Tags 		budgets
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.BudgetListResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/budgets [get]
+func (c *BudgetController) GetBudgetsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	budgets, err := c.BudgetService.GetBudgets(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get budgets", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get budgets successful",
+		Data:            budgets,
+	})
+}
+
+// CreateBudgetHandler godoc
+// @Summary 	Create budget
+// @Description Set a monthly spending limit for a category
+// @Tags 		budgets
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.BudgetRequest true "Budget data"
+// @Success 	201 {object} response.SuccessResponse{data=response.BudgetResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/budgets [post]
+func (c *BudgetController) CreateBudgetHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.BudgetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	budget, err := c.BudgetService.CreateBudget(userID, &req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Budget created",
+		Data:            budget,
+	})
+}
+
+// UpdateBudgetHandler godoc
+// @Summary 	Update budget
+// @Description Update the monthly spending limit of a budget
+// @Tags 		budgets
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Budget ID"
+// @Param 		request body request.UpdateBudgetRequest true "Budget data"
+// @Success 	200 {object} response.SuccessResponse{data=response.BudgetResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/budgets/{id} [put]
+func (c *BudgetController) UpdateBudgetHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid budget ID", nil)
+		return
+	}
+
+	var req request.UpdateBudgetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	budget, err := c.BudgetService.UpdateBudget(uint(id), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrBudgetNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Budget updated",
+		Data:            budget,
+	})
+}
+
+// DeleteBudgetHandler godoc
+// @Summary 	Delete budget
+// @Description Delete a category budget
+// @Tags 		budgets
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Budget ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/budgets/{id} [delete]
+func (c *BudgetController) DeleteBudgetHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid budget ID", nil)
+		return
+	}
+
+	if err := c.BudgetService.DeleteBudget(uint(id), userID); err != nil {
+		if errors.Is(err, service.ErrBudgetNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Budget deleted",
+		Data:            nil,
+	})
+}
@@ -75,6 +75,7 @@ func (c *cartController) GetCartHandler(ctx *gin.Context) {
 				Category:  item.Product.Category,
 				Name:      item.Product.Name,
 				Price:     item.Product.Price,
+				Stock:     item.Product.Stock,
 				ImageLink: item.Product.ImageLink,
 				CreatedAt: item.Product.CreatedAt,
 				UpdatedAt: item.Product.UpdatedAt,
@@ -22,9 +22,11 @@ type CategoryController struct {
 // @Accept 		json
 // @Produce 	json
 // @Security 	BearerAuth
+// @Param 		ledger_id query int false "Ledger ID, defaults to the personal ledger"
 // @Success 	200 {object} response.SuccessResponse
 // @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
 // @Router 		/category [get]
 func (c *CategoryController) GetAllCategoriesHandler(ctx *gin.Context) {
 	userID, err := utility.GetUserIDFromContext(ctx)
@@ -33,9 +35,15 @@ func (c *CategoryController) GetAllCategoriesHandler(ctx *gin.Context) {
 		return
 	}
 
-	categories, err := c.CategoryService.GetCategories(userID)
+	var filter request.CategoryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid filter parameters: "+err.Error(), nil)
+		return
+	}
+
+	categories, err := c.CategoryService.GetCategories(userID, filter)
 	if err != nil {
-		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		ledgerErrorResponse(ctx, err)
 		return
 	}
 
@@ -113,7 +121,7 @@ func (c *CategoryController) CreateCategoryHandler(ctx *gin.Context) {
 
 	category, err := c.CategoryService.CreateCategory(&req, userID)
 	if err != nil {
-		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		ledgerErrorResponse(ctx, err)
 		return
 	}
 
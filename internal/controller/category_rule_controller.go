@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryRuleController struct {
+	CategoryRuleService *service.CategoryRuleService
+}
+
+func NewCategoryRuleController(categoryRuleService *service.CategoryRuleService) *CategoryRuleController {
+	return &CategoryRuleController{CategoryRuleService: categoryRuleService}
+}
+
+// GetCategoryRulesHandler godoc
+// @Summary 	Get category rules
+// @Description Get auto-categorisation rules of logged in user ordered by priority
+// @Tags 		category-rules
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=[]response.CategoryRuleResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/category-rules [get]
+func (c *CategoryRuleController) GetCategoryRulesHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	rules, err := c.CategoryRuleService.GetCategoryRules(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get category rules", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get category rules successful",
+		Data:            rules,
+	})
+}
+
+// CreateCategoryRuleHandler godoc
+// @Summary 	Create category rule
+// @Description Create a rule (description contains/regex, amount range, type) that assigns a category to new and imported transactions. Lower priority is evaluated first.
+// @Tags 		category-rules
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.CategoryRuleRequest true "Category rule data"
+// @Success 	201 {object} response.SuccessResponse{data=response.CategoryRuleResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/category-rules [post]
+func (c *CategoryRuleController) CreateCategoryRuleHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.CategoryRuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	rule, err := c.CategoryRuleService.CreateCategoryRule(userID, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Category rule created",
+		Data:            rule,
+	})
+}
+
+// UpdateCategoryRuleHandler godoc
+// @Summary 	Update category rule
+// @Description Update an auto-categorisation rule. Existing transactions are not changed until rules are re-applied.
+// @Tags 		category-rules
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Category rule ID"
+// @Param 		request body request.CategoryRuleRequest true "Category rule data"
+// @Success 	200 {object} response.SuccessResponse{data=response.CategoryRuleResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/category-rules/{id} [put]
+func (c *CategoryRuleController) UpdateCategoryRuleHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid category rule ID", nil)
+		return
+	}
+
+	var req request.CategoryRuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	rule, err := c.CategoryRuleService.UpdateCategoryRule(userID, uint(id), req)
+	if err != nil {
+		if errors.Is(err, service.ErrCategoryRuleNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Category rule updated",
+		Data:            rule,
+	})
+}
+
+// DeleteCategoryRuleHandler godoc
+// @Summary 	Delete category rule
+// @Description Delete an auto-categorisation rule
+// @Tags 		category-rules
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Category rule ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/category-rules/{id} [delete]
+func (c *CategoryRuleController) DeleteCategoryRuleHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid category rule ID", nil)
+		return
+	}
+
+	if err := c.CategoryRuleService.DeleteCategoryRule(userID, uint(id)); err != nil {
+		if errors.Is(err, service.ErrCategoryRuleNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Category rule deleted",
+		Data:            nil,
+	})
+}
+
+// ApplyCategoryRulesHandler godoc
+// @Summary 	Re-apply category rules
+// @Description Re-apply category rules to existing transactions and report how many rows changed. Transactions without a matching rule are left untouched.
+// @Tags 		category-rules
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.ApplyCategoryRulesRequest false "Optional date range"
+// @Success 	200 {object} response.SuccessResponse{data=response.ApplyCategoryRulesResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/category-rules/apply [post]
+func (c *CategoryRuleController) ApplyCategoryRulesHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.ApplyCategoryRulesRequest
+	// body boleh kosong untuk menerapkan ke semua transaksi
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utility.ValidationErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	result, err := c.CategoryRuleService.ApplyCategoryRules(userID, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Category rules applied",
+		Data:            result,
+	})
+}
@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"errors"
+	"go-electroshop/internal/payload/request"
 	"go-electroshop/internal/payload/response"
 	"go-electroshop/internal/service"
 	"go-electroshop/internal/utility"
@@ -55,12 +57,17 @@ func (c *DashboardController) GetFinancialOverviewHandler(ctx *gin.Context) {
 
 // GetDashboardChartsHandler godoc
 // @Summary 	Get dashboard charts data
-// @Description Get user's dashboard charts including income vs expense, category distribution, and top expenses
+// @Description Get user's dashboard charts including income vs expense, category distribution, top expenses and cash flow forecast
 // Tags 		dashboard
 // @Accept 		json
 // @Produce 	json
 // @Security 	BearerAuth
+// @Param 		from 			query string false "Start date (YYYY-MM-DD), default first day of the month five months ago"
+// @Param 		to 				query string false "End date inclusive (YYYY-MM-DD), default today"
+// @Param 		granularity 	query string false "Bucket size (day/week/month/quarter/year), default month"
+// @Param 		forecast_months query int false "Forecast horizon in months (3, 6 or 12)"
 // @Success 	200 {object} response.SuccessResponse{data=response.RespDashboardCharts}
+// @Failure 	400 {object} response.ErrorResponse
 // @Failure 	401 {object} response.ErrorResponse
 // @Failure 	500 {object} response.ErrorResponse
 // @Router 		/dashboard/charts [get]
@@ -72,8 +79,18 @@ func (c *DashboardController) GetDashboardChartsHandler(ctx *gin.Context) {
 		return
 	}
 
-	charts, err := c.DashboardService.GetDashboardCharts(userID)
+	var filter request.DashboardChartsFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	charts, err := c.DashboardService.GetDashboardCharts(userID, filter)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidChartDate) || errors.Is(err, service.ErrInvalidChartRange) || errors.Is(err, service.ErrTooManyChartBuckets) {
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		logrus.Errorf("Error getting dashboard charts: %v", err)
 		utility.InternalServerErrorResponse(ctx, "Failed to get dashboard charts", err)
 		return
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRateController struct {
+	ExchangeRateService *service.ExchangeRateService
+}
+
+func NewExchangeRateController(exchangeRateService *service.ExchangeRateService) *ExchangeRateController {
+	return &ExchangeRateController{ExchangeRateService: exchangeRateService}
+}
+
+// GetExchangeRatesHandler godoc
+// @Summary 	Get exchange rates
+// @Description Get exchange rates of logged in user, newest first
+// @Tags 		exchange-rates
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		currency query string false "Only rates from or to this currency (e.g. USD)"
+// @Success 	200 {object} response.SuccessResponse{data=[]response.ExchangeRateResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/exchange-rates [get]
+func (c *ExchangeRateController) GetExchangeRatesHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.ExchangeRateFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	rates, err := c.ExchangeRateService.GetExchangeRates(userID, filter)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get exchange rates successful",
+		Data:            rates,
+	})
+}
+
+// SaveExchangeRateHandler godoc
+// @Summary 	Save exchange rate
+// @Description Enter a manual exchange rate (1 from_currency = rate to_currency) effective from the given date. An existing rate for the same pair and date is replaced.
+// @Tags 		exchange-rates
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.ExchangeRateRequest true "Exchange rate data"
+// @Success 	200 {object} response.SuccessResponse{data=response.ExchangeRateResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/exchange-rates [post]
+func (c *ExchangeRateController) SaveExchangeRateHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.ExchangeRateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	rate, err := c.ExchangeRateService.SaveExchangeRate(userID, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Exchange rate saved",
+		Data:            rate,
+	})
+}
+
+// DeleteExchangeRateHandler godoc
+// @Summary 	Delete exchange rate
+// @Description Delete an exchange rate
+// @Tags 		exchange-rates
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Exchange rate ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/exchange-rates/{id} [delete]
+func (c *ExchangeRateController) DeleteExchangeRateHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid exchange rate ID", nil)
+		return
+	}
+
+	if err := c.ExchangeRateService.DeleteExchangeRate(userID, uint(id)); err != nil {
+		if errors.Is(err, service.ErrExchangeRateNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Exchange rate deleted",
+		Data:            nil,
+	})
+}
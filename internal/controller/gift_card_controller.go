@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GiftCardController struct {
+	GiftCardService *service.GiftCardService
+}
+
+func NewGiftCardController(giftCardService *service.GiftCardService) *GiftCardController {
+	return &GiftCardController{GiftCardService: giftCardService}
+}
+
+// IssueGiftCardHandler godoc
+// @Summary 	Issue gift card
+// @Description Issue a new gift card with a balance. The plain code is only returned in this response.
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.IssueGiftCardRequest true "Gift card data"
+// @Success 	201 {object} response.SuccessResponse{data=response.GiftCardResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/admin/gift-cards [post]
+func (c *GiftCardController) IssueGiftCardHandler(ctx *gin.Context) {
+	adminID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.IssueGiftCardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	giftCard, err := c.GiftCardService.Issue(adminID, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Gift card issued",
+		Data:            giftCard,
+	})
+}
+
+// GetGiftCardsHandler godoc
+// @Summary 	Get gift cards
+// @Description Get all issued gift cards with pagination, newest first
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		page 	query 	int 	false 	"Page number"
+// @Param 		limit 	query 	int 	false 	"Limit per page"
+// @Success 	200 {object} response.SuccessResponse{data=response.GiftCardListResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/admin/gift-cards [get]
+func (c *GiftCardController) GetGiftCardsHandler(ctx *gin.Context) {
+	var filter request.GiftCardFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid filter parameters: "+err.Error(), nil)
+		return
+	}
+
+	giftCards, err := c.GiftCardService.GetGiftCards(filter)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get gift cards", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get gift cards successful",
+		Data:            giftCards,
+	})
+}
+
+// DisableGiftCardHandler godoc
+// @Summary 	Disable gift card
+// @Description Disable a gift card so its remaining balance can no longer be used
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id 	path 	int 	true 	"Gift card ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/admin/gift-cards/{id}/disable [put]
+func (c *GiftCardController) DisableGiftCardHandler(ctx *gin.Context) {
+	giftCardID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid gift card ID", nil)
+		return
+	}
+
+	if err := c.GiftCardService.DisableGiftCard(uint(giftCardID)); err != nil {
+		if errors.Is(err, service.ErrGiftCardNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to disable gift card", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Gift card disabled",
+	})
+}
+
+// CheckGiftCardHandler godoc
+// @Summary 	Check gift card balance
+// @Description Check the remaining balance of a gift card code before applying it at checkout
+// @Tags 		gift-card
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.GiftCardCodeRequest true "Gift card code"
+// @Success 	200 {object} response.SuccessResponse{data=response.GiftCardBalanceResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/gift-cards/check [post]
+func (c *GiftCardController) CheckGiftCardHandler(ctx *gin.Context) {
+	var req request.GiftCardCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	balance, err := c.GiftCardService.CheckBalance(req.Code)
+	if err != nil {
+		if errors.Is(err, service.ErrGiftCardNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to check gift card", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Check gift card successful",
+		Data:            balance,
+	})
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InsightController struct {
+	InsightService *service.InsightService
+}
+
+func NewInsightController(insightService *service.InsightService) *InsightController {
+	return &InsightController{InsightService: insightService}
+}
+
+// GetInsightsHandler godoc
+// @Summary 	Get insights feed
+// @Description Get spending insights of logged in user: unusually large transactions and categories spending above their typical month. Insights are recomputed periodically in the background, most severe first.
+// @Tags 		insights
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		include_dismissed query bool false "Include dismissed insights"
+// @Success 	200 {object} response.SuccessResponse{data=[]response.InsightResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/insights [get]
+func (c *InsightController) GetInsightsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.InsightFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	insights, err := c.InsightService.GetInsights(userID, filter)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get insights successful",
+		Data:            insights,
+	})
+}
+
+// DismissInsightHandler godoc
+// @Summary 	Dismiss insight
+// @Description Hide an insight from the feed. It stays hidden when insights are recomputed.
+// @Tags 		insights
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Insight ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/insights/{id}/dismiss [post]
+func (c *InsightController) DismissInsightHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	insightID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid insight ID", nil)
+		return
+	}
+
+	if err := c.InsightService.DismissInsight(userID, uint(insightID)); err != nil {
+		if errors.Is(err, service.ErrInsightNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Insight dismissed",
+		Data:            nil,
+	})
+}
@@ -0,0 +1,512 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LedgerController struct {
+	LedgerService *service.LedgerService
+}
+
+func NewLedgerController(ledgerService *service.LedgerService) *LedgerController {
+	return &LedgerController{LedgerService: ledgerService}
+}
+
+// GetLedgersHandler godoc
+// @Summary 	Get ledgers
+// @Description Get ledgers the logged in user is a member of, including the personal ledger
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=[]response.LedgerResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/ledgers [get]
+func (c *LedgerController) GetLedgersHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	ledgers, err := c.LedgerService.GetLedgers(userID)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get ledgers successful",
+		Data:            ledgers,
+	})
+}
+
+// GetLedgerHandler godoc
+// @Summary 	Get ledger
+// @Description Get a ledger with its members. Pending invitations are only shown to the owner.
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Ledger ID"
+// @Success 	200 {object} response.SuccessResponse{data=response.LedgerDetailResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/ledgers/{id} [get]
+func (c *LedgerController) GetLedgerHandler(ctx *gin.Context) {
+	userID, ledgerID, ok := ledgerParams(ctx, "id", "Invalid ledger ID")
+	if !ok {
+		return
+	}
+
+	ledger, err := c.LedgerService.GetLedger(userID, ledgerID)
+	if err != nil {
+		ledgerErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get ledger successful",
+		Data:            ledger,
+	})
+}
+
+// CreateLedgerHandler godoc
+// @Summary 	Create ledger
+// @Description Create a shared ledger owned by the logged in user
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.LedgerRequest true "Ledger data"
+// @Success 	201 {object} response.SuccessResponse{data=response.LedgerResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/ledgers [post]
+func (c *LedgerController) CreateLedgerHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.LedgerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	ledger, err := c.LedgerService.CreateLedger(userID, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Ledger created",
+		Data:            ledger,
+	})
+}
+
+// UpdateLedgerHandler godoc
+// @Summary 	Rename ledger
+// @Description Rename a ledger, only the owner can do this
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Ledger ID"
+// @Param 		request body request.LedgerRequest true "Ledger data"
+// @Success 	200 {object} response.SuccessResponse{data=response.LedgerResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/ledgers/{id} [put]
+func (c *LedgerController) UpdateLedgerHandler(ctx *gin.Context) {
+	userID, ledgerID, ok := ledgerParams(ctx, "id", "Invalid ledger ID")
+	if !ok {
+		return
+	}
+
+	var req request.LedgerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	ledger, err := c.LedgerService.UpdateLedger(userID, ledgerID, req)
+	if err != nil {
+		ledgerErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Ledger updated",
+		Data:            ledger,
+	})
+}
+
+// DeleteLedgerHandler godoc
+// @Summary 	Delete ledger
+// @Description Delete a shared ledger with its categories, members and invitations. Only the owner can do this and the ledger must not have transactions, including trashed ones.
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Ledger ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Failure 	409 {object} response.SuccessResponse
+// @Router 		/ledgers/{id} [delete]
+func (c *LedgerController) DeleteLedgerHandler(ctx *gin.Context) {
+	userID, ledgerID, ok := ledgerParams(ctx, "id", "Invalid ledger ID")
+	if !ok {
+		return
+	}
+
+	if err := c.LedgerService.DeleteLedger(userID, ledgerID); err != nil {
+		ledgerErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Ledger deleted",
+		Data:            nil,
+	})
+}
+
+// GetLedgerSummaryHandler godoc
+// @Summary 	Get ledger summary
+// @Description Get income and expense of a ledger for a month, aggregated across all members, per member and per category. Amounts are in the base currency of the logged in user.
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Ledger ID"
+// @Param 		month query string false "Month in YYYY-MM format, defaults to the current month"
+// @Success 	200 {object} response.SuccessResponse{data=response.LedgerSummaryResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/ledgers/{id}/summary [get]
+func (c *LedgerController) GetLedgerSummaryHandler(ctx *gin.Context) {
+	userID, ledgerID, ok := ledgerParams(ctx, "id", "Invalid ledger ID")
+	if !ok {
+		return
+	}
+
+	var filter request.LedgerSummaryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	summary, err := c.LedgerService.GetLedgerSummary(userID, ledgerID, filter)
+	if err != nil {
+		ledgerErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get ledger summary successful",
+		Data:            summary,
+	})
+}
+
+// InviteMemberHandler godoc
+// @Summary 	Invite ledger member
+// @Description Invite someone by email to join a shared ledger as editor or viewer. Inviting the same email again renews the invitation. Only the owner can do this.
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Ledger ID"
+// @Param 		request body request.LedgerInvitationRequest true "Invitation data"
+// @Success 	201 {object} response.SuccessResponse{data=response.LedgerInvitationResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Failure 	409 {object} response.SuccessResponse
+// @Router 		/ledgers/{id}/invitations [post]
+func (c *LedgerController) InviteMemberHandler(ctx *gin.Context) {
+	userID, ledgerID, ok := ledgerParams(ctx, "id", "Invalid ledger ID")
+	if !ok {
+		return
+	}
+
+	var req request.LedgerInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	invitation, err := c.LedgerService.InviteMember(ctx.Request.Context(), userID, ledgerID, req)
+	if err != nil {
+		ledgerErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Invitation sent",
+		Data:            invitation,
+	})
+}
+
+// RevokeInvitationHandler godoc
+// @Summary 	Revoke ledger invitation
+// @Description Revoke a pending invitation, only the owner can do this
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Ledger ID"
+// @Param 		invitationId path int true "Invitation ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/ledgers/{id}/invitations/{invitationId} [delete]
+func (c *LedgerController) RevokeInvitationHandler(ctx *gin.Context) {
+	userID, ledgerID, ok := ledgerParams(ctx, "id", "Invalid ledger ID")
+	if !ok {
+		return
+	}
+
+	invitationID, err := strconv.ParseUint(ctx.Param("invitationId"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid invitation ID", nil)
+		return
+	}
+
+	if err := c.LedgerService.RevokeInvitation(userID, ledgerID, uint(invitationID)); err != nil {
+		ledgerErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Invitation revoked",
+		Data:            nil,
+	})
+}
+
+// GetInvitationsHandler godoc
+// @Summary 	Get my ledger invitations
+// @Description Get pending ledger invitations sent to the email of the logged in user
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=[]response.LedgerInvitationResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/ledgers/invitations [get]
+func (c *LedgerController) GetInvitationsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	invitations, err := c.LedgerService.GetInvitations(userID)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get invitations successful",
+		Data:            invitations,
+	})
+}
+
+// AcceptInvitationHandler godoc
+// @Summary 	Accept ledger invitation
+// @Description Join the ledger of an invitation sent to the email of the logged in user
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Invitation ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/ledgers/invitations/{id}/accept [post]
+func (c *LedgerController) AcceptInvitationHandler(ctx *gin.Context) {
+	userID, invitationID, ok := ledgerParams(ctx, "id", "Invalid invitation ID")
+	if !ok {
+		return
+	}
+
+	if err := c.LedgerService.AcceptInvitation(userID, invitationID); err != nil {
+		ledgerErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Invitation accepted",
+		Data:            nil,
+	})
+}
+
+// DeclineInvitationHandler godoc
+// @Summary 	Decline ledger invitation
+// @Description Decline an invitation sent to the email of the logged in user
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Invitation ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/ledgers/invitations/{id} [delete]
+func (c *LedgerController) DeclineInvitationHandler(ctx *gin.Context) {
+	userID, invitationID, ok := ledgerParams(ctx, "id", "Invalid invitation ID")
+	if !ok {
+		return
+	}
+
+	if err := c.LedgerService.DeclineInvitation(userID, invitationID); err != nil {
+		ledgerErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Invitation declined",
+		Data:            nil,
+	})
+}
+
+// UpdateMemberRoleHandler godoc
+// @Summary 	Change ledger member role
+// @Description Change the role of a member to editor or viewer, only the owner can do this
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Ledger ID"
+// @Param 		userId path int true "Member user ID"
+// @Param 		request body request.LedgerMemberRequest true "Member role"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/ledgers/{id}/members/{userId} [put]
+func (c *LedgerController) UpdateMemberRoleHandler(ctx *gin.Context) {
+	userID, ledgerID, ok := ledgerParams(ctx, "id", "Invalid ledger ID")
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid member ID", nil)
+		return
+	}
+
+	var req request.LedgerMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	if err := c.LedgerService.UpdateMemberRole(userID, ledgerID, uint(memberID), req); err != nil {
+		ledgerErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Member role updated",
+		Data:            nil,
+	})
+}
+
+// RemoveMemberHandler godoc
+// @Summary 	Remove ledger member
+// @Description Remove a member from a ledger. The owner can remove anyone else, other members can only leave the ledger themselves. Transactions they recorded stay in the ledger.
+// @Tags 		ledgers
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Ledger ID"
+// @Param 		userId path int true "Member user ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/ledgers/{id}/members/{userId} [delete]
+func (c *LedgerController) RemoveMemberHandler(ctx *gin.Context) {
+	userID, ledgerID, ok := ledgerParams(ctx, "id", "Invalid ledger ID")
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid member ID", nil)
+		return
+	}
+
+	if err := c.LedgerService.RemoveMember(userID, ledgerID, uint(memberID)); err != nil {
+		ledgerErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Member removed",
+		Data:            nil,
+	})
+}
+
+func ledgerParams(ctx *gin.Context, param, invalidIDMessage string) (uint, uint, bool) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(ctx.Param(param), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, invalidIDMessage, nil)
+		return 0, 0, false
+	}
+
+	return userID, uint(id), true
+}
+
+// ledgerErrorResponse juga dipakai handler kategori dan transaksi yang menerima ledger_id
+func ledgerErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrLedgerNotFound), errors.Is(err, service.ErrLedgerMemberNotFound), errors.Is(err, service.ErrLedgerInvitationNotFound):
+		utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, service.ErrLedgerReadOnly), errors.Is(err, service.ErrLedgerOwnerOnly), errors.Is(err, service.ErrPersonalLedger):
+		utility.ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, service.ErrLedgerMemberExists), errors.Is(err, service.ErrLedgerNotEmpty):
+		utility.ErrorResponse(ctx, http.StatusConflict, err.Error(), nil)
+	default:
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+	}
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type LoyaltyController struct {
+	LoyaltyService *service.LoyaltyService
+}
+
+func NewLoyaltyController(loyaltyService *service.LoyaltyService) *LoyaltyController {
+	return &LoyaltyController{LoyaltyService: loyaltyService}
+}
+
+// GetLoyaltyBalanceHandler godoc
+// @Summary 	Get loyalty points balance
+// @Description Get the logged in user's loyalty points balance and its redeem value
+// @Tags 		loyalty
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.LoyaltyBalanceResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/loyalty [get]
+func (c *LoyaltyController) GetLoyaltyBalanceHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	balance, err := c.LoyaltyService.GetBalance(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get loyalty balance", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get loyalty balance successful",
+		Data:            balance,
+	})
+}
+
+// GetLoyaltyLedgerHandler godoc
+// @Summary 	Get loyalty points history
+// @Description Get the logged in user's loyalty points ledger, newest first
+// @Tags 		loyalty
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		page 	query 	int 	false 	"Page number"
+// @Param 		limit 	query 	int 	false 	"Limit per page"
+// @Success 	200 {object} response.SuccessResponse{data=response.LoyaltyLedgerResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/loyalty/ledger [get]
+func (c *LoyaltyController) GetLoyaltyLedgerHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.LoyaltyLedgerFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid filter parameters: "+err.Error(), nil)
+		return
+	}
+
+	ledger, err := c.LoyaltyService.GetLedger(userID, filter)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get loyalty ledger successful",
+		Data:            ledger,
+	})
+}
+
+// AdjustLoyaltyPointsHandler godoc
+// @Summary 	Adjust loyalty points
+// @Description Manually add (positive points) or deduct (negative points) a user's loyalty points
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.LoyaltyAdjustRequest true "Adjustment data"
+// @Success 	201 {object} response.SuccessResponse{data=response.LoyaltyLedgerEntryResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/admin/loyalty/adjust [post]
+func (c *LoyaltyController) AdjustLoyaltyPointsHandler(ctx *gin.Context) {
+	var req request.LoyaltyAdjustRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	entry, err := c.LoyaltyService.Adjust(req)
+	if err != nil {
+		if !errors.Is(err, service.ErrInsufficientPoints) {
+			logrus.Errorf("Error adjusting loyalty points: %v", err)
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Loyalty points adjusted",
+		Data:            entry,
+	})
+}
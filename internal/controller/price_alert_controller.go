@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PriceAlertController struct {
+	PriceAlertService *service.PriceAlertService
+}
+
+func NewPriceAlertController(priceAlertService *service.PriceAlertService) *PriceAlertController {
+	return &PriceAlertController{PriceAlertService: priceAlertService}
+}
+
+// SubscribePriceAlertHandler godoc
+// @Summary 	Subscribe to a price-drop alert
+// @Description Get notified once when the product price drops to or below the target price
+// @Tags 		price-alerts
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Product ID"
+// @Param 		request body request.PriceAlertRequest true "Target price"
+// @Success 	200 {object} response.SuccessResponse{data=response.PriceAlertResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/product/{id}/price-alert [post]
+func (c *PriceAlertController) SubscribePriceAlertHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	productID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid product ID", nil)
+		return
+	}
+
+	var req request.PriceAlertRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	alert, err := c.PriceAlertService.Subscribe(userID, uint(productID), req.TargetPrice)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Price alert saved",
+		Data:            alert,
+	})
+}
+
+// UnsubscribePriceAlertHandler godoc
+// @Summary 	Remove a price-drop alert
+// @Description Remove the user's price-drop alert for a product
+// @Tags 		price-alerts
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Product ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/product/{id}/price-alert [delete]
+func (c *PriceAlertController) UnsubscribePriceAlertHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	productID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid product ID", nil)
+		return
+	}
+
+	if err := c.PriceAlertService.Unsubscribe(userID, uint(productID)); err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Price alert removed",
+		Data:            nil,
+	})
+}
+
+// GetPriceAlertsHandler godoc
+// @Summary 	Get price-drop alerts
+// @Description Get all price-drop alerts of the logged in user
+// @Tags 		price-alerts
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=[]response.PriceAlertResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/price-alerts [get]
+func (c *PriceAlertController) GetPriceAlertsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	alerts, err := c.PriceAlertService.GetUserAlerts(userID)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get price alerts successful",
+		Data:            alerts,
+	})
+}
@@ -14,11 +14,15 @@ import (
 )
 
 type ProductController struct {
-	ProductService *service.ProductService
+	ProductService     *service.ProductService
+	ProductViewService *service.ProductViewService
 }
 
-func NewProductController(productService *service.ProductService) *ProductController {
-	return &ProductController{ProductService: productService}
+func NewProductController(productService *service.ProductService, productViewService *service.ProductViewService) *ProductController {
+	return &ProductController{
+		ProductService:     productService,
+		ProductViewService: productViewService,
+	}
 }
 
 // GetProductsHandler godoc
@@ -31,6 +35,7 @@ func NewProductController(productService *service.ProductService) *ProductContro
 // @Param 		search query string false "Search term"
 // @Param 		min_price query number false "Minimum price"
 // @Param 		max_price query number false "Maximum price"
+// @Param 		sort query string false "Sort order (newest, price_asc, price_desc, popularity)"
 // @Param 		page query int false "Page number"
 // @Param 		limit query int false "Items per page"
 // @Success 	200 {object} response.SuccessResponse{data=response.ProductListResponse}
@@ -89,6 +94,21 @@ func (c *ProductController) GetProductByIDHandler(ctx *gin.Context) {
 		return
 	}
 
+	// catat view produk, view dari admin tidak dihitung
+	if !utility.IsAdminRequest(ctx) {
+		userID := utility.GetOptionalUserID(ctx)
+		var sessionID string
+		if userID == nil {
+			sessionID = utility.GetOrSetViewerSession(ctx)
+		}
+
+		go func(productID uint) {
+			if err := c.ProductViewService.RecordView(productID, userID, sessionID); err != nil {
+				logrus.Warnf("Failed to record view for product %d: %v", productID, err)
+			}
+		}(product.ID)
+	}
+
 	ctx.JSON(http.StatusOK, response.SuccessResponse{
 		ResponseStatus:  true,
 		ResponseMessage: "Get product successful",
@@ -96,6 +116,73 @@ func (c *ProductController) GetProductByIDHandler(ctx *gin.Context) {
 	})
 }
 
+// GetRecentlyViewedHandler godoc
+// @Summary 	Get recently viewed products
+// @Description Get products recently viewed by the logged in user, or by the anonymous session when not logged in
+// @Tags 		products
+// @Accept 		json
+// @Produce 	json
+// @Param 		limit query int false "Maximum number of products"
+// @Success 	200 {object} response.SuccessResponse{data=response.RecentlyViewedResponse}
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/product/recently-viewed [get]
+func (c *ProductController) GetRecentlyViewedHandler(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+
+	userID := utility.GetOptionalUserID(ctx)
+	var sessionID string
+	if userID == nil {
+		sessionID = utility.GetOrSetViewerSession(ctx)
+	}
+
+	products, err := c.ProductViewService.GetRecentlyViewed(userID, sessionID, limit)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get recently viewed products", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get recently viewed products successful",
+		Data:            products,
+	})
+}
+
+// GetProductViewAnalyticsHandler godoc
+// @Summary 	Get product view analytics
+// @Description Get view counts per product per day for the given date range (default last 30 days)
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		from query string false "Start date (YYYY-MM-DD)"
+// @Param 		to query string false "End date (YYYY-MM-DD)"
+// @Param 		product_id query int false "Product ID"
+// @Param 		limit query int false "Maximum number of products"
+// @Success 	200 {object} response.SuccessResponse{data=response.ProductViewAnalyticsResponse}
+// @Failure 	400 {object} response.ErrorResponse
+// @Failure 	401 {object} response.ErrorResponse
+// @Router 		/admin/products/views [get]
+func (c *ProductController) GetProductViewAnalyticsHandler(ctx *gin.Context) {
+	var filter request.ProductViewFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid filter parameters: "+err.Error(), nil)
+		return
+	}
+
+	analytics, err := c.ProductViewService.GetViewAnalytics(filter)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get product view analytics successful",
+		Data:            analytics,
+	})
+}
+
 // CreateProductHandler godoc
 // @Summary 	Create product
 // @Description Create a new product
@@ -222,6 +309,37 @@ func (c *ProductController) DeleteProductHandler(ctx *gin.Context) {
 	})
 }
 
+// GetPriceHistoryHandler godoc
+// @Summary 	Get product price history
+// @Description Get every price change of a product, newest first
+// @Tags 		products
+// @Accept 		json
+// @Produce 	json
+// @Param 		id path int true "Product ID"
+// @Success 	200 {object} response.SuccessResponse{data=[]response.PriceHistoryResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/product/{id}/price-history [get]
+func (c *ProductController) GetPriceHistoryHandler(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid product ID", nil)
+		return
+	}
+
+	history, err := c.ProductService.GetPriceHistory(uint(id))
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get price history successful",
+		Data:            history,
+	})
+}
+
 // GetProductCategoriesHandler godoc
 // @Summary 	Get all product categories
 // @Description Get a list of all unique product categories
@@ -0,0 +1,279 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecurringTransactionController struct {
+	RecurringTransactionService *service.RecurringTransactionService
+}
+
+func NewRecurringTransactionController(recurringTransactionService *service.RecurringTransactionService) *RecurringTransactionController {
+	return &RecurringTransactionController{RecurringTransactionService: recurringTransactionService}
+}
+
+// GetRecurringTransactionsHandler godoc
+// @Summary 	Get recurring transactions
+// @Description Get all recurring transaction templates for logged in user
+// @Tags 		recurring-transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=[]response.RecurringTransactionResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/recurring-transactions [get]
+func (c *RecurringTransactionController) GetRecurringTransactionsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	recurrings, err := c.RecurringTransactionService.GetRecurringTransactions(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get recurring transactions", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get recurring transactions successful",
+		Data:            recurrings,
+	})
+}
+
+// CreateRecurringTransactionHandler godoc
+// @Summary 	Create recurring transaction
+// @Description Create a daily/weekly/monthly/yearly transaction template. Occurrences up to today are posted immediately.
+// @Tags 		recurring-transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.CreateRecurringTransactionRequest true "Recurring transaction data"
+// @Success 	201 {object} response.SuccessResponse{data=response.RecurringTransactionResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/recurring-transactions [post]
+func (c *RecurringTransactionController) CreateRecurringTransactionHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.CreateRecurringTransactionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	recurring, err := c.RecurringTransactionService.CreateRecurringTransaction(userID, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Recurring transaction created",
+		Data:            recurring,
+	})
+}
+
+// UpdateRecurringTransactionHandler godoc
+// @Summary 	Update recurring transaction
+// @Description Update a recurring transaction template. Already posted transactions are not changed.
+// @Tags 		recurring-transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Recurring transaction ID"
+// @Param 		request body request.UpdateRecurringTransactionRequest true "Recurring transaction data"
+// @Success 	200 {object} response.SuccessResponse{data=response.RecurringTransactionResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/recurring-transactions/{id} [put]
+func (c *RecurringTransactionController) UpdateRecurringTransactionHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid recurring transaction ID", nil)
+		return
+	}
+
+	var req request.UpdateRecurringTransactionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	recurring, err := c.RecurringTransactionService.UpdateRecurringTransaction(userID, uint(id), req)
+	if err != nil {
+		if errors.Is(err, service.ErrRecurringTransactionNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Recurring transaction updated",
+		Data:            recurring,
+	})
+}
+
+// DeleteRecurringTransactionHandler godoc
+// @Summary 	Delete recurring transaction
+// @Description Stop and delete a recurring transaction template. Already posted transactions are kept.
+// @Tags 		recurring-transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Recurring transaction ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/recurring-transactions/{id} [delete]
+func (c *RecurringTransactionController) DeleteRecurringTransactionHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid recurring transaction ID", nil)
+		return
+	}
+
+	if err := c.RecurringTransactionService.DeleteRecurringTransaction(userID, uint(id)); err != nil {
+		if errors.Is(err, service.ErrRecurringTransactionNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Recurring transaction deleted",
+		Data:            nil,
+	})
+}
+
+// GetOccurrencesHandler godoc
+// @Summary 	Get recurring transaction occurrences
+// @Description Get upcoming occurrences (including edits and skips) and the most recently processed ones
+// @Tags 		recurring-transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id 			path 	int 	true 	"Recurring transaction ID"
+// @Param 		upcoming 	query 	int 	false 	"Number of upcoming occurrences (max 50)"
+// @Success 	200 {object} response.SuccessResponse{data=response.RecurringOccurrenceListResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/recurring-transactions/{id}/occurrences [get]
+func (c *RecurringTransactionController) GetOccurrencesHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid recurring transaction ID", nil)
+		return
+	}
+
+	var filter request.OccurrenceFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid filter parameters: "+err.Error(), nil)
+		return
+	}
+
+	occurrences, err := c.RecurringTransactionService.GetOccurrences(userID, uint(id), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrRecurringTransactionNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to get occurrences", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get occurrences successful",
+		Data:            occurrences,
+	})
+}
+
+// UpdateOccurrenceHandler godoc
+// @Summary 	Edit or skip an occurrence
+// @Description Skip a single upcoming occurrence, or override its amount/description before it is posted
+// @Tags 		recurring-transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id 		path 	int 	true 	"Recurring transaction ID"
+// @Param 		date 	path 	string 	true 	"Occurrence date (YYYY-MM-DD)"
+// @Param 		request body request.UpdateOccurrenceRequest true "Occurrence data"
+// @Success 	200 {object} response.SuccessResponse{data=response.RecurringOccurrenceResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/recurring-transactions/{id}/occurrences/{date} [put]
+func (c *RecurringTransactionController) UpdateOccurrenceHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid recurring transaction ID", nil)
+		return
+	}
+
+	var req request.UpdateOccurrenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	occurrence, err := c.RecurringTransactionService.UpdateOccurrence(userID, uint(id), ctx.Param("date"), req)
+	if err != nil {
+		if errors.Is(err, service.ErrRecurringTransactionNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Occurrence updated",
+		Data:            occurrence,
+	})
+}
@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/report"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ReportController struct {
+	ReportService *service.ReportService
+}
+
+func NewReportController(reportService *service.ReportService) *ReportController {
+	return &ReportController{ReportService: reportService}
+}
+
+// GetMonthlyReportHandler godoc
+// @Summary 	Get monthly report
+// @Description Get the monthly statement: opening/closing balance, income and expense by category, top expenses, budget status and comparison with the previous month, in the user's base currency
+// @Tags 		reports
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		month query string false "Month (YYYY-MM), default current month"
+// @Success 	200 {object} response.SuccessResponse{data=response.MonthlyReportResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/reports/monthly [get]
+func (c *ReportController) GetMonthlyReportHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.MonthlyReportFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	monthlyReport, err := c.ReportService.GetMonthlyReport(userID, filter.Month)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get monthly report successful",
+		Data:            monthlyReport,
+	})
+}
+
+// ExportMonthlyReportHandler godoc
+// @Summary 	Export monthly report
+// @Description Download the monthly statement as a multi-sheet XLSX workbook with charts or as a PDF
+// @Tags 		reports
+// @Accept 		json
+// @Produce 	application/octet-stream
+// @Security 	BearerAuth
+// @Param 		month 	query string false "Month (YYYY-MM), default current month"
+// @Param 		format 	query string false "File format (xlsx/pdf), default xlsx"
+// @Success 	200 {file} file "Report file download"
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/reports/monthly/export [get]
+func (c *ReportController) ExportMonthlyReportHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.MonthlyReportFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	buffer, filename, err := c.ReportService.ExportMonthlyReport(userID, filter)
+	if err != nil {
+		logrus.Errorf("Error exporting monthly report: %v", err)
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	contentType := report.ContentType(filter.Format)
+	ctx.Header("Content-Description", "File Transfer")
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	ctx.Header("Content-Transfer-Encoding", "binary")
+	ctx.Header("Expires", "0")
+	ctx.Header("Cache-Control", "must-revalidate")
+	ctx.Header("Pragma", "public")
+
+	ctx.Data(http.StatusOK, contentType, buffer.Bytes())
+}
+
+// GetReportScheduleHandler godoc
+// @Summary 	Get report schedule
+// @Description Get the monthly report delivery schedule of logged in user
+// @Tags 		reports
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.ReportScheduleResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/reports/schedule [get]
+func (c *ReportController) GetReportScheduleHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	schedule, err := c.ReportService.GetReportSchedule(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrReportScheduleNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get report schedule successful",
+		Data:            schedule,
+	})
+}
+
+// SaveReportScheduleHandler godoc
+// @Summary 	Save report schedule
+// @Description Deliver the previous month's report through the notifier every month starting on day_of_month (1-28) in the user's timezone. Replaces the existing schedule.
+// @Tags 		reports
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.ReportScheduleRequest true "Report schedule data"
+// @Success 	200 {object} response.SuccessResponse{data=response.ReportScheduleResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/reports/schedule [put]
+func (c *ReportController) SaveReportScheduleHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.ReportScheduleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	schedule, err := c.ReportService.SaveReportSchedule(userID, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Report schedule saved",
+		Data:            schedule,
+	})
+}
+
+// DeleteReportScheduleHandler godoc
+// @Summary 	Delete report schedule
+// @Description Stop delivering monthly reports
+// @Tags 		reports
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/reports/schedule [delete]
+func (c *ReportController) DeleteReportScheduleHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	if err := c.ReportService.DeleteReportSchedule(userID); err != nil {
+		if errors.Is(err, service.ErrReportScheduleNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Report schedule deleted",
+		Data:            nil,
+	})
+}
@@ -0,0 +1,361 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SavingsGoalController struct {
+	SavingsGoalService *service.SavingsGoalService
+}
+
+func NewSavingsGoalController(savingsGoalService *service.SavingsGoalService) *SavingsGoalController {
+	return &SavingsGoalController{SavingsGoalService: savingsGoalService}
+}
+
+// GetSavingsGoalsHandler godoc
+// @Summary 	Get savings goals
+// @Description Get savings goals with saved amount, contribution rate and projected completion date
+// @Tags 		savings-goals
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=[]response.SavingsGoalResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/savings-goals [get]
+func (c *SavingsGoalController) GetSavingsGoalsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	goals, err := c.SavingsGoalService.GetSavingsGoals(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get savings goals", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get savings goals successful",
+		Data:            goals,
+	})
+}
+
+// GetSavingsGoalHandler godoc
+// @Summary 	Get savings goal
+// @Description Get a savings goal with its progress and projection
+// @Tags 		savings-goals
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Savings goal ID"
+// @Success 	200 {object} response.SuccessResponse{data=response.SavingsGoalResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/savings-goals/{id} [get]
+func (c *SavingsGoalController) GetSavingsGoalHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid savings goal ID", nil)
+		return
+	}
+
+	goal, err := c.SavingsGoalService.GetSavingsGoal(userID, uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrSavingsGoalNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to get savings goal", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get savings goal successful",
+		Data:            goal,
+	})
+}
+
+// CreateSavingsGoalHandler godoc
+// @Summary 	Create savings goal
+// @Description Create a savings goal, optionally linked to an account or a category
+// @Tags 		savings-goals
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.SavingsGoalRequest true "Savings goal data"
+// @Success 	201 {object} response.SuccessResponse{data=response.SavingsGoalResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/savings-goals [post]
+func (c *SavingsGoalController) CreateSavingsGoalHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.SavingsGoalRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	goal, err := c.SavingsGoalService.CreateSavingsGoal(userID, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Savings goal created",
+		Data:            goal,
+	})
+}
+
+// UpdateSavingsGoalHandler godoc
+// @Summary 	Update savings goal
+// @Description Update target, dates or linked account/category of a savings goal
+// @Tags 		savings-goals
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Savings goal ID"
+// @Param 		request body request.SavingsGoalRequest true "Savings goal data"
+// @Success 	200 {object} response.SuccessResponse{data=response.SavingsGoalResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/savings-goals/{id} [put]
+func (c *SavingsGoalController) UpdateSavingsGoalHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid savings goal ID", nil)
+		return
+	}
+
+	var req request.SavingsGoalRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	goal, err := c.SavingsGoalService.UpdateSavingsGoal(userID, uint(id), req)
+	if err != nil {
+		if errors.Is(err, service.ErrSavingsGoalNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Savings goal updated",
+		Data:            goal,
+	})
+}
+
+// DeleteSavingsGoalHandler godoc
+// @Summary 	Delete savings goal
+// @Description Delete a savings goal
+// @Tags 		savings-goals
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Savings goal ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/savings-goals/{id} [delete]
+func (c *SavingsGoalController) DeleteSavingsGoalHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid savings goal ID", nil)
+		return
+	}
+
+	if err := c.SavingsGoalService.DeleteSavingsGoal(userID, uint(id)); err != nil {
+		if errors.Is(err, service.ErrSavingsGoalNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Savings goal deleted",
+		Data:            nil,
+	})
+}
+
+// GetContributionsHandler godoc
+// @Summary 	Get savings goal contributions
+// @Description Get manual contributions and withdrawals of a savings goal
+// @Tags 		savings-goals
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Savings goal ID"
+// @Success 	200 {object} response.SuccessResponse{data=[]response.SavingsContributionResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/savings-goals/{id}/contributions [get]
+func (c *SavingsGoalController) GetContributionsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid savings goal ID", nil)
+		return
+	}
+
+	contributions, err := c.SavingsGoalService.GetContributions(userID, uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrSavingsGoalNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to get contributions", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get contributions successful",
+		Data:            contributions,
+	})
+}
+
+// AddContributionHandler godoc
+// @Summary 	Add savings goal contribution
+// @Description Record a contribution to a savings goal, a negative amount records a withdrawal
+// @Tags 		savings-goals
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Savings goal ID"
+// @Param 		request body request.SavingsContributionRequest true "Contribution data"
+// @Success 	201 {object} response.SuccessResponse{data=response.SavingsGoalResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/savings-goals/{id}/contributions [post]
+func (c *SavingsGoalController) AddContributionHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid savings goal ID", nil)
+		return
+	}
+
+	var req request.SavingsContributionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	goal, err := c.SavingsGoalService.AddContribution(userID, uint(id), req)
+	if err != nil {
+		if errors.Is(err, service.ErrSavingsGoalNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Contribution added",
+		Data:            goal,
+	})
+}
+
+// DeleteContributionHandler godoc
+// @Summary 	Delete savings goal contribution
+// @Description Delete a manual contribution from a savings goal
+// @Tags 		savings-goals
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Savings goal ID"
+// @Param 		contributionId path int true "Contribution ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/savings-goals/{id}/contributions/{contributionId} [delete]
+func (c *SavingsGoalController) DeleteContributionHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid savings goal ID", nil)
+		return
+	}
+
+	contributionID, err := strconv.ParseUint(ctx.Param("contributionId"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid contribution ID", nil)
+		return
+	}
+
+	if err := c.SavingsGoalService.DeleteContribution(userID, uint(id), uint(contributionID)); err != nil {
+		if errors.Is(err, service.ErrSavingsGoalNotFound) || errors.Is(err, service.ErrSavingsContributionNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Contribution deleted",
+		Data:            nil,
+	})
+}
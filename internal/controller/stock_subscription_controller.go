@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockSubscriptionController struct {
+	StockNotificationService *service.StockNotificationService
+}
+
+func NewStockSubscriptionController(stockNotificationService *service.StockNotificationService) *StockSubscriptionController {
+	return &StockSubscriptionController{StockNotificationService: stockNotificationService}
+}
+
+// SubscribeStockHandler godoc
+// @Summary 	Subscribe to back-in-stock notification
+// @Description Get notified when an out-of-stock product is available again
+// @Tags 		stock-subscriptions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Product ID"
+// @Success 	200 {object} response.SuccessResponse{data=response.StockSubscriptionResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/product/{id}/stock-subscription [post]
+func (c *StockSubscriptionController) SubscribeStockHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	productID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid product ID", nil)
+		return
+	}
+
+	subscription, err := c.StockNotificationService.Subscribe(userID, uint(productID))
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Stock subscription saved",
+		Data:            subscription,
+	})
+}
+
+// UnsubscribeStockHandler godoc
+// @Summary 	Remove back-in-stock notification
+// @Description Remove the user's back-in-stock subscription for a product
+// @Tags 		stock-subscriptions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Product ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/product/{id}/stock-subscription [delete]
+func (c *StockSubscriptionController) UnsubscribeStockHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	productID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid product ID", nil)
+		return
+	}
+
+	if err := c.StockNotificationService.Unsubscribe(userID, uint(productID)); err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Stock subscription removed",
+		Data:            nil,
+	})
+}
+
+// GetStockSubscriptionsHandler godoc
+// @Summary 	Get back-in-stock subscriptions
+// @Description Get all back-in-stock subscriptions of the logged in user
+// @Tags 		stock-subscriptions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=[]response.StockSubscriptionResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/stock-subscriptions [get]
+func (c *StockSubscriptionController) GetStockSubscriptionsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	subscriptions, err := c.StockNotificationService.GetUserSubscriptions(userID)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get stock subscriptions successful",
+		Data:            subscriptions,
+	})
+}
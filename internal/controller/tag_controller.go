@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagController struct {
+	TagService *service.TagService
+}
+
+func NewTagController(tagService *service.TagService) *TagController {
+	return &TagController{TagService: tagService}
+}
+
+// GetTagsHandler godoc
+// @Summary 	Get tags
+// @Description Get all transaction tags of logged in user with usage count
+// @Tags 		tags
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=[]response.TagResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/tags [get]
+func (c *TagController) GetTagsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	tags, err := c.TagService.GetTags(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get tags", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get tags successful",
+		Data:            tags,
+	})
+}
+
+// GetTagSummaryHandler godoc
+// @Summary 	Get tag summary
+// @Description Get income, expense and transaction count per tag. A transaction with several tags is counted under each tag.
+// @Tags 		tags
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		start_date 	query 	string 	false 	"Start date (YYYY-MM-DD)"
+// @Param 		end_date 	query 	string 	false 	"End date (YYYY-MM-DD)"
+// @Success 	200 {object} response.SuccessResponse{data=[]response.TagSummary}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/tags/summary [get]
+func (c *TagController) GetTagSummaryHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.TagSummaryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid filter parameters: "+err.Error(), nil)
+		return
+	}
+
+	summary, err := c.TagService.GetTagSummary(userID, filter)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get tag summary successful",
+		Data:            summary,
+	})
+}
+
+// RenameTagHandler godoc
+// @Summary 	Rename tag
+// @Description Rename a tag on every transaction that uses it
+// @Tags 		tags
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Tag ID"
+// @Param 		request body request.TagRequest true "Tag data"
+// @Success 	200 {object} response.SuccessResponse{data=response.TagResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/tags/{id} [put]
+func (c *TagController) RenameTagHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid tag ID", nil)
+		return
+	}
+
+	var req request.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	tag, err := c.TagService.RenameTag(userID, uint(id), req)
+	if err != nil {
+		if errors.Is(err, service.ErrTagNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Tag updated",
+		Data:            tag,
+	})
+}
+
+// DeleteTagHandler godoc
+// @Summary 	Delete tag
+// @Description Remove a tag from all transactions, the transactions themselves are kept
+// @Tags 		tags
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Tag ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/tags/{id} [delete]
+func (c *TagController) DeleteTagHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid tag ID", nil)
+		return
+	}
+
+	if err := c.TagService.DeleteTag(userID, uint(id)); err != nil {
+		if errors.Is(err, service.ErrTagNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Tag deleted",
+		Data:            nil,
+	})
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"go-electroshop/internal/payload/request"
 	"go-electroshop/internal/payload/response"
@@ -25,10 +26,17 @@ type TransactionController struct {
 // @Accept 		json
 // @Produce 	json
 // @Security 	BearerAuth
+// @Param 		ledger_id 	query 	int 	false 	"Ledger ID, lists transactions of all members. Defaults to transactions recorded by the logged in user in any ledger"
 // @Param 		start_date 	query 	string 	false 	"Start date (YYYY-MM-DD)"
 // @Param 		end_date 	query 	string 	false 	"End date (YYYY-MM-DD)"
 // @Param 		category_id	query 	int 	false 	"Category ID"
+// @Param 		account_id	query 	int 	false 	"Account ID"
 // @Param 		type 		query 	string 	false 	"Transaction type (income/expense)"
+// @Param 		tags 		query 	string 	false 	"Comma separated tags, transaction must have all of them"
+// @Param 		q 			query 	string 	false 	"Search words (or word prefixes) in description and category name"
+// @Param 		min_amount 	query 	number 	false 	"Minimum amount in transaction currency"
+// @Param 		max_amount 	query 	number 	false 	"Maximum amount in transaction currency"
+// @Param 		sort 		query 	string 	false 	"Sort order (date_desc/date_asc/amount_desc/amount_asc/relevance), default date_desc"
 // @Param 		page 		query 	int 	false 	"Page number"
 // @Param 		limit 		query 	int 	false 	"Limit per page"
 // @Success 	200 {object} response.SuccessResponse{data=response.TransactionListResponse}
@@ -53,6 +61,10 @@ func (c *TransactionController) GetTransactionHandler(ctx *gin.Context) {
 
 	transactions, err := c.TransactionService.GetTransactionByUser(userID, filter)
 	if err != nil {
+		if errors.Is(err, service.ErrLedgerNotFound) {
+			ledgerErrorResponse(ctx, err)
+			return
+		}
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
 	}
@@ -91,7 +103,7 @@ func (c *TransactionController) CreateTransactionHandler(ctx *gin.Context) {
 
 	transaction, err := c.TransactionService.CreateTransaction(userID, req)
 	if err != nil {
-		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		ledgerErrorResponse(ctx, err)
 		return
 	}
 
@@ -184,22 +196,73 @@ func (c *TransactionController) DeleteTransactionHandler(ctx *gin.Context) {
 	})
 }
 
-// ExportTransactionsExcelHandler godoc
-// @Summary 	Export transactions to Excel
-// @Description Export transactions to Excel file
+// BulkTransactionsHandler godoc
+// @Summary 	Bulk update transactions
+// @Description Run one action on many transactions selected by ids or by filter: recategorize (category_id), delete (moves to trash), change_type (type), add_tags / remove_tags (tags) or shift_date (days). Everything runs in one database transaction; nothing changes if an id is not found. Split transactions are skipped by recategorize.
+// @Tags 		transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.BulkTransactionRequest true "Bulk action"
+// @Success 	200 {object} response.SuccessResponse{data=response.BulkTransactionResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/transaction/bulk [post]
+func (c *TransactionController) BulkTransactionsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.BulkTransactionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	result, err := c.TransactionService.BulkUpdateTransactions(userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrBulkTransactionsNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		ledgerErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Bulk update successful",
+		Data:            result,
+	})
+}
+
+// ExportTransactionsHandler godoc
+// @Summary 	Export transactions
+// @Description Stream all transactions matching the filter as CSV, XLSX or JSON. Split transactions are written as one row per category in CSV and XLSX.
 // @Tags 		transactions
 // @Accept 		json
 // @Produce 	application/octet-stream
 // @Security 	BearerAuth
+// @Param 		format 		query 	string 	false 	"File format (csv/xlsx/json), default xlsx"
+// @Param 		ledger_id 	query 	int 	false 	"Ledger ID, exports transactions of all members. Defaults to transactions recorded by the logged in user in any ledger"
 // @Param 		start_date 	query 	string 	false 	"Start date (YYYY-MM-DD)"
 // @Param 		end_date 	query 	string 	false 	"End date (YYYY-MM-DD)"
 // @Param 		category_id	query 	int 	false 	"Category ID"
+// @Param 		account_id	query 	int 	false 	"Account ID"
 // @Param 		type 		query 	string 	false 	"Transaction type (income/expense)"
-// @Success 	200 {file} file "Excel file download"
+// @Param 		tags 		query 	string 	false 	"Comma separated tags, transaction must have all of them"
+// @Param 		q 			query 	string 	false 	"Search words (or word prefixes) in description and category name"
+// @Param 		min_amount 	query 	number 	false 	"Minimum amount in transaction currency"
+// @Param 		max_amount 	query 	number 	false 	"Maximum amount in transaction currency"
+// @Success 	200 {file} file "Export file download"
 // @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
 // @Router 		/transaction/export [get]
-func (c *TransactionController) ExportTransactionsExcelHandler(ctx *gin.Context) {
+func (c *TransactionController) ExportTransactionsHandler(ctx *gin.Context) {
 	userID, err := utility.GetUserIDFromContext(ctx)
 	if err != nil {
 		logrus.Errorf("Error getting user ID: %v", err)
@@ -207,29 +270,151 @@ func (c *TransactionController) ExportTransactionsExcelHandler(ctx *gin.Context)
 		return
 	}
 
-	var filter request.TransactionFilter
+	var filter request.TransactionExportFilter
 	if err := ctx.ShouldBindQuery(&filter); err != nil {
 		logrus.Errorf("Error binding query params: %v", err)
 		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid filter parameters: "+err.Error(), nil)
 		return
 	}
 
-	buffer, err := c.TransactionService.ExportTransactionsExcel(userID, filter)
-	if err != nil {
+	// header dikirim bersama data pertama, file ditulis langsung ke response tanpa buffer
+	downloadHeaders := map[string]string{
+		"Content-Description":       "File Transfer",
+		"Content-Type":              utility.TransactionExportContentType(filter.Format),
+		"Content-Disposition":       fmt.Sprintf("attachment; filename=transactions_%s.%s", time.Now().Format("20060102"), filter.Format),
+		"Content-Transfer-Encoding": "binary",
+		"Expires":                   "0",
+		"Cache-Control":             "must-revalidate",
+		"Pragma":                    "public",
+	}
+	for key, value := range downloadHeaders {
+		ctx.Header(key, value)
+	}
+	ctx.Status(http.StatusOK)
+
+	if err := c.TransactionService.ExportTransactions(ctx.Request.Context(), userID, filter, ctx.Writer); err != nil {
 		logrus.Errorf("Error exporting transactions: %v", err)
-		utility.InternalServerErrorResponse(ctx, "Failed while export Excel", err)
+
+		// sebagian file sudah terkirim, response tidak bisa diganti dengan pesan error
+		if ctx.Writer.Written() {
+			ctx.Abort()
+			return
+		}
+
+		for key := range downloadHeaders {
+			ctx.Writer.Header().Del(key)
+		}
+		if errors.Is(err, service.ErrLedgerNotFound) {
+			ledgerErrorResponse(ctx, err)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed while export transactions", err)
+	}
+}
+
+// batas ukuran file statement yang bisa di-import
+const maxImportFileSize = 5 << 20
+
+// ImportTransactionsHandler godoc
+// @Summary 	Preview transaction import
+// @Description Upload a CSV, OFX or QIF bank statement and preview the parsed rows with duplicate detection. For CSV without column mapping the headers and sample rows are returned instead.
+// @Tags 		transactions
+// @Accept 		multipart/form-data
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		file 				formData 	file 	true 	"Statement file (max 5MB)"
+// @Param 		format 				formData 	string 	true 	"File format (csv/ofx/qif)"
+// @Param 		date_column 		formData 	string 	false 	"CSV date column"
+// @Param 		amount_column 		formData 	string 	false 	"CSV amount column"
+// @Param 		description_column 	formData 	string 	false 	"CSV description column"
+// @Param 		type_column 		formData 	string 	false 	"CSV type column (credit/debit)"
+// @Param 		date_format 		formData 	string 	false 	"CSV date format, e.g. DD/MM/YYYY"
+// @Param 		decimal_separator 	formData 	string 	false 	"CSV decimal separator (. or ,)"
+// @Param 		delimiter 			formData 	string 	false 	"CSV delimiter (, or ;)"
+// @Success 	200 {object} response.SuccessResponse{data=response.ImportPreviewResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/transaction/import [post]
+func (c *TransactionController) ImportTransactionsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		logrus.Errorf("Error getting user ID: %v", err)
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.ImportPreviewRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "File is required", nil)
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "File size must not exceed 5MB", nil)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logrus.Errorf("Error opening import file: %v", err)
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Failed to read file", nil)
+		return
+	}
+	defer file.Close()
+
+	preview, err := c.TransactionService.PreviewImport(userID, file, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Import preview successful",
+		Data:            preview,
+	})
+}
+
+// CommitImportHandler godoc
+// @Summary 	Commit transaction import
+// @Description Save the selected preview rows in one database transaction. Duplicates are skipped unless allow_duplicate is set, and a result is reported per row.
+// @Tags 		transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.ImportCommitRequest true "Rows to import"
+// @Success 	200 {object} response.SuccessResponse{data=response.ImportCommitResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/transaction/import/commit [post]
+func (c *TransactionController) CommitImportHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		logrus.Errorf("Error getting user ID: %v", err)
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
 	}
 
-	// Set response headers
-	filename := fmt.Sprintf("transactions_%s.xlsx", time.Now().Format("20060102"))
-	ctx.Header("Content-Description", "File Transfer")
-	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	ctx.Header("Content-Transfer-Encoding", "binary")
-	ctx.Header("Expires", "0")
-	ctx.Header("Cache-Control", "must-revalidate")
-	ctx.Header("Pragma", "public")
+	var req request.ImportCommitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	result, err := c.TransactionService.CommitImport(userID, req)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
 
-	ctx.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buffer.Bytes())
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Import finished",
+		Data:            result,
+	})
 }
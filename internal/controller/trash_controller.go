@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashController struct {
+	TrashService *service.TrashService
+}
+
+func NewTrashController(trashService *service.TrashService) *TrashController {
+	return &TrashController{TrashService: trashService}
+}
+
+// GetTrashedTransactionsHandler godoc
+// @Summary 	Get trashed transactions
+// @Description Get deleted transactions of logged in user, newest first. Items are purged permanently at purge_at.
+// @Tags 		transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		page 	query int false "Page number, default 1"
+// @Param 		limit 	query int false "Items per page, default 10"
+// @Success 	200 {object} response.SuccessResponse{data=response.TrashedTransactionListResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/transaction/trash [get]
+func (c *TrashController) GetTrashedTransactionsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.TrashFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	transactions, err := c.TrashService.GetTrashedTransactions(userID, filter)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get trashed transactions successful",
+		Data:            transactions,
+	})
+}
+
+// RestoreTransactionHandler godoc
+// @Summary 	Restore transaction
+// @Description Restore a deleted transaction. Fails if its category is still in trash or its account has been deleted.
+// @Tags 		transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Transaction ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Failure 	409 {object} response.SuccessResponse
+// @Router 		/transaction/{id}/restore [post]
+func (c *TrashController) RestoreTransactionHandler(ctx *gin.Context) {
+	userID, transactionID, ok := trashParams(ctx, "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	if err := c.TrashService.RestoreTransaction(userID, transactionID); err != nil {
+		trashErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Transaction restored",
+		Data:            nil,
+	})
+}
+
+// PurgeTransactionHandler godoc
+// @Summary 	Purge transaction
+// @Description Permanently delete a transaction in trash together with its splits, tags and attachments
+// @Tags 		transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Transaction ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/transaction/{id}/purge [delete]
+func (c *TrashController) PurgeTransactionHandler(ctx *gin.Context) {
+	userID, transactionID, ok := trashParams(ctx, "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	if err := c.TrashService.PurgeTransaction(ctx.Request.Context(), userID, transactionID); err != nil {
+		trashErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Transaction purged",
+		Data:            nil,
+	})
+}
+
+// GetTrashedCategoriesHandler godoc
+// @Summary 	Get trashed categories
+// @Description Get deleted categories of logged in user, newest first
+// @Tags 		categories
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=[]response.TrashedCategoryResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/category/trash [get]
+func (c *TrashController) GetTrashedCategoriesHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	categories, err := c.TrashService.GetTrashedCategories(userID)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get trashed categories successful",
+		Data:            categories,
+	})
+}
+
+// RestoreCategoryHandler godoc
+// @Summary 	Restore category
+// @Description Restore a deleted category
+// @Tags 		categories
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Category ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/category/{id}/restore [post]
+func (c *TrashController) RestoreCategoryHandler(ctx *gin.Context) {
+	userID, categoryID, ok := trashParams(ctx, "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	if err := c.TrashService.RestoreCategory(userID, categoryID); err != nil {
+		trashErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Category restored",
+		Data:            nil,
+	})
+}
+
+// PurgeCategoryHandler godoc
+// @Summary 	Purge category
+// @Description Permanently delete a category in trash together with its budget and category rules. Fails while transactions (including trashed ones), recurring transactions or savings goals still use it.
+// @Tags 		categories
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Category ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Failure 	409 {object} response.SuccessResponse
+// @Router 		/category/{id}/purge [delete]
+func (c *TrashController) PurgeCategoryHandler(ctx *gin.Context) {
+	userID, categoryID, ok := trashParams(ctx, "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	if err := c.TrashService.PurgeCategory(userID, categoryID); err != nil {
+		trashErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Category purged",
+		Data:            nil,
+	})
+}
+
+func trashParams(ctx *gin.Context, invalidIDMessage string) (uint, uint, bool) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, invalidIDMessage, nil)
+		return 0, 0, false
+	}
+
+	return userID, uint(id), true
+}
+
+func trashErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTrashedTransactionNotFound), errors.Is(err, service.ErrTrashedCategoryNotFound):
+		utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, service.ErrCategoryInTrash), errors.Is(err, service.ErrTransactionAccountDeleted), errors.Is(err, service.ErrCategoryInUse):
+		utility.ErrorResponse(ctx, http.StatusConflict, err.Error(), nil)
+	default:
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+	}
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetUserSettingsHandler godoc
+// @Summary 	Get user settings
+// @Description Get timezone and locale used for transaction dates, month boundaries and chart labels
+// @Tags 		user
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.UserSettingsResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/user/settings [get]
+func (c *UserController) GetUserSettingsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	settings, err := c.UserService.GetUserSettings(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to get user settings", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get user settings successful",
+		Data:            settings,
+	})
+}
+
+// UpdateUserSettingsHandler godoc
+// @Summary 	Update user settings
+// @Description Update timezone (IANA name, e.g. Asia/Jakarta) and locale (en/id)
+// @Tags 		user
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.UserSettingsRequest true "User settings"
+// @Success 	200 {object} response.SuccessResponse{data=response.UserSettingsResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/user/settings [put]
+func (c *UserController) UpdateUserSettingsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.UserSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.ValidationErrorResponse(ctx, err)
+		return
+	}
+
+	settings, err := c.UserService.UpdateUserSettings(userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "User settings updated",
+		Data:            settings,
+	})
+}
@@ -0,0 +1,104 @@
+package exchangerate
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileProvider serves rates from a CSV file with the columns date,from,to,rate,
+// e.g. "2025-01-02,USD,IDR,16250". It stands in for a real rate API and is
+// loaded once when created.
+type FileProvider struct {
+	rates map[string][]datedRate
+}
+
+type datedRate struct {
+	date time.Time
+	rate float64
+}
+
+func NewFileProvider(path string) (*FileProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadFileProvider(file)
+}
+
+// ReadFileProvider parses rates in the FileProvider CSV format from r
+func ReadFileProvider(r io.Reader) (*FileProvider, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	provider := &FileProvider{rates: map[string][]datedRate{}}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(record[0], "date") {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, record[0])
+		}
+		rate, err := strconv.ParseFloat(record[3], 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("line %d: invalid rate %q", line, record[3])
+		}
+
+		key := pairKey(record[1], record[2])
+		provider.rates[key] = append(provider.rates[key], datedRate{date: date, rate: rate})
+	}
+
+	for _, rates := range provider.rates {
+		sort.Slice(rates, func(i, j int) bool { return rates[i].date.Before(rates[j].date) })
+	}
+
+	return provider, nil
+}
+
+func (p *FileProvider) Name() string {
+	return "file"
+}
+
+// Rate returns the latest rate on or before date, using the inverse pair when
+// only the opposite direction is listed
+func (p *FileProvider) Rate(ctx context.Context, from, to string, date time.Time) (float64, error) {
+	if rate, ok := latestRate(p.rates[pairKey(from, to)], date); ok {
+		return rate, nil
+	}
+	if rate, ok := latestRate(p.rates[pairKey(to, from)], date); ok {
+		return 1 / rate, nil
+	}
+	return 0, ErrRateNotFound
+}
+
+func latestRate(rates []datedRate, date time.Time) (float64, bool) {
+	// rates are sorted by date, find the first one after date
+	i := sort.Search(len(rates), func(i int) bool { return rates[i].date.After(date) })
+	if i == 0 {
+		return 0, false
+	}
+	return rates[i-1].rate, true
+}
+
+func pairKey(from, to string) string {
+	return strings.ToUpper(strings.TrimSpace(from)) + "/" + strings.ToUpper(strings.TrimSpace(to))
+}
@@ -0,0 +1,37 @@
+package exchangerate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrRateNotFound is returned by Rate when the provider has no rate for the pair
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// Provider looks up the rate converting one unit of from into to, effective on
+// date. Implementations must be safe for concurrent use.
+type Provider interface {
+	Name() string
+	Rate(ctx context.Context, from, to string, date time.Time) (float64, error)
+}
+
+// NewFromEnv builds the provider configured by EXCHANGE_RATE_PROVIDER ("none" or
+// "file"). The file provider reads EXCHANGE_RATE_FILE (default ./exchange_rates.csv).
+// A nil provider means only rates entered by users are available.
+func NewFromEnv() (Provider, error) {
+	switch name := os.Getenv("EXCHANGE_RATE_PROVIDER"); name {
+	case "", "none":
+		return nil, nil
+	case "file":
+		path := os.Getenv("EXCHANGE_RATE_FILE")
+		if path == "" {
+			path = "./exchange_rates.csv"
+		}
+		return NewFileProvider(path)
+	default:
+		return nil, fmt.Errorf("unknown exchange rate provider: %s", name)
+	}
+}
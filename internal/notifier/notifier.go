@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Message adalah notifikasi untuk satu user
+type Message struct {
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Attachment adalah file yang dikirim bersama pesan, misalnya laporan. Channel yang tidak
+// bisa mengirim file boleh mengabaikannya.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Notifier mengirim pesan ke user. Implementasinya bisa berupa email, push notification,
+// dan lain-lain.
+type Notifier interface {
+	Notify(ctx context.Context, userID uint, msg Message) error
+}
+
+// LogNotifier menulis notifikasi ke log aplikasi. Dipakai sebagai notifier default
+// sampai ada channel pengiriman yang sebenarnya.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, userID uint, msg Message) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"user_id": userID,
+		"subject": msg.Subject,
+	})
+	for _, attachment := range msg.Attachments {
+		logger = logger.WithField("attachment", fmt.Sprintf("%s (%d bytes)", attachment.Filename, len(attachment.Data)))
+	}
+	logger.Info(msg.Body)
+	return nil
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	AccountCash       = "cash"
+	AccountBank       = "bank"
+	AccountEWallet    = "ewallet"
+	AccountCreditCard = "credit_card"
+)
+
+// Account adalah dompet/rekening milik user. Saldo dihitung dari OpeningBalance
+// ditambah transaksi dan transfer yang terkait, tidak disimpan di tabel.
+type Account struct {
+	gorm.Model
+	UserID         uint    `gorm:"not null;index"`
+	Name           string  `gorm:"type:varchar(100);not null"`
+	Type           string  `gorm:"size:20;not null"`
+	OpeningBalance float64 `gorm:"type:decimal(15,2);not null;default:0"`
+	Currency       string  `gorm:"type:varchar(3);not null;default:'IDR'"` // kode ISO 4217, tidak bisa diubah
+}
+
+func (a *Account) BeforeSave(tx *gorm.DB) error {
+	validTypes := map[string]bool{
+		AccountCash:       true,
+		AccountBank:       true,
+		AccountEWallet:    true,
+		AccountCreditCard: true,
+	}
+	if !validTypes[a.Type] {
+		return fmt.Errorf("invalid account type: %s", a.Type)
+	}
+
+	if a.Name == "" {
+		return fmt.Errorf("account name cannot be empty")
+	}
+
+	return nil
+}
+
+// Transfer memindahkan uang antar dua account milik user yang sama dengan mata uang yang sama.
+// Transfer tidak dihitung sebagai income maupun expense.
+type Transfer struct {
+	gorm.Model
+	UserID        uint      `gorm:"not null;index"`
+	FromAccountID uint      `gorm:"not null;index"`
+	ToAccountID   uint      `gorm:"not null;index"`
+	Amount        float64   `gorm:"type:decimal(15,2);not null"`
+	Description   string    `gorm:"type:text"`
+	Date          time.Time `gorm:"not null"`
+	FromAccount   Account   `gorm:"foreignKey:FromAccountID"`
+	ToAccount     Account   `gorm:"foreignKey:ToAccountID"`
+}
+
+func (t *Transfer) BeforeSave(tx *gorm.DB) error {
+	if t.Amount <= 0 {
+		return fmt.Errorf("amount must be greater than 0")
+	}
+
+	if t.FromAccountID == t.ToAccountID {
+		return fmt.Errorf("cannot transfer to the same account")
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Budget adalah batas pengeluaran bulanan user untuk satu kategori.
+// Batas yang sama berlaku setiap bulan sampai diubah atau dihapus.
+type Budget struct {
+	ID         uint     `gorm:"primaryKey"`
+	UserID     uint     `gorm:"not null;uniqueIndex:idx_budget_user_category"`
+	CategoryID uint     `gorm:"not null;uniqueIndex:idx_budget_user_category"`
+	Amount     float64  `gorm:"type:decimal(15,2);not null"`
+	Category   Category `gorm:"foreignKey:CategoryID"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (b *Budget) BeforeSave(tx *gorm.DB) error {
+	if b.Amount <= 0 {
+		return errors.New("budget amount must be greater than 0")
+	}
+	return nil
+}
@@ -8,7 +8,8 @@ import (
 
 type Category struct {
 	gorm.Model
-	UserID    uint   `gorm:"not null;index"`
+	UserID    uint   `gorm:"not null;index"` // member yang membuat kategori
+	LedgerID  uint   `gorm:"index"`
 	Name      string `gorm:"type:varchar(100);not null"`
 	Color     string `gorm:"type:varchar(50);default:'bg-blue-100'"`
 	IconColor string `gorm:"type:varchar(50);default:'text-blue-500'"`
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"gorm.io/gorm"
+)
+
+// CategoryRule mengisi kategori transaksi secara otomatis jika semua kondisi yang diisi cocok.
+// Rule dengan Priority lebih kecil dievaluasi lebih dulu, rule pertama yang cocok dipakai.
+type CategoryRule struct {
+	gorm.Model
+	UserID              uint     `gorm:"not null;index"`
+	CategoryID          uint     `gorm:"not null;index"`
+	Name                string   `gorm:"type:varchar(100)"`
+	Priority            int      `gorm:"not null;default:0"`
+	DescriptionContains string   `gorm:"type:varchar(255)"` // tidak case sensitive
+	DescriptionPattern  string   `gorm:"type:varchar(255)"` // regular expression
+	MinAmount           *float64 `gorm:"type:decimal(15,2)"`
+	MaxAmount           *float64 `gorm:"type:decimal(15,2)"`
+	Type                string   `gorm:"size:20"` // kosong berarti income dan expense
+	Category            Category `gorm:"foreignKey:CategoryID"`
+}
+
+func (r *CategoryRule) BeforeSave(tx *gorm.DB) error {
+	return r.Validate()
+}
+
+// Validate dipanggil juga oleh service supaya pesan error bisa dikembalikan ke user
+func (r *CategoryRule) Validate() error {
+	if r.DescriptionContains == "" && r.DescriptionPattern == "" &&
+		r.MinAmount == nil && r.MaxAmount == nil && r.Type == "" {
+		return errors.New("category rule must have at least one condition")
+	}
+
+	if r.DescriptionPattern != "" {
+		if _, err := regexp.Compile(r.DescriptionPattern); err != nil {
+			return fmt.Errorf("invalid description pattern: %v", err)
+		}
+	}
+
+	if r.MinAmount != nil && r.MaxAmount != nil && *r.MinAmount > *r.MaxAmount {
+		return errors.New("min amount cannot be greater than max amount")
+	}
+
+	if r.Type != "" && r.Type != "income" && r.Type != "expense" {
+		return fmt.Errorf("invalid transaction type: %s", r.Type)
+	}
+
+	return nil
+}
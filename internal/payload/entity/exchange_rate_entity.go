@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExchangeRateManual menandai kurs yang diinput sendiri oleh user
+const ExchangeRateManual = "manual"
+
+// ExchangeRate berarti 1 FromCurrency = Rate ToCurrency, berlaku mulai Date sampai ada kurs
+// yang lebih baru. Kurs disimpan per user, baik input manual maupun hasil rate provider.
+type ExchangeRate struct {
+	ID           uint      `gorm:"primaryKey"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_exchange_rate_pair_date"`
+	FromCurrency string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rate_pair_date"`
+	ToCurrency   string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rate_pair_date"`
+	Date         time.Time `gorm:"not null;uniqueIndex:idx_exchange_rate_pair_date"`
+	Rate         float64   `gorm:"type:decimal(20,8);not null"`
+	Source       string    `gorm:"type:varchar(20);not null;default:'manual'"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (r *ExchangeRate) BeforeSave(tx *gorm.DB) error {
+	if r.Rate <= 0 {
+		return fmt.Errorf("exchange rate must be greater than 0")
+	}
+
+	if r.FromCurrency == r.ToCurrency {
+		return fmt.Errorf("exchange rate currencies must be different")
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// GiftCard hanya menyimpan hash kode, kode aslinya ditampilkan sekali saat kartu diterbitkan
+type GiftCard struct {
+	ID             uint       `gorm:"primaryKey"`
+	CodeHash       string     `gorm:"type:varchar(64);uniqueIndex;not null"`
+	CodeLast4      string     `gorm:"type:varchar(4);not null"`
+	InitialBalance float64    `gorm:"type:decimal(15,2);not null"`
+	Balance        float64    `gorm:"type:decimal(15,2);not null"`
+	IssuedBy       uint       `gorm:"not null"`
+	Note           string     `gorm:"type:varchar(255)"`
+	ExpiresAt      *time.Time `gorm:"index"`
+	Disabled       bool       `gorm:"not null;default:false"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// GiftCardUsage adalah catatan pergerakan saldo gift card, Amount negatif saat saldo dipakai
+type GiftCardUsage struct {
+	ID           uint      `gorm:"primaryKey"`
+	GiftCardID   uint      `gorm:"not null;index;uniqueIndex:idx_gift_card_usage_reference"`
+	UserID       *uint     `gorm:"index"`
+	Amount       float64   `gorm:"type:decimal(15,2);not null"`
+	BalanceAfter float64   `gorm:"type:decimal(15,2);not null"`
+	Reference    string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_gift_card_usage_reference"`
+	CreatedAt    time.Time `gorm:"index"`
+}
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+const (
+	InsightKindUnusualTransaction = "unusual_transaction"
+	InsightKindCategorySpike      = "category_spike"
+
+	InsightSeverityInfo     = "info"
+	InsightSeverityWarning  = "warning"
+	InsightSeverityCritical = "critical"
+)
+
+// Insight adalah temuan pengeluaran tidak biasa yang dihitung ulang oleh background job.
+// Key unik per user sehingga insight yang sama diperbarui saat dihitung ulang dan status
+// dismiss-nya tidak hilang.
+type Insight struct {
+	ID            uint      `gorm:"primaryKey"`
+	UserID        uint      `gorm:"not null;uniqueIndex:idx_insight_user_key"`
+	Key           string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_insight_user_key"`
+	Kind          string    `gorm:"type:varchar(30);not null"`
+	Severity      string    `gorm:"type:varchar(10);not null"`
+	Message       string    `gorm:"type:varchar(255);not null"`
+	CategoryID    *uint     `gorm:"index"`
+	TransactionID *uint     `gorm:"index"`
+	Period        time.Time `gorm:"type:date;not null"`          // awal bulan yang dianalisis
+	Amount        float64   `gorm:"type:decimal(15,2);not null"` // dalam base currency
+	Baseline      float64   `gorm:"type:decimal(15,2);not null"` // nilai yang biasa, dalam base currency
+	DismissedAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	LedgerRoleOwner  = "owner"
+	LedgerRoleEditor = "editor"
+	LedgerRoleViewer = "viewer"
+)
+
+// Ledger adalah buku kas tempat kategori dan transaksi dicatat, bisa dipakai bersama beberapa
+// member. Setiap user punya satu personal ledger yang tidak bisa dibagikan, data lama sebelum
+// ada ledger dipindahkan ke personal ledger masing-masing.
+type Ledger struct {
+	gorm.Model
+	Name     string         `gorm:"type:varchar(100);not null"`
+	OwnerID  uint           `gorm:"not null;index"`
+	Personal bool           `gorm:"not null;default:false"`
+	Members  []LedgerMember `gorm:"foreignKey:LedgerID"`
+}
+
+func (l *Ledger) BeforeSave(tx *gorm.DB) error {
+	if l.Name == "" {
+		return errors.New("ledger name cannot be empty")
+	}
+	return nil
+}
+
+// LedgerMember adalah akses user ke ledger. Owner dan editor bisa mengubah kategori dan
+// transaksi, viewer hanya bisa melihat.
+type LedgerMember struct {
+	ID        uint   `gorm:"primaryKey"`
+	LedgerID  uint   `gorm:"not null;uniqueIndex:idx_ledger_member"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_ledger_member;index"`
+	Role      string `gorm:"type:varchar(10);not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	User      User `gorm:"foreignKey:UserID"`
+}
+
+// LedgerInvitation adalah undangan join ledger untuk email tertentu. Undangan dihapus setelah
+// diterima atau ditolak, user yang belum terdaftar bisa menerimanya setelah register.
+type LedgerInvitation struct {
+	ID        uint      `gorm:"primaryKey"`
+	LedgerID  uint      `gorm:"not null;uniqueIndex:idx_ledger_invitation"`
+	Email     string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_ledger_invitation;index"`
+	Role      string    `gorm:"type:varchar(10);not null"`
+	InvitedBy uint      `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Ledger    Ledger `gorm:"foreignKey:LedgerID"`
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	LoyaltyEntryEarn   = "earn"
+	LoyaltyEntryRedeem = "redeem"
+	LoyaltyEntryExpire = "expire"
+	LoyaltyEntryAdjust = "adjust"
+)
+
+var ErrLoyaltyLedgerImmutable = errors.New("loyalty ledger entries cannot be modified")
+
+// LoyaltyAccount menyimpan saldo poin user saat ini. Row ini di-lock selama entry
+// ledger ditambahkan supaya redeem yang bersamaan diproses bergantian.
+type LoyaltyAccount struct {
+	UserID    uint  `gorm:"primaryKey;autoIncrement:false"`
+	Balance   int64 `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+}
+
+// LoyaltyLedgerEntry adalah catatan pergerakan poin yang hanya bisa ditambah. Points
+// positif untuk earn dan negatif untuk redeem/expire, Balance adalah saldo setelah entry ini.
+type LoyaltyLedgerEntry struct {
+	ID          uint       `gorm:"primaryKey"`
+	UserID      uint       `gorm:"not null;index;uniqueIndex:idx_loyalty_entry_reference"`
+	Type        string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_loyalty_entry_reference"`
+	Reference   *string    `gorm:"type:varchar(100);uniqueIndex:idx_loyalty_entry_reference"`
+	Points      int64      `gorm:"not null"`
+	Balance     int64      `gorm:"not null"`
+	Description string     `gorm:"type:varchar(255)"`
+	ExpiresAt   *time.Time `gorm:"index"`
+	CreatedAt   time.Time  `gorm:"index"`
+}
+
+func (e *LoyaltyLedgerEntry) BeforeUpdate(tx *gorm.DB) error {
+	return ErrLoyaltyLedgerImmutable
+}
+
+func (e *LoyaltyLedgerEntry) BeforeDelete(tx *gorm.DB) error {
+	return ErrLoyaltyLedgerImmutable
+}
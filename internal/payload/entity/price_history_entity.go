@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+type ProductPriceHistory struct {
+	ID        uint      `gorm:"primaryKey"`
+	ProductID uint      `gorm:"not null;index"`
+	OldPrice  float64   `gorm:"type:decimal(15,2);not null"`
+	NewPrice  float64   `gorm:"type:decimal(15,2);not null"`
+	ChangedAt time.Time `gorm:"not null;index"`
+}
+
+// PriceAlert memberi notifikasi sekali saat harga produk turun sampai TargetPrice atau lebih
+// rendah. Setelah terpicu alert menjadi tidak aktif.
+type PriceAlert struct {
+	ID          uint    `gorm:"primaryKey"`
+	UserID      uint    `gorm:"not null;uniqueIndex:idx_price_alert_user_product"`
+	ProductID   uint    `gorm:"not null;uniqueIndex:idx_price_alert_user_product;index"`
+	TargetPrice float64 `gorm:"type:decimal(15,2);not null"`
+	Active      bool    `gorm:"not null;default:true;index"`
+	TriggeredAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Product     Product `gorm:"foreignKey:ProductID"`
+}
@@ -8,6 +8,7 @@ type Product struct {
 	Category  string  `gorm:"type:varchar(100);not null;index"`
 	Name      string  `gorm:"type:varchar(255);not null"`
 	Price     float64 `gorm:"type:decimal(15,2);not null"`
+	Stock     *int    // nil berarti stok tidak dilacak, misalnya produk yang dibuat sebelum ada kolom stock
 	ImageLink string  `gorm:"type:varchar(255)"`
 }
 
@@ -18,5 +19,13 @@ func (p *Product) BeforeCreate(tx *gorm.DB) error {
 	if p.Price <= 0 {
 		return gorm.ErrInvalidData
 	}
+	if p.Stock != nil && *p.Stock < 0 {
+		return gorm.ErrInvalidData
+	}
 	return nil
 }
+
+// InStock bernilai true jika stok masih ada atau stok produk tidak dilacak
+func (p *Product) InStock() bool {
+	return p.Stock == nil || *p.Stock > 0
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// ProductView adalah satu kali lihat detail produk, milik user yang login atau session anonim
+type ProductView struct {
+	ID        uint      `gorm:"primaryKey"`
+	ProductID uint      `gorm:"not null;index"`
+	UserID    *uint     `gorm:"index:idx_product_views_user_viewed"`
+	SessionID string    `gorm:"type:varchar(64);index:idx_product_views_session_viewed"`
+	ViewedAt  time.Time `gorm:"not null;index:idx_product_views_user_viewed;index:idx_product_views_session_viewed"`
+	Product   Product   `gorm:"foreignKey:ProductID"`
+}
+
+// ProductViewStat menyimpan jumlah view produk yang sudah diagregasi per hari
+type ProductViewStat struct {
+	ProductID uint      `gorm:"primaryKey"`
+	Date      time.Time `gorm:"type:date;primaryKey"`
+	ViewCount int64     `gorm:"not null;default:0"`
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+	RecurrenceYearly  = "yearly"
+)
+
+const (
+	OccurrenceScheduled = "scheduled" // dibuat lebih awal karena ada override, belum diposting
+	OccurrenceSkipped   = "skipped"
+	OccurrencePosted    = "posted"
+)
+
+// RecurringTransaction adalah template transaksi yang diposting otomatis oleh scheduler.
+// NextRunDate selalu menunjuk occurrence ke-OccurrenceCount yang belum diproses.
+type RecurringTransaction struct {
+	gorm.Model
+	UserID          uint       `gorm:"not null;index"`
+	CategoryID      uint       `gorm:"not null"`
+	AccountID       *uint      `gorm:"index"`
+	Amount          float64    `gorm:"not null"`
+	Type            string     `gorm:"size:20;not null"` // income atau expense
+	Description     string     `gorm:"type:text"`
+	Frequency       string     `gorm:"size:20;not null"`
+	Interval        int        `gorm:"not null;default:1"`
+	StartDate       time.Time  `gorm:"type:date;not null"`
+	EndDate         *time.Time `gorm:"type:date"`
+	MaxOccurrences  *int
+	OccurrenceCount int       `gorm:"not null;default:0"`
+	NextRunDate     time.Time `gorm:"type:date;not null;index"`
+	Active          bool      `gorm:"not null;default:true;index"`
+	Category        Category  `gorm:"foreignKey:CategoryID"`
+}
+
+func (r *RecurringTransaction) BeforeSave(tx *gorm.DB) error {
+	validFrequencies := map[string]bool{
+		RecurrenceDaily:   true,
+		RecurrenceWeekly:  true,
+		RecurrenceMonthly: true,
+		RecurrenceYearly:  true,
+	}
+	if !validFrequencies[r.Frequency] {
+		return fmt.Errorf("invalid frequency: %s", r.Frequency)
+	}
+
+	if r.Type != "income" && r.Type != "expense" {
+		return fmt.Errorf("invalid transaction type: %s", r.Type)
+	}
+
+	if r.Amount <= 0 {
+		return fmt.Errorf("amount must be greater than 0")
+	}
+
+	if r.Interval <= 0 {
+		r.Interval = 1
+	}
+
+	return nil
+}
+
+// RecurringOccurrence mencatat satu tanggal dari jadwal recurring. Unique index
+// (recurring_transaction_id, date) menjamin satu tanggal hanya diposting sekali.
+// Amount/Description diisi jika occurrence tersebut diedit sebelum diposting.
+type RecurringOccurrence struct {
+	ID                     uint      `gorm:"primaryKey"`
+	RecurringTransactionID uint      `gorm:"not null;uniqueIndex:idx_recurring_occurrence_date"`
+	Date                   time.Time `gorm:"type:date;not null;uniqueIndex:idx_recurring_occurrence_date"`
+	Status                 string    `gorm:"size:20;not null"`
+	Amount                 *float64
+	Description            *string `gorm:"type:text"`
+	TransactionID          *uint   `gorm:"index"`
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ReportFormatXLSX = "xlsx"
+	ReportFormatPDF  = "pdf"
+)
+
+// ReportSchedule adalah pengiriman laporan bulanan otomatis lewat notifier. Satu user hanya
+// punya satu jadwal, laporan bulan sebelumnya dikirim mulai tanggal DayOfMonth.
+type ReportSchedule struct {
+	ID         uint       `gorm:"primaryKey"`
+	UserID     uint       `gorm:"not null;uniqueIndex"`
+	Format     string     `gorm:"type:varchar(10);not null;default:'pdf'"`
+	DayOfMonth int        `gorm:"not null;default:1"`
+	Enabled    bool       `gorm:"not null"`
+	LastPeriod *time.Time `gorm:"type:date"` // awal bulan laporan terakhir yang terkirim
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (r *ReportSchedule) BeforeSave(tx *gorm.DB) error {
+	if r.Format != ReportFormatXLSX && r.Format != ReportFormatPDF {
+		return errors.New("report format must be xlsx or pdf")
+	}
+	// dibatasi 28 supaya jadwal tetap jalan di bulan Februari
+	if r.DayOfMonth < 1 || r.DayOfMonth > 28 {
+		return errors.New("day of month must be between 1 and 28")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SavingsGoal adalah target tabungan user. Dana terkumpul dihitung dari kontribusi manual
+// ditambah, jika di-link, arus dana account atau transaksi kategori sejak StartDate.
+type SavingsGoal struct {
+	gorm.Model
+	UserID        uint                  `gorm:"not null;index"`
+	Name          string                `gorm:"type:varchar(100);not null"`
+	TargetAmount  float64               `gorm:"type:decimal(15,2);not null"`
+	TargetDate    *time.Time            `gorm:"type:date"`
+	StartDate     time.Time             `gorm:"type:date;not null"`
+	AccountID     *uint                 `gorm:"index"`
+	CategoryID    *uint                 `gorm:"index"`
+	Contributions []SavingsContribution `gorm:"foreignKey:GoalID"`
+}
+
+func (g *SavingsGoal) BeforeSave(tx *gorm.DB) error {
+	return g.Validate()
+}
+
+func (g *SavingsGoal) Validate() error {
+	if g.Name == "" {
+		return errors.New("savings goal name cannot be empty")
+	}
+	if g.TargetAmount <= 0 {
+		return errors.New("target amount must be greater than 0")
+	}
+	// account dan kategori bisa tumpang tindih sehingga dana terhitung dua kali
+	if g.AccountID != nil && g.CategoryID != nil {
+		return errors.New("savings goal can be linked to an account or a category, not both")
+	}
+	if g.TargetDate != nil && !g.TargetDate.After(g.StartDate) {
+		return errors.New("target date must be after start date")
+	}
+	return nil
+}
+
+// SavingsContribution adalah setoran manual ke savings goal. Amount negatif berarti penarikan.
+type SavingsContribution struct {
+	ID        uint      `gorm:"primaryKey"`
+	GoalID    uint      `gorm:"not null;index"`
+	Amount    float64   `gorm:"type:decimal(15,2);not null"`
+	Date      time.Time `gorm:"type:date;not null"`
+	Note      string    `gorm:"type:varchar(255)"`
+	CreatedAt time.Time
+}
+
+func (c *SavingsContribution) BeforeSave(tx *gorm.DB) error {
+	if c.Amount == 0 {
+		return errors.New("contribution amount cannot be 0")
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// StockSubscription adalah permintaan user untuk diberi notifikasi saat produk yang habis
+// tersedia lagi. Subscriber dilayani berurutan sesuai CreatedAt.
+type StockSubscription struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_stock_subscription_user_product"`
+	ProductID  uint      `gorm:"not null;uniqueIndex:idx_stock_subscription_user_product;index:idx_stock_subscription_queue"`
+	CreatedAt  time.Time `gorm:"index:idx_stock_subscription_queue"`
+	NotifiedAt *time.Time
+	Product    Product `gorm:"foreignKey:ProductID"`
+}
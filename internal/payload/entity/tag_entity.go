@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tag adalah label bebas milik user, satu transaksi bisa punya banyak tag.
+// Nama tag selalu disimpan lowercase tanpa spasi, misalnya "trip-bali".
+type Tag struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_tag_user_name"`
+	Name      string `gorm:"type:varchar(50);not null;uniqueIndex:idx_tag_user_name"`
+	CreatedAt time.Time
+}
+
+func (t *Tag) BeforeSave(tx *gorm.DB) error {
+	if t.Name == "" {
+		return errors.New("tag name cannot be empty")
+	}
+	return nil
+}
@@ -8,14 +8,22 @@ import (
 
 type Transaction struct {
 	gorm.Model
-	UserID      uint      `gorm:"not null"`
+	UserID      uint      `gorm:"not null"` // member yang mencatat transaksi
+	LedgerID    uint      `gorm:"index"`    // selalu sama dengan ledger kategorinya
 	CategoryID  uint      `gorm:"not null"`
+	AccountID   *uint     `gorm:"index"`
 	Amount      float64   `gorm:"not null"`
-	Type        string    `gorm:"size:20;not null"` // income atau expense
+	Currency    string    `gorm:"type:varchar(3);not null;default:'IDR'"` // sama dengan mata uang account jika ada
+	Type        string    `gorm:"size:20;not null"`                       // income atau expense
 	Description string    `gorm:"type:text"`
 	Date        time.Time `gorm:"not null"`
 	User        User      `gorm:"foreignKey:UserID"`
 	Category    Category  `gorm:"foreignKey:CategoryID"`
+	Account     *Account  `gorm:"foreignKey:AccountID"`
+	// Splits membagi transaksi ke beberapa kategori, CategoryID tetap diisi kategori split pertama
+	Splits      []TransactionSplit      `gorm:"foreignKey:TransactionID"`
+	Attachments []TransactionAttachment `gorm:"foreignKey:TransactionID"`
+	Tags        []Tag                   `gorm:"many2many:transaction_tags"`
 }
 
 func (t *Transaction) BeforeSave(tx *gorm.DB) (err error) {
@@ -35,3 +43,32 @@ func (t *Transaction) BeforeSave(tx *gorm.DB) (err error) {
 
 	return nil
 }
+
+// TransactionSplit adalah satu baris pembagian transaksi per kategori.
+// Total amount semua split sama dengan amount transaksinya.
+type TransactionSplit struct {
+	ID            uint     `gorm:"primaryKey"`
+	TransactionID uint     `gorm:"not null;index"`
+	CategoryID    uint     `gorm:"not null;index"`
+	Amount        float64  `gorm:"type:decimal(15,2);not null"`
+	Description   string   `gorm:"type:varchar(255)"`
+	Category      Category `gorm:"foreignKey:CategoryID"`
+}
+
+func (s *TransactionSplit) BeforeSave(tx *gorm.DB) error {
+	if s.Amount <= 0 {
+		return fmt.Errorf("split amount must be greater than 0")
+	}
+	return nil
+}
+
+// TransactionAttachment adalah metadata struk/lampiran, isi file disimpan di storage
+type TransactionAttachment struct {
+	gorm.Model
+	TransactionID uint   `gorm:"not null;index"`
+	UserID        uint   `gorm:"not null;index"`
+	FileName      string `gorm:"type:varchar(255);not null"`
+	ContentType   string `gorm:"type:varchar(100);not null"`
+	Size          int64  `gorm:"not null"`
+	StorageKey    string `gorm:"type:varchar(500);not null"`
+}
@@ -11,4 +11,8 @@ type User struct {
 	IsAdmin    bool   `gorm:"type:boolean;default:false"`
 	Provider   string `gorm:"type:varchar(50);omitempty"`
 	ProfilePic string `gorm:"type:varchar(255);omitempty"`
+	Timezone   string `gorm:"type:varchar(64);not null;default:'UTC'"` // nama IANA, misalnya Asia/Jakarta
+	Locale     string `gorm:"type:varchar(10);not null;default:'en'"`
+	// semua total di dashboard dan ringkasan transaksi dilaporkan dalam mata uang ini
+	BaseCurrency string `gorm:"type:varchar(3);not null;default:'IDR'"`
 }
@@ -0,0 +1,23 @@
+package request
+
+type AccountRequest struct {
+	Name           string  `json:"name" binding:"required,max=100"`
+	Type           string  `json:"type" binding:"required,oneof=cash bank ewallet credit_card"`
+	OpeningBalance float64 `json:"opening_balance"` // boleh negatif, misalnya tagihan kartu kredit
+	// kosong berarti base currency user, tidak bisa diubah setelah account dibuat
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+}
+
+type TransferRequest struct {
+	FromAccountID uint    `json:"from_account_id" binding:"required"`
+	ToAccountID   uint    `json:"to_account_id" binding:"required,nefield=FromAccountID"`
+	Amount        float64 `json:"amount" binding:"required,gt=0"`
+	Description   string  `json:"description"`
+	Date          string  `json:"date" binding:"required"` // 2006-01-02 atau RFC3339, timestamp memakai timezone user
+}
+
+type TransferFilter struct {
+	AccountID uint `form:"account_id"`
+	Page      int  `form:"page,default=1"`
+	Limit     int  `form:"limit,default=10"`
+}
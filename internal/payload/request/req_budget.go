@@ -0,0 +1,10 @@
+package request
+
+type BudgetRequest struct {
+	CategoryID uint    `json:"category_id" binding:"required"`
+	Amount     float64 `json:"amount" binding:"required,gt=0"`
+}
+
+type UpdateBudgetRequest struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+}
@@ -1,6 +1,7 @@
 package request
 
 type CategoryRequest struct {
+	LedgerID  uint   `json:"ledger_id"` // kosong berarti personal ledger
 	Name      string `json:"name" binding:"required"`
 	Color     string `json:"color"`
 	IconColor string `json:"icon_color"`
@@ -11,3 +12,7 @@ type UpdateCategoryRequest struct {
 	Color     string `json:"color"`
 	IconColor string `json:"icon_color"`
 }
+
+type CategoryFilter struct {
+	LedgerID uint `form:"ledger_id"` // kosong berarti personal ledger
+}
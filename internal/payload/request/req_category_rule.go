@@ -0,0 +1,18 @@
+package request
+
+type CategoryRuleRequest struct {
+	CategoryID          uint     `json:"category_id" binding:"required"`
+	Name                string   `json:"name" binding:"max=100"`
+	Priority            int      `json:"priority"` // lebih kecil dievaluasi lebih dulu
+	DescriptionContains string   `json:"description_contains" binding:"max=255"`
+	DescriptionPattern  string   `json:"description_pattern" binding:"max=255"` // regular expression
+	MinAmount           *float64 `json:"min_amount" binding:"omitempty,gte=0"`
+	MaxAmount           *float64 `json:"max_amount" binding:"omitempty,gte=0"`
+	Type                string   `json:"type" binding:"omitempty,oneof=income expense"`
+}
+
+// ApplyCategoryRulesRequest membatasi transaksi yang dikategorikan ulang, kosong berarti semua
+type ApplyCategoryRulesRequest struct {
+	StartDate string `json:"start_date"` // format 2006-01-02
+	EndDate   string `json:"end_date"`   // format 2006-01-02
+}
@@ -0,0 +1,8 @@
+package request
+
+type DashboardChartsFilter struct {
+	From           string `form:"from"` // format 2006-01-02, default awal bulan lima bulan lalu
+	To             string `form:"to"`   // format 2006-01-02 inklusif, default hari ini
+	Granularity    string `form:"granularity,default=month" binding:"oneof=day week month quarter year"`
+	ForecastMonths int    `form:"forecast_months,default=6" binding:"oneof=3 6 12"`
+}
@@ -0,0 +1,14 @@
+package request
+
+// ExchangeRateRequest berarti 1 FromCurrency = Rate ToCurrency mulai Date.
+// Kurs dengan pasangan dan tanggal yang sama akan ditimpa.
+type ExchangeRateRequest struct {
+	FromCurrency string  `json:"from_currency" binding:"required,len=3"`
+	ToCurrency   string  `json:"to_currency" binding:"required,len=3"`
+	Rate         float64 `json:"rate" binding:"required,gt=0"`
+	Date         string  `json:"date"` // 2006-01-02 atau RFC3339, kosong berarti hari ini menurut timezone user
+}
+
+type ExchangeRateFilter struct {
+	Currency string `form:"currency"` // kurs dari atau ke mata uang ini
+}
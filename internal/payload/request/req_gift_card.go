@@ -0,0 +1,16 @@
+package request
+
+type IssueGiftCardRequest struct {
+	Amount    float64 `json:"amount" binding:"required,gt=0"`
+	ExpiresAt string  `json:"expires_at"` // format 2006-01-02, kosong berarti tidak kedaluwarsa
+	Note      string  `json:"note" binding:"max=255"`
+}
+
+type GiftCardCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type GiftCardFilter struct {
+	Page  int `form:"page,default=1"`
+	Limit int `form:"limit,default=10"`
+}
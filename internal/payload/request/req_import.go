@@ -0,0 +1,31 @@
+package request
+
+// ImportPreviewRequest dikirim sebagai multipart form bersama field "file".
+// Untuk CSV tanpa mapping kolom, response berisi header dan contoh baris untuk langkah mapping.
+type ImportPreviewRequest struct {
+	Format            string `form:"format" binding:"required,oneof=csv ofx qif"`
+	DateColumn        string `form:"date_column"`
+	AmountColumn      string `form:"amount_column"`
+	DescriptionColumn string `form:"description_column"`
+	TypeColumn        string `form:"type_column"`
+	DateFormat        string `form:"date_format"`       // contoh DD/MM/YYYY, default YYYY-MM-DD
+	DecimalSeparator  string `form:"decimal_separator"` // "." atau ","
+	Delimiter         string `form:"delimiter"`         // "," atau ";"
+}
+
+// ImportRow divalidasi per baris di service supaya baris yang salah hanya gagal sendiri
+type ImportRow struct {
+	Index          int     `json:"index"`
+	Date           string  `json:"date"` // format 2006-01-02
+	Amount         float64 `json:"amount"`
+	Type           string  `json:"type"`
+	Description    string  `json:"description"`
+	CategoryID     uint    `json:"category_id"`     // kosong berarti dari category rule, lalu default_category_id
+	AllowDuplicate bool    `json:"allow_duplicate"` // tetap import walaupun terdeteksi duplikat
+}
+
+type ImportCommitRequest struct {
+	AccountID         *uint       `json:"account_id"`
+	DefaultCategoryID uint        `json:"default_category_id"` // dipakai jika tidak ada category rule yang cocok
+	Rows              []ImportRow `json:"rows" binding:"required,min=1,max=1000"`
+}
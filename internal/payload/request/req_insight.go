@@ -0,0 +1,5 @@
+package request
+
+type InsightFilter struct {
+	IncludeDismissed bool `form:"include_dismissed"`
+}
@@ -0,0 +1,18 @@
+package request
+
+type LedgerRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type LedgerInvitationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type LedgerMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type LedgerSummaryFilter struct {
+	Month string `form:"month"` // format 2006-01, default bulan berjalan
+}
@@ -0,0 +1,12 @@
+package request
+
+type LoyaltyAdjustRequest struct {
+	UserID      uint   `json:"user_id" binding:"required"`
+	Points      int64  `json:"points" binding:"required,ne=0"`
+	Description string `json:"description" binding:"required,max=255"`
+}
+
+type LoyaltyLedgerFilter struct {
+	Page  int `form:"page,default=1"`
+	Limit int `form:"limit,default=10"`
+}
@@ -5,6 +5,7 @@ type ProductRequest struct {
 	Category  string  `json:"category" binding:"required"`
 	Name      string  `json:"name" binding:"required"`
 	Price     float64 `json:"price" binding:"required,gt=0"`
+	Stock     *int    `json:"stock" binding:"omitempty,gte=0"` // nil berarti stok tidak dilacak
 	ImageLink string  `json:"image_link"`
 }
 
@@ -13,6 +14,7 @@ type UpdateProductRequest struct {
 	Category  string  `json:"category" binding:"required"`
 	Name      string  `json:"name" binding:"required"`
 	Price     float64 `json:"price" binding:"required,gt=0"`
+	Stock     *int    `json:"stock" binding:"omitempty,gte=0"` // nil berarti stok tidak diubah
 	ImageLink string  `json:"image_link"`
 }
 
@@ -21,6 +23,18 @@ type ProductFilter struct {
 	Search   string  `form:"search"`
 	MinPrice float64 `form:"min_price"`
 	MaxPrice float64 `form:"max_price"`
+	Sort     string  `form:"sort" binding:"omitempty,oneof=newest price_asc price_desc popularity"`
 	Page     int     `form:"page,default=1"`
 	Limit    int     `form:"limit,default=10"`
 }
+
+type ProductViewFilter struct {
+	From      string `form:"from"` // format 2006-01-02
+	To        string `form:"to"`   // format 2006-01-02
+	ProductID uint   `form:"product_id"`
+	Limit     int    `form:"limit,default=10"` // maksimal 100
+}
+
+type PriceAlertRequest struct {
+	TargetPrice float64 `json:"target_price" binding:"required,gt=0"`
+}
@@ -0,0 +1,36 @@
+package request
+
+type CreateRecurringTransactionRequest struct {
+	CategoryID     uint    `json:"category_id" binding:"required"`
+	AccountID      *uint   `json:"account_id"`
+	Amount         float64 `json:"amount" binding:"required,gt=0"`
+	Type           string  `json:"type" binding:"required,oneof=income expense"`
+	Description    string  `json:"description"`
+	Frequency      string  `json:"frequency" binding:"required,oneof=daily weekly monthly yearly"`
+	Interval       int     `json:"interval" binding:"omitempty,gte=1,lte=365"` // default 1
+	StartDate      string  `json:"start_date" binding:"required"`              // format 2006-01-02
+	EndDate        string  `json:"end_date"`                                   // format 2006-01-02, opsional
+	MaxOccurrences *int    `json:"max_occurrences" binding:"omitempty,gte=1"`
+}
+
+type UpdateRecurringTransactionRequest struct {
+	CategoryID     uint    `json:"category_id" binding:"required"`
+	AccountID      *uint   `json:"account_id"`
+	Amount         float64 `json:"amount" binding:"required,gt=0"`
+	Type           string  `json:"type" binding:"required,oneof=income expense"`
+	Description    string  `json:"description"`
+	EndDate        string  `json:"end_date"` // format 2006-01-02, kosong berarti tanpa tanggal akhir
+	MaxOccurrences *int    `json:"max_occurrences" binding:"omitempty,gte=1"`
+	Active         *bool   `json:"active"`
+}
+
+// UpdateOccurrenceRequest mengubah atau melewati satu occurrence yang belum diposting
+type UpdateOccurrenceRequest struct {
+	Skip        bool     `json:"skip"`
+	Amount      *float64 `json:"amount" binding:"omitempty,gt=0"`
+	Description *string  `json:"description"`
+}
+
+type OccurrenceFilter struct {
+	Upcoming int `form:"upcoming,default=5" binding:"omitempty,gte=1,lte=50"`
+}
@@ -0,0 +1,12 @@
+package request
+
+type MonthlyReportFilter struct {
+	Month  string `form:"month"` // format 2006-01, default bulan berjalan
+	Format string `form:"format,default=xlsx" binding:"oneof=xlsx pdf"`
+}
+
+type ReportScheduleRequest struct {
+	Format     string `json:"format" binding:"required,oneof=xlsx pdf"`
+	DayOfMonth int    `json:"day_of_month" binding:"omitempty,min=1,max=28"` // default 1
+	Enabled    *bool  `json:"enabled"`                                       // default true
+}
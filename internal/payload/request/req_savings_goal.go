@@ -0,0 +1,16 @@
+package request
+
+type SavingsGoalRequest struct {
+	Name         string  `json:"name" binding:"required,max=100"`
+	TargetAmount float64 `json:"target_amount" binding:"required,gt=0"`
+	TargetDate   string  `json:"target_date"` // format 2006-01-02, opsional
+	StartDate    string  `json:"start_date"`  // format 2006-01-02, default hari ini
+	AccountID    *uint   `json:"account_id" binding:"omitempty,excluded_with=CategoryID"`
+	CategoryID   *uint   `json:"category_id"`
+}
+
+type SavingsContributionRequest struct {
+	Amount float64 `json:"amount" binding:"required,ne=0"` // negatif untuk penarikan
+	Date   string  `json:"date"`                           // 2006-01-02 atau RFC3339, default hari ini menurut timezone user
+	Note   string  `json:"note" binding:"max=255"`
+}
@@ -1,26 +1,88 @@
 package request
 
 type CreateTransactionRequest struct {
-	CategoryID  uint    `json:"category_id" binding:"required"`
+	LedgerID    uint    `json:"ledger_id"`   // kosong berarti personal ledger
+	CategoryID  uint    `json:"category_id"` // kosong berarti ditentukan oleh category rule, harus kategori ledger yang sama
+	AccountID   *uint   `json:"account_id"`
 	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	Currency    string  `json:"currency" binding:"omitempty,len=3"` // kosong berarti mata uang account atau base currency user
 	Type        string  `json:"type" binding:"required,oneof=income expense"`
 	Description string  `json:"description"`
-	Date        string  `json:"date" binding:"required"`
+	Date        string  `json:"date" binding:"required"` // 2006-01-02 atau RFC3339, timestamp memakai timezone user
+	// minimal 2 split dengan total sama dengan amount, kosong berarti satu kategori
+	Splits []TransactionSplitRequest `json:"splits" binding:"omitempty,dive"`
+	Tags   []string                  `json:"tags" binding:"omitempty,max=20,dive,max=50"`
 }
 
 type UpdateTransactionRequest struct {
-	CategoryID  uint    `json:"category_id" binding:"required"`
+	CategoryID  uint    `json:"category_id" binding:"required_without=Splits"`
+	AccountID   *uint   `json:"account_id"`
 	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	Currency    string  `json:"currency" binding:"omitempty,len=3"` // kosong berarti mata uang account atau base currency user
 	Type        string  `json:"type" binding:"required,oneof=income expense"`
 	Description string  `json:"description"`
-	Date        string  `json:"date" binding:"required"`
+	Date        string  `json:"date" binding:"required"` // 2006-01-02 atau RFC3339, timestamp memakai timezone user
+	// split lama diganti seluruhnya, kosong berarti transaksi tidak di-split lagi
+	Splits []TransactionSplitRequest `json:"splits" binding:"omitempty,dive"`
+	Tags   []string                  `json:"tags" binding:"omitempty,max=20,dive,max=50"`
+}
+
+type TransactionSplitRequest struct {
+	CategoryID  uint    `json:"category_id" binding:"required"`
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	Description string  `json:"description" binding:"max=255"`
 }
 
 type TransactionFilter struct {
-	StartDate  string `form:"start_date"` // format 2006-01-02
-	EndDate    string `form:"end_date"`   // format 2006-01-02
-	CategoryID uint   `form:"category_id"`
-	Type       string `form:"type" binding:"omitempty,oneof=income expense"`
-	Page       int    `form:"page,default=1"`
-	Limit      int    `form:"limit,default=10"`
+	// kosong berarti transaksi yang dicatat user di semua ledger, diisi berarti transaksi semua member ledger tersebut
+	LedgerID   uint     `form:"ledger_id" json:"ledger_id"`
+	StartDate  string   `form:"start_date" json:"start_date"` // format 2006-01-02
+	EndDate    string   `form:"end_date" json:"end_date"`     // format 2006-01-02
+	CategoryID uint     `form:"category_id" json:"category_id"`
+	AccountID  uint     `form:"account_id" json:"account_id"`
+	Type       string   `form:"type" json:"type" binding:"omitempty,oneof=income expense"`
+	Tags       string   `form:"tags" json:"tags"`                                       // dipisah koma, transaksi harus punya semua tag
+	Q          string   `form:"q" json:"q" binding:"max=100"`                           // kata atau awalan kata di deskripsi dan nama kategori
+	MinAmount  *float64 `form:"min_amount" json:"min_amount" binding:"omitempty,gte=0"` // dalam mata uang transaksi
+	MaxAmount  *float64 `form:"max_amount" json:"max_amount" binding:"omitempty,gte=0"`
+	Sort       string   `form:"sort" json:"-" binding:"omitempty,oneof=date_desc date_asc amount_desc amount_asc relevance"` // default date_desc, relevance hanya berlaku jika Q diisi
+	Page       int      `form:"page,default=1" json:"-"`
+	Limit      int      `form:"limit,default=10" json:"-"`
+}
+
+// TransactionExportFilter memakai filter yang sama dengan list transaksi, page, limit dan sort
+// diabaikan karena export selalu berisi semua transaksi yang cocok, urut dari yang terbaru
+type TransactionExportFilter struct {
+	TransactionFilter
+	Format string `form:"format,default=xlsx" binding:"oneof=csv xlsx json"`
+}
+
+const (
+	BulkActionRecategorize = "recategorize"
+	BulkActionDelete       = "delete"
+	BulkActionChangeType   = "change_type"
+	BulkActionAddTags      = "add_tags"
+	BulkActionRemoveTags   = "remove_tags"
+	BulkActionShiftDate    = "shift_date"
+)
+
+// BulkTransactionRequest menjalankan satu aksi pada transaksi yang dipilih lewat IDs atau
+// Filter (salah satu saja). Field lain hanya dipakai oleh aksi yang bersangkutan.
+type BulkTransactionRequest struct {
+	Action     string             `json:"action" binding:"required,oneof=recategorize delete change_type add_tags remove_tags shift_date"`
+	IDs        []uint             `json:"ids" binding:"omitempty,max=5000"`
+	Filter     *TransactionFilter `json:"filter"`
+	CategoryID uint               `json:"category_id"`                                   // recategorize
+	Type       string             `json:"type" binding:"omitempty,oneof=income expense"` // change_type
+	Tags       []string           `json:"tags"`                                          // add_tags, remove_tags
+	Days       int                `json:"days"`                                          // shift_date, negatif berarti mundur
+}
+
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+type TagSummaryFilter struct {
+	StartDate string `form:"start_date"` // format 2006-01-02
+	EndDate   string `form:"end_date"`   // format 2006-01-02
 }
@@ -0,0 +1,8 @@
+package request
+
+type UserSettingsRequest struct {
+	Timezone string `json:"timezone" binding:"required,max=64"` // nama IANA, misalnya Asia/Jakarta
+	Locale   string `json:"locale" binding:"required,oneof=en id"`
+	// kosong berarti base currency tidak diubah
+	BaseCurrency string `json:"base_currency" binding:"omitempty,len=3"`
+}
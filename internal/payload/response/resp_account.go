@@ -0,0 +1,31 @@
+package response
+
+import "time"
+
+type AccountResponse struct {
+	ID             uint      `json:"id"`
+	Name           string    `json:"name"`
+	Type           string    `json:"type"`
+	Currency       string    `json:"currency"`
+	OpeningBalance float64   `json:"opening_balance"`
+	Balance        float64   `json:"balance"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type TransferResponse struct {
+	ID            uint      `json:"id"`
+	FromAccountID uint      `json:"from_account_id"`
+	FromAccount   string    `json:"from_account"`
+	ToAccountID   uint      `json:"to_account_id"`
+	ToAccount     string    `json:"to_account"`
+	Amount        float64   `json:"amount"`
+	Description   string    `json:"description"`
+	Date          time.Time `json:"date"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type TransferListResponse struct {
+	Transfers  []TransferResponse `json:"transfers"`
+	Pagination Pagination         `json:"pagination"`
+}
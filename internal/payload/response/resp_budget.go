@@ -0,0 +1,23 @@
+package response
+
+import "time"
+
+type BudgetResponse struct {
+	ID                    uint    `json:"id"`
+	CategoryID            uint    `json:"category_id"`
+	CategoryName          string  `json:"category_name"`
+	Limit                 float64 `json:"limit"`
+	Spent                 float64 `json:"spent"`
+	Remaining             float64 `json:"remaining"`
+	UtilizationPercentage float64 `json:"utilization_percentage"`
+	Overspent             bool    `json:"overspent"`
+}
+
+type BudgetListResponse struct {
+	PeriodStart    time.Time        `json:"period_start"`
+	PeriodEnd      time.Time        `json:"period_end"`
+	TotalLimit     float64          `json:"total_limit"`
+	TotalSpent     float64          `json:"total_spent"`
+	TotalRemaining float64          `json:"total_remaining"`
+	Budgets        []BudgetResponse `json:"budgets"`
+}
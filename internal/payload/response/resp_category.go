@@ -9,7 +9,8 @@ type CategoryResponse struct {
 	IconColor       string    `json:"icon_color"`
 	UsageCount      int64     `json:"usage_count"`
 	UsagePercentage float64   `json:"usage_percentage"`
-	UserID          uint      `json:"user_id"`
+	UserID          uint      `json:"user_id"` // member yang membuat kategori
+	LedgerID        uint      `json:"ledger_id"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 	DeletedAt       time.Time `json:"deleted_at,omitempty"`
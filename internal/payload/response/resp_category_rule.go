@@ -0,0 +1,23 @@
+package response
+
+import "time"
+
+type CategoryRuleResponse struct {
+	ID                  uint      `json:"id"`
+	CategoryID          uint      `json:"category_id"`
+	CategoryName        string    `json:"category_name"`
+	Name                string    `json:"name"`
+	Priority            int       `json:"priority"`
+	DescriptionContains string    `json:"description_contains"`
+	DescriptionPattern  string    `json:"description_pattern"`
+	MinAmount           *float64  `json:"min_amount"`
+	MaxAmount           *float64  `json:"max_amount"`
+	Type                string    `json:"type"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+type ApplyCategoryRulesResponse struct {
+	Checked int64 `json:"checked"`
+	Updated int64 `json:"updated"`
+}
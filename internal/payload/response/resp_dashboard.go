@@ -2,10 +2,22 @@ package response
 
 // Financial Overview
 type RespFinancialOverview struct {
-	CurrentBalance float64 `json:"current_balance"`
-	MonthlyIncome  float64 `json:"monthly_income"`
-	MonthlyExpense float64 `json:"monthly_expense"`
-	TotalSavings   float64 `json:"total_savings"`
+	Currency       string                `json:"currency"` // base currency user, semua nominal dalam mata uang ini
+	CurrentBalance float64               `json:"current_balance"`
+	MonthlyIncome  float64               `json:"monthly_income"`
+	MonthlyExpense float64               `json:"monthly_expense"`
+	TotalSavings   float64               `json:"total_savings"`
+	Accounts       []AccountBalance      `json:"accounts"`
+	SavingsGoals   []SavingsGoalResponse `json:"savings_goals"`
+}
+
+type AccountBalance struct {
+	ID          uint    `json:"id"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Currency    string  `json:"currency"`
+	Balance     float64 `json:"balance"`      // dalam mata uang account
+	BaseBalance float64 `json:"base_balance"` // dalam base currency user
 }
 
 // Expense Analysis
@@ -37,8 +49,33 @@ type TopExpenses struct {
 	} `json:"datasets"`
 }
 
+// Budget Utilization, spent vs limit per kategori untuk bulan berjalan
+type BudgetUtilization struct {
+	Labels   []string       `json:"labels"`
+	Datasets []ChartDataset `json:"datasets"`
+}
+
+// Cash Flow Forecast, saldo akhir bulan aktual diikuti proyeksi saldo.
+// Nilai null berarti dataset tidak punya titik di bulan tersebut.
+type CashFlowForecast struct {
+	Labels            []string          `json:"labels"`
+	Datasets          []ForecastDataset `json:"datasets"`
+	AverageMonthlyNet float64           `json:"average_monthly_net"` // rata-rata net non-recurring historis
+	MonthlyNetStdDev  float64           `json:"monthly_net_std_dev"`
+}
+
+type ForecastDataset struct {
+	Label           string     `json:"label"`
+	Data            []*float64 `json:"data"`
+	BorderColor     string     `json:"border_color,omitempty"`
+	BackgroundColor string     `json:"background_color,omitempty"`
+}
+
 type RespDashboardCharts struct {
+	Currency             string               `json:"currency"` // base currency user
 	IncomeVsExpense      RespIncomeVsExpense  `json:"income_vs_expense"`
 	CategoryDistribution CategoryDistribution `json:"category_distribution"`
 	TopExpenses          TopExpenses          `json:"top_expenses"`
+	BudgetUtilization    BudgetUtilization    `json:"budget_utilization"`
+	CashFlowForecast     CashFlowForecast     `json:"cash_flow_forecast"`
 }
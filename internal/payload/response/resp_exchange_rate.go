@@ -0,0 +1,13 @@
+package response
+
+import "time"
+
+type ExchangeRateResponse struct {
+	ID           uint      `json:"id"`
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	Rate         float64   `json:"rate"`
+	Date         time.Time `json:"date"`
+	Source       string    `json:"source"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
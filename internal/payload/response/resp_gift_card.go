@@ -0,0 +1,34 @@
+package response
+
+import "time"
+
+type GiftCardResponse struct {
+	ID             uint       `json:"id"`
+	Code           string     `json:"code,omitempty"`
+	MaskedCode     string     `json:"masked_code"`
+	InitialBalance float64    `json:"initial_balance"`
+	Balance        float64    `json:"balance"`
+	Note           string     `json:"note,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Disabled       bool       `json:"disabled"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type GiftCardListResponse struct {
+	GiftCards  []GiftCardResponse `json:"gift_cards"`
+	Pagination Pagination         `json:"pagination"`
+}
+
+type GiftCardBalanceResponse struct {
+	MaskedCode string     `json:"masked_code"`
+	Balance    float64    `json:"balance"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	Usable     bool       `json:"usable"`
+}
+
+type GiftCardRedemptionResponse struct {
+	MaskedCode       string  `json:"masked_code"`
+	AppliedAmount    float64 `json:"applied_amount"`
+	RemainingBalance float64 `json:"remaining_balance"`
+	Reference        string  `json:"reference"`
+}
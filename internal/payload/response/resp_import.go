@@ -0,0 +1,38 @@
+package response
+
+type ImportPreviewRow struct {
+	Index                  int     `json:"index"`
+	Date                   string  `json:"date,omitempty"`
+	Amount                 float64 `json:"amount"`
+	Type                   string  `json:"type,omitempty"`
+	Description            string  `json:"description"`
+	CategoryID             uint    `json:"category_id,omitempty"` // saran dari category rule
+	Duplicate              bool    `json:"duplicate"`
+	DuplicateTransactionID uint    `json:"duplicate_transaction_id,omitempty"`
+	DuplicateOfIndex       *int    `json:"duplicate_of_index,omitempty"` // duplikat baris lain di file yang sama
+	Error                  string  `json:"error,omitempty"`
+}
+
+type ImportPreviewResponse struct {
+	Format     string             `json:"format"`
+	Headers    []string           `json:"headers,omitempty"`     // hanya untuk langkah mapping CSV
+	SampleRows [][]string         `json:"sample_rows,omitempty"` // hanya untuk langkah mapping CSV
+	Rows       []ImportPreviewRow `json:"rows"`
+	TotalRows  int                `json:"total_rows"`
+	Duplicates int                `json:"duplicates"`
+	Invalid    int                `json:"invalid"`
+}
+
+type ImportRowResult struct {
+	Index         int    `json:"index"`
+	Status        string `json:"status"` // created, duplicate atau failed
+	TransactionID uint   `json:"transaction_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+type ImportCommitResponse struct {
+	Created    int               `json:"created"`
+	Duplicates int               `json:"duplicates"`
+	Failed     int               `json:"failed"`
+	Results    []ImportRowResult `json:"results"`
+}
@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+// InsightResponse adalah satu item insights feed, Amount dan Baseline dalam base currency
+type InsightResponse struct {
+	ID            uint       `json:"id"`
+	Kind          string     `json:"kind"`
+	Severity      string     `json:"severity"`
+	Message       string     `json:"message"`
+	CategoryID    *uint      `json:"category_id"`
+	TransactionID *uint      `json:"transaction_id"`
+	Period        string     `json:"period"` // format 2006-01
+	Amount        float64    `json:"amount"`
+	Baseline      float64    `json:"baseline"`
+	Currency      string     `json:"currency"`
+	DismissedAt   *time.Time `json:"dismissed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
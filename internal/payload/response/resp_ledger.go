@@ -0,0 +1,68 @@
+package response
+
+import "time"
+
+type LedgerResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Personal    bool      `json:"personal"`
+	OwnerID     uint      `json:"owner_id"`
+	Role        string    `json:"role"` // role user yang login
+	MemberCount int64     `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// LedgerDetailResponse berisi member ledger, Invitations hanya diisi untuk owner
+type LedgerDetailResponse struct {
+	LedgerResponse
+	Members     []LedgerMemberResponse     `json:"members"`
+	Invitations []LedgerInvitationResponse `json:"invitations,omitempty"`
+}
+
+type LedgerMemberResponse struct {
+	UserID   uint      `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type LedgerInvitationResponse struct {
+	ID        uint      `json:"id"`
+	LedgerID  uint      `json:"ledger_id"`
+	Ledger    string    `json:"ledger"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LedgerSummaryResponse adalah dashboard satu ledger untuk satu bulan, semua nominal dalam base
+// currency user yang login
+type LedgerSummaryResponse struct {
+	LedgerID    uint                  `json:"ledger_id"`
+	Month       string                `json:"month"` // format 2006-01
+	Label       string                `json:"label"`
+	PeriodStart time.Time             `json:"period_start"`
+	PeriodEnd   time.Time             `json:"period_end"`
+	Currency    string                `json:"currency"`
+	Income      float64               `json:"income"`
+	Expense     float64               `json:"expense"`
+	Net         float64               `json:"net"`
+	Members     []LedgerMemberTotal   `json:"members"`
+	Categories  []LedgerCategoryTotal `json:"categories"`
+}
+
+type LedgerMemberTotal struct {
+	UserID       uint    `json:"user_id"`
+	Name         string  `json:"name"`
+	Income       float64 `json:"income"`
+	Expense      float64 `json:"expense"`
+	Transactions int64   `json:"transactions"`
+}
+
+type LedgerCategoryTotal struct {
+	Category string  `json:"category"`
+	Type     string  `json:"type"`
+	Amount   float64 `json:"amount"`
+}
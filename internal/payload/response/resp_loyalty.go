@@ -0,0 +1,31 @@
+package response
+
+import "time"
+
+type LoyaltyBalanceResponse struct {
+	Points         int64   `json:"points"`
+	RedeemValue    float64 `json:"redeem_value"`
+	PointValue     float64 `json:"point_value"`
+	RupiahPerPoint float64 `json:"rupiah_per_point"`
+}
+
+type LoyaltyLedgerEntryResponse struct {
+	ID          uint       `json:"id"`
+	Type        string     `json:"type"`
+	Points      int64      `json:"points"`
+	Balance     int64      `json:"balance"`
+	Reference   string     `json:"reference,omitempty"`
+	Description string     `json:"description"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type LoyaltyLedgerResponse struct {
+	Entries    []LoyaltyLedgerEntryResponse `json:"entries"`
+	Pagination Pagination                   `json:"pagination"`
+}
+
+type LoyaltyRedemptionResponse struct {
+	Entry    LoyaltyLedgerEntryResponse `json:"entry"`
+	Discount float64                    `json:"discount"`
+}
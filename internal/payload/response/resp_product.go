@@ -8,6 +8,7 @@ type ProductResponse struct {
 	Category  string    `json:"category"`
 	Name      string    `json:"name"`
 	Price     float64   `json:"price"`
+	Stock     *int      `json:"stock"` // null berarti stok tidak dilacak
 	ImageLink string    `json:"image_link"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -17,3 +18,29 @@ type ProductListResponse struct {
 	Products   []ProductResponse `json:"products"`
 	Pagination Pagination        `json:"pagination"`
 }
+
+type PriceHistoryResponse struct {
+	OldPrice  float64   `json:"old_price"`
+	NewPrice  float64   `json:"new_price"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type PriceAlertResponse struct {
+	ID           uint       `json:"id"`
+	ProductID    uint       `json:"product_id"`
+	ProductName  string     `json:"product_name"`
+	CurrentPrice float64    `json:"current_price"`
+	TargetPrice  float64    `json:"target_price"`
+	Active       bool       `json:"active"`
+	TriggeredAt  *time.Time `json:"triggered_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type StockSubscriptionResponse struct {
+	ID          uint       `json:"id"`
+	ProductID   uint       `json:"product_id"`
+	ProductName string     `json:"product_name"`
+	Stock       *int       `json:"stock"`
+	NotifiedAt  *time.Time `json:"notified_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,30 @@
+package response
+
+import "time"
+
+type RecentlyViewedProduct struct {
+	Product  ProductResponse `json:"product"`
+	ViewedAt time.Time       `json:"viewed_at"`
+}
+
+type RecentlyViewedResponse struct {
+	Products []RecentlyViewedProduct `json:"products"`
+}
+
+type DailyViewCount struct {
+	Date  string `json:"date"`
+	Views int64  `json:"views"`
+}
+
+type ProductViewAnalytics struct {
+	ProductID   uint             `json:"product_id"`
+	ProductName string           `json:"product_name"`
+	TotalViews  int64            `json:"total_views"`
+	Daily       []DailyViewCount `json:"daily"`
+}
+
+type ProductViewAnalyticsResponse struct {
+	From     string                 `json:"from"`
+	To       string                 `json:"to"`
+	Products []ProductViewAnalytics `json:"products"`
+}
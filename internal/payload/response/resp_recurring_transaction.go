@@ -0,0 +1,36 @@
+package response
+
+import "time"
+
+type RecurringTransactionResponse struct {
+	ID              uint       `json:"id"`
+	CategoryID      uint       `json:"category_id"`
+	Category        string     `json:"category"`
+	AccountID       *uint      `json:"account_id,omitempty"`
+	Amount          float64    `json:"amount"`
+	Type            string     `json:"type"`
+	Description     string     `json:"description"`
+	Frequency       string     `json:"frequency"`
+	Interval        int        `json:"interval"`
+	StartDate       time.Time  `json:"start_date"`
+	EndDate         *time.Time `json:"end_date,omitempty"`
+	MaxOccurrences  *int       `json:"max_occurrences,omitempty"`
+	OccurrenceCount int        `json:"occurrence_count"`
+	NextRunDate     *time.Time `json:"next_run_date,omitempty"`
+	Active          bool       `json:"active"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type RecurringOccurrenceResponse struct {
+	Date          time.Time `json:"date"`
+	Status        string    `json:"status"` // upcoming, scheduled, skipped atau posted
+	Amount        float64   `json:"amount"`
+	Description   string    `json:"description"`
+	TransactionID *uint     `json:"transaction_id,omitempty"`
+}
+
+type RecurringOccurrenceListResponse struct {
+	Upcoming []RecurringOccurrenceResponse `json:"upcoming"`
+	Recent   []RecurringOccurrenceResponse `json:"recent"`
+}
@@ -0,0 +1,60 @@
+package response
+
+import "time"
+
+// MonthlyReportResponse adalah laporan keuangan satu bulan, semua nominal dalam base currency
+// kecuali ReportExpense.Amount
+type MonthlyReportResponse struct {
+	Month          string                `json:"month"` // format 2006-01
+	Label          string                `json:"label"` // nama bulan sesuai locale user, mis. "Jan 2025"
+	PeriodStart    time.Time             `json:"period_start"`
+	PeriodEnd      time.Time             `json:"period_end"`
+	Currency       string                `json:"currency"`
+	OpeningBalance float64               `json:"opening_balance"`
+	ClosingBalance float64               `json:"closing_balance"`
+	Income         float64               `json:"income"`
+	Expense        float64               `json:"expense"`
+	Net            float64               `json:"net"`
+	Categories     []ReportCategoryTotal `json:"categories"`
+	TopExpenses    []ReportExpense       `json:"top_expenses"`
+	Budgets        []BudgetResponse      `json:"budgets"`
+	Comparison     ReportComparison      `json:"comparison"`
+}
+
+type ReportCategoryTotal struct {
+	Category       string  `json:"category"`
+	Type           string  `json:"type"`
+	Amount         float64 `json:"amount"`
+	PreviousAmount float64 `json:"previous_amount"`
+}
+
+type ReportExpense struct {
+	TransactionID uint      `json:"transaction_id"`
+	Date          time.Time `json:"date"`
+	Category      string    `json:"category"`
+	Description   string    `json:"description"`
+	Amount        float64   `json:"amount"` // dalam mata uang transaksi
+	Currency      string    `json:"currency"`
+	BaseAmount    float64   `json:"base_amount"`
+}
+
+// ReportComparison membandingkan bulan laporan dengan bulan sebelumnya. Perubahan dalam persen,
+// nil jika nilai bulan sebelumnya 0.
+type ReportComparison struct {
+	PreviousMonth   string   `json:"previous_month"`
+	PreviousLabel   string   `json:"previous_label"`
+	PreviousIncome  float64  `json:"previous_income"`
+	PreviousExpense float64  `json:"previous_expense"`
+	PreviousNet     float64  `json:"previous_net"`
+	IncomeChange    *float64 `json:"income_change"`
+	ExpenseChange   *float64 `json:"expense_change"`
+}
+
+type ReportScheduleResponse struct {
+	ID         uint       `json:"id"`
+	Format     string     `json:"format"`
+	DayOfMonth int        `json:"day_of_month"`
+	Enabled    bool       `json:"enabled"`
+	LastPeriod *time.Time `json:"last_period"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
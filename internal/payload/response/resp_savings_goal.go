@@ -0,0 +1,30 @@
+package response
+
+import "time"
+
+type SavingsGoalResponse struct {
+	ID                          uint       `json:"id"`
+	Name                        string     `json:"name"`
+	TargetAmount                float64    `json:"target_amount"`
+	TargetDate                  *time.Time `json:"target_date"`
+	StartDate                   time.Time  `json:"start_date"`
+	AccountID                   *uint      `json:"account_id"`
+	CategoryID                  *uint      `json:"category_id"`
+	SavedAmount                 float64    `json:"saved_amount"`
+	RemainingAmount             float64    `json:"remaining_amount"`
+	ProgressPercentage          float64    `json:"progress_percentage"`
+	Completed                   bool       `json:"completed"`
+	MonthlyContributionRate     float64    `json:"monthly_contribution_rate"`
+	RequiredMonthlyContribution float64    `json:"required_monthly_contribution"`
+	ProjectedCompletionDate     *time.Time `json:"projected_completion_date"`
+	OnTrack                     *bool      `json:"on_track"` // null jika goal tidak punya target date
+}
+
+type SavingsContributionResponse struct {
+	ID        uint      `json:"id"`
+	GoalID    uint      `json:"goal_id"`
+	Amount    float64   `json:"amount"`
+	Date      time.Time `json:"date"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
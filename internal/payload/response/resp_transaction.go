@@ -3,17 +3,44 @@ package response
 import "time"
 
 type TransactionResponse struct {
+	ID            uint      `json:"id"`
+	LedgerID      uint      `json:"ledger_id"`
+	CreatedBy     uint      `json:"created_by"`                // member yang mencatat transaksi
+	CreatedByName string    `json:"created_by_name,omitempty"` // hanya diisi pada list transaksi ledger
+	CategoryID    uint      `json:"category_id"`
+	Category      string    `json:"category"`
+	AccountID     *uint     `json:"account_id,omitempty"`
+	Account       string    `json:"account,omitempty"`
+	Amount        float64   `json:"amount"`
+	Currency      string    `json:"currency"`
+	Type          string    `json:"type"`
+	Description   string    `json:"description"`
+	Date          time.Time `json:"date"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	Splits      []TransactionSplitResponse `json:"splits,omitempty"`
+	Attachments []AttachmentResponse       `json:"attachments,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+}
+
+type AttachmentResponse struct {
 	ID          uint      `json:"id"`
-	CategoryID  uint      `json:"category_id"`
-	Category    string    `json:"category"`
-	Amount      float64   `json:"amount"`
-	Type        string    `json:"type"`
-	Description string    `json:"description"`
-	Date        time.Time `json:"date"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+type TransactionSplitResponse struct {
+	ID          uint    `json:"id"`
+	CategoryID  uint    `json:"category_id"`
+	Category    string  `json:"category"`
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description"`
+}
+
+// TransactionSummary dalam base currency user
 type TransactionSummary struct {
 	TotalIncome  float64 `json:"total_income"`
 	TotalExpense float64 `json:"total_expense"`
@@ -25,3 +52,27 @@ type TransactionListResponse struct {
 	Summary      TransactionSummary    `json:"summary"`
 	Pagination   Pagination            `json:"pagination"`
 }
+
+// BulkTransactionResponse adalah ringkasan hasil bulk action. Untuk add_tags dan remove_tags
+// Updated adalah jumlah relasi tag yang ditambah atau dihapus.
+type BulkTransactionResponse struct {
+	Action  string `json:"action"`
+	Matched int64  `json:"matched"`
+	Updated int64  `json:"updated"`
+	Skipped int64  `json:"skipped"` // transaksi split pada recategorize, kategorinya mengikuti split
+}
+
+type TagResponse struct {
+	ID               uint   `json:"id"`
+	Name             string `json:"name"`
+	TransactionCount int64  `json:"transaction_count"`
+}
+
+type TagSummary struct {
+	TagID            uint    `json:"tag_id"`
+	Tag              string  `json:"tag"`
+	TransactionCount int64   `json:"transaction_count"`
+	TotalIncome      float64 `json:"total_income"`
+	TotalExpense     float64 `json:"total_expense"`
+	Balance          float64 `json:"balance"`
+}
@@ -0,0 +1,25 @@
+package response
+
+import "time"
+
+// TrashedTransactionResponse adalah transaksi di trash, PurgeAt adalah waktu transaksi
+// dihapus permanen oleh retention job
+type TrashedTransactionResponse struct {
+	TransactionResponse
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+type TrashedTransactionListResponse struct {
+	Transactions []TrashedTransactionResponse `json:"transactions"`
+	Pagination   Pagination                   `json:"pagination"`
+}
+
+type TrashedCategoryResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	IconColor string    `json:"icon_color"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
@@ -0,0 +1,7 @@
+package response
+
+type UserSettingsResponse struct {
+	Timezone     string `json:"timezone"`
+	Locale       string `json:"locale"`
+	BaseCurrency string `json:"base_currency"`
+}
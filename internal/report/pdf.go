@@ -0,0 +1,355 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"go-electroshop/internal/payload/response"
+	"io"
+	"math"
+	"strings"
+)
+
+// ukuran A4 dalam point
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	pageMargin = 48.0
+	lineHeight = 16.0
+)
+
+// WritePDF membuat r sebagai laporan A4 yang siap dicetak. Grafik digambar sebagai bar
+// horizontal di samping angkanya.
+func WritePDF(w io.Writer, r *response.MonthlyReportResponse, locale string) error {
+	doc := newPDFDocument()
+	amount := func(value float64) string { return FormatAmount(value, locale) }
+
+	doc.text(pageMargin, doc.y, 18, true, "Monthly Report "+r.Label)
+	doc.y -= 20
+	doc.text(pageMargin, doc.y, 10, false, fmt.Sprintf("Period %s - %s, amounts in %s",
+		r.PeriodStart.Format("2006-01-02"), r.PeriodEnd.Format("2006-01-02"), r.Currency))
+	doc.y -= 28
+
+	doc.heading("Summary")
+	doc.table([]pdfColumn{{width: 200}, {width: 140, right: true}}, [][]string{
+		{"Opening balance", amount(r.OpeningBalance)},
+		{"Income", amount(r.Income)},
+		{"Expense", amount(r.Expense)},
+		{"Net", amount(r.Net)},
+		{"Closing balance", amount(r.ClosingBalance)},
+	})
+
+	doc.heading("Month over month")
+	doc.table([]pdfColumn{
+		{title: "", width: 120},
+		{title: r.Label, width: 130, right: true},
+		{title: r.Comparison.PreviousLabel, width: 130, right: true},
+		{title: "Change", width: 80, right: true},
+	}, [][]string{
+		{"Income", amount(r.Income), amount(r.Comparison.PreviousIncome), formatChange(r.Comparison.IncomeChange)},
+		{"Expense", amount(r.Expense), amount(r.Comparison.PreviousExpense), formatChange(r.Comparison.ExpenseChange)},
+		{"Net", amount(r.Net), amount(r.Comparison.PreviousNet), ""},
+	})
+
+	for _, section := range []struct{ categoryType, title string }{{"income", "Income by category"}, {"expense", "Expense by category"}} {
+		categoryType := section.categoryType
+		var rows [][]string
+		var values []float64
+		for _, category := range r.Categories {
+			if category.Type == categoryType {
+				rows = append(rows, []string{category.Category, amount(category.Amount), amount(category.PreviousAmount)})
+				values = append(values, category.Amount)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		doc.heading(section.title)
+		doc.barTable([]pdfColumn{
+			{title: "Category", width: 150},
+			{title: r.Label, width: 100, right: true},
+			{title: r.Comparison.PreviousLabel, width: 100, right: true},
+		}, rows, values, categoryType == "expense")
+	}
+
+	if len(r.TopExpenses) > 0 {
+		doc.heading("Top expenses")
+		rows := make([][]string, len(r.TopExpenses))
+		for i, expense := range r.TopExpenses {
+			original := ""
+			if expense.Currency != r.Currency {
+				original = expense.Currency + " " + amount(expense.Amount)
+			}
+			rows[i] = []string{expense.Date.Format("2006-01-02"), expense.Category, expense.Description, original, amount(expense.BaseAmount)}
+		}
+		doc.table([]pdfColumn{
+			{title: "Date", width: 70},
+			{title: "Category", width: 90},
+			{title: "Description", width: 150},
+			{title: "Original", width: 90, right: true},
+			{title: "Amount", width: 95, right: true},
+		}, rows)
+	}
+
+	if len(r.Budgets) > 0 {
+		doc.heading("Budget status")
+		rows := make([][]string, len(r.Budgets))
+		values := make([]float64, len(r.Budgets))
+		for i, budget := range r.Budgets {
+			rows[i] = []string{budget.CategoryName, amount(budget.Limit), amount(budget.Spent), fmt.Sprintf("%.1f%%", budget.UtilizationPercentage)}
+			values[i] = budget.UtilizationPercentage
+		}
+		doc.budgetTable([]pdfColumn{
+			{title: "Category", width: 130},
+			{title: "Limit", width: 90, right: true},
+			{title: "Spent", width: 90, right: true},
+			{title: "Used", width: 60, right: true},
+		}, rows, values)
+	}
+
+	return doc.writeTo(w)
+}
+
+type pdfColumn struct {
+	title string
+	width float64
+	right bool
+}
+
+// pdfDocument adalah writer PDF 1.4 minimal untuk teks dan kotak berwarna dengan font
+// Helvetica bawaan, sehingga tidak butuh file font atau library PDF. y adalah baseline
+// baris berikutnya, dihitung dari bawah halaman.
+type pdfDocument struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+func newPDFDocument() *pdfDocument {
+	doc := &pdfDocument{}
+	doc.addPage()
+	return doc
+}
+
+func (d *pdfDocument) addPage() {
+	d.page = new(bytes.Buffer)
+	d.pages = append(d.pages, d.page)
+	d.y = pageHeight - pageMargin
+}
+
+// ensureSpace membuat halaman baru jika height tidak muat lagi di atas margin bawah
+func (d *pdfDocument) ensureSpace(height float64) {
+	if d.y-height < pageMargin {
+		d.addPage()
+	}
+}
+
+func (d *pdfDocument) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(s))
+}
+
+func (d *pdfDocument) cell(x, size float64, bold bool, column pdfColumn, s string) {
+	s = truncateText(s, column.width-6, size)
+	if column.right {
+		x += column.width - 6 - textWidth(s, size)
+	}
+	d.text(x, d.y, size, bold, s)
+}
+
+func (d *pdfDocument) rect(x, y, width, height float64, color [3]float64) {
+	// q/Q supaya warna isi tidak terbawa ke teks yang digambar setelahnya
+	fmt.Fprintf(d.page, "q %.2f %.2f %.2f rg %.2f %.2f %.2f %.2f re f Q\n", color[0], color[1], color[2], x, y, width, height)
+}
+
+func (d *pdfDocument) heading(title string) {
+	d.ensureSpace(lineHeight * 4)
+	d.y -= 6
+	d.text(pageMargin, d.y, 13, true, title)
+	d.rect(pageMargin, d.y-5, pageWidth-2*pageMargin, 0.8, [3]float64{0.6, 0.6, 0.6})
+	d.y -= lineHeight + 6
+}
+
+// row menulis satu baris tabel dan mengembalikan posisi x setelah kolom terakhir
+func (d *pdfDocument) row(columns []pdfColumn, values []string, bold bool) float64 {
+	x := pageMargin
+	for i, column := range columns {
+		if i < len(values) {
+			d.cell(x, 10, bold, column, values[i])
+		}
+		x += column.width
+	}
+	return x
+}
+
+func (d *pdfDocument) tableHeader(columns []pdfColumn) {
+	for _, column := range columns {
+		if column.title != "" {
+			d.row(columns, columnTitles(columns), true)
+			d.y -= lineHeight
+			return
+		}
+	}
+}
+
+func (d *pdfDocument) table(columns []pdfColumn, rows [][]string) {
+	d.tableHeader(columns)
+	for _, values := range rows {
+		d.ensureSpace(lineHeight)
+		d.row(columns, values, false)
+		d.y -= lineHeight
+	}
+	d.y -= lineHeight / 2
+}
+
+// barTable menulis tabel dengan bar di setiap baris, panjangnya relatif terhadap nilai
+// terbesar
+func (d *pdfDocument) barTable(columns []pdfColumn, rows [][]string, values []float64, expense bool) {
+	color := [3]float64{0.30, 0.62, 0.36}
+	if expense {
+		color = [3]float64{0.84, 0.36, 0.32}
+	}
+
+	var max float64
+	for _, value := range values {
+		max = math.Max(max, value)
+	}
+
+	d.tableHeader(columns)
+	for i, row := range rows {
+		d.ensureSpace(lineHeight)
+		x := d.row(columns, row, false)
+		if max > 0 && values[i] > 0 {
+			d.rect(x, d.y-1, (pageWidth-pageMargin-x)*values[i]/max, 9, color)
+		}
+		d.y -= lineHeight
+	}
+	d.y -= lineHeight / 2
+}
+
+// budgetTable menulis baris budget dengan bar pemakaian, merah jika budget sudah
+// terlampaui
+func (d *pdfDocument) budgetTable(columns []pdfColumn, rows [][]string, utilization []float64) {
+	d.tableHeader(columns)
+	for i, row := range rows {
+		d.ensureSpace(lineHeight)
+		x := d.row(columns, row, false)
+		width := pageWidth - pageMargin - x
+
+		color := [3]float64{0.30, 0.62, 0.36}
+		if utilization[i] > 100 {
+			color = [3]float64{0.84, 0.36, 0.32}
+		}
+		d.rect(x, d.y-1, width, 9, [3]float64{0.92, 0.92, 0.92})
+		d.rect(x, d.y-1, width*math.Min(math.Max(utilization[i], 0), 100)/100, 9, color)
+		d.y -= lineHeight
+	}
+	d.y -= lineHeight / 2
+}
+
+func (d *pdfDocument) writeTo(w io.Writer) error {
+	var out bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// object 1-4 tetap, setiap halaman menambah object page dan content stream-nya
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		fmt.Fprintf(page, "BT /F1 8.0 Tf %.2f %.2f Td (%s) Tj ET\n", pageWidth-pageMargin-textWidth(footer, 8), pageMargin/2, footer)
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := out.WriteTo(w)
+	return err
+}
+
+func columnTitles(columns []pdfColumn) []string {
+	titles := make([]string, len(columns))
+	for i, column := range columns {
+		titles[i] = column.title
+	}
+	return titles
+}
+
+// pdfEscape meng-encode s untuk string literal PDF dalam WinAnsiEncoding. Karakter di luar
+// Latin-1 tidak tersedia di font standar dan diganti "?".
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// helveticaWidths adalah lebar glyph Helvetica untuk ASCII 32-126 dalam 1/1000 em
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth memperkirakan lebar s dalam point, teks bold sedikit lebih lebar tapi cukup
+// dekat untuk merapikan kolom
+func textWidth(s string, size float64) float64 {
+	var width int
+	for _, r := range s {
+		if r >= 32 && r < 127 {
+			width += helveticaWidths[r-32]
+		} else {
+			width += 556
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// truncateText memendekkan s dengan "..." supaya muat di width
+func truncateText(s string, width, size float64) string {
+	if textWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
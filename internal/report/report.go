@@ -0,0 +1,81 @@
+// Package report membuat laporan keuangan bulanan dalam bentuk workbook XLSX atau
+// dokumen PDF.
+package report
+
+import (
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/response"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	ContentTypePDF  = "application/pdf"
+)
+
+// Write membuat r dalam format yang diminta. Nominal di PDF diformat sesuai locale,
+// di workbook tetap berupa angka.
+func Write(w io.Writer, r *response.MonthlyReportResponse, format, locale string) error {
+	switch format {
+	case FormatXLSX:
+		return WriteXLSX(w, r)
+	case FormatPDF:
+		return WritePDF(w, r, locale)
+	}
+	return errors.New("unsupported report format: " + format)
+}
+
+func ContentType(format string) string {
+	if format == FormatPDF {
+		return ContentTypePDF
+	}
+	return ContentTypeXLSX
+}
+
+// Filename mengembalikan nama file download laporan, misalnya "report_2025-01.pdf"
+func Filename(month, format string) string {
+	return fmt.Sprintf("report_%s.%s", month, format)
+}
+
+// FormatAmount memformat value dengan dua desimal dan pemisah ribuan, locale "id"
+// memakai 1.234,56 dan locale lain 1,234.56
+func FormatAmount(value float64, locale string) string {
+	thousands, decimal := ",", "."
+	if locale == "id" {
+		thousands, decimal = ".", ","
+	}
+
+	digits := strconv.FormatFloat(math.Abs(value), 'f', 2, 64)
+	integer, fraction := digits[:len(digits)-3], digits[len(digits)-2:]
+
+	var b strings.Builder
+	if value < 0 && strings.Trim(integer+fraction, "0") != "" {
+		b.WriteByte('-')
+	}
+	for i, c := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+		b.WriteRune(c)
+	}
+	b.WriteString(decimal)
+	b.WriteString(fraction)
+
+	return b.String()
+}
+
+// formatChange memformat perubahan dibanding bulan lalu dalam persen, "-" jika tidak ada
+// pembanding
+func formatChange(change *float64) string {
+	if change == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", *change)
+}
@@ -0,0 +1,268 @@
+package report
+
+import (
+	"fmt"
+	"go-electroshop/internal/payload/response"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	sheetSummary     = "Summary"
+	sheetCategories  = "Categories"
+	sheetTopExpenses = "Top Expenses"
+	sheetBudgets     = "Budgets"
+)
+
+// WriteXLSX membuat r sebagai workbook dengan sheet ringkasan, kategori, pengeluaran
+// terbesar dan budget, masing-masing dengan grafik angkanya
+func WriteXLSX(w io.Writer, r *response.MonthlyReportResponse) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// sheet default dipakai untuk ringkasan supaya terbuka pertama kali
+	if err := f.SetSheetName("Sheet1", sheetSummary); err != nil {
+		return err
+	}
+	for _, sheet := range []string{sheetCategories, sheetTopExpenses, sheetBudgets} {
+		if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+	}
+
+	styles, err := newXLSXStyles(f)
+	if err != nil {
+		return err
+	}
+
+	writers := []func(*excelize.File, *response.MonthlyReportResponse, xlsxStyles) error{
+		writeSummarySheet, writeCategoriesSheet, writeTopExpensesSheet, writeBudgetsSheet,
+	}
+	for _, write := range writers {
+		if err := write(f, r, styles); err != nil {
+			return err
+		}
+	}
+
+	_, err = f.WriteTo(w)
+	return err
+}
+
+type xlsxStyles struct {
+	title   int
+	header  int
+	amount  int
+	percent int
+}
+
+func newXLSXStyles(f *excelize.File) (xlsxStyles, error) {
+	var styles xlsxStyles
+	var err error
+
+	if styles.title, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}}); err != nil {
+		return styles, err
+	}
+	if styles.header, err = f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+	}); err != nil {
+		return styles, err
+	}
+	if styles.amount, err = f.NewStyle(&excelize.Style{NumFmt: 4}); err != nil { // #,##0.00
+		return styles, err
+	}
+	if styles.percent, err = f.NewStyle(&excelize.Style{NumFmt: 10}); err != nil { // 0.00%
+		return styles, err
+	}
+
+	return styles, nil
+}
+
+// setRow menulis values mulai dari kolom A pada row
+func setRow(f *excelize.File, sheet string, row int, values ...interface{}) error {
+	return f.SetSheetRow(sheet, fmt.Sprintf("A%d", row), &values)
+}
+
+// ratio mengubah perubahan dalam persen menjadi pecahan untuk style persen
+func ratio(change *float64) interface{} {
+	if change == nil {
+		return "-"
+	}
+	return *change / 100
+}
+
+func writeSummarySheet(f *excelize.File, r *response.MonthlyReportResponse, styles xlsxStyles) error {
+	sheet := sheetSummary
+
+	rows := [][]interface{}{
+		{"Monthly Report " + r.Label},
+		{"Period", r.PeriodStart.Format("2006-01-02") + " - " + r.PeriodEnd.Format("2006-01-02")},
+		{"Currency", r.Currency},
+		{},
+		{"Opening Balance", r.OpeningBalance},
+		{"Income", r.Income},
+		{"Expense", r.Expense},
+		{"Net", r.Net},
+		{"Closing Balance", r.ClosingBalance},
+		{},
+		{"Month over Month", r.Label, r.Comparison.PreviousLabel, "Change"},
+		{"Income", r.Income, r.Comparison.PreviousIncome, ratio(r.Comparison.IncomeChange)},
+		{"Expense", r.Expense, r.Comparison.PreviousExpense, ratio(r.Comparison.ExpenseChange)},
+		{"Net", r.Net, r.Comparison.PreviousNet},
+	}
+	for i, values := range rows {
+		if err := setRow(f, sheet, i+1, values...); err != nil {
+			return err
+		}
+	}
+
+	f.SetCellStyle(sheet, "A1", "A1", styles.title)
+	f.SetCellStyle(sheet, "B5", "B9", styles.amount)
+	f.SetCellStyle(sheet, "A11", "D11", styles.header)
+	f.SetCellStyle(sheet, "B12", "C14", styles.amount)
+	f.SetCellStyle(sheet, "D12", "D13", styles.percent)
+	f.SetColWidth(sheet, "A", "A", 20)
+	f.SetColWidth(sheet, "B", "D", 18)
+
+	return f.AddChart(sheet, "F2", &excelize.Chart{
+		Type: excelize.Col,
+		Series: []excelize.ChartSeries{
+			{Name: "'Summary'!$B$11", Categories: "'Summary'!$A$12:$A$13", Values: "'Summary'!$B$12:$B$13"},
+			{Name: "'Summary'!$C$11", Categories: "'Summary'!$A$12:$A$13", Values: "'Summary'!$C$12:$C$13"},
+		},
+		Title:  []excelize.RichTextRun{{Text: "Income vs Expense"}},
+		Legend: excelize.ChartLegend{Position: "bottom"},
+	})
+}
+
+func writeCategoriesSheet(f *excelize.File, r *response.MonthlyReportResponse, styles xlsxStyles) error {
+	sheet := sheetCategories
+
+	if err := setRow(f, sheet, 1, "Type", "Category", r.Label, r.Comparison.PreviousLabel); err != nil {
+		return err
+	}
+	f.SetCellStyle(sheet, "A1", "D1", styles.header)
+
+	// pengeluaran lebih dulu supaya pie chart mencakup satu blok baris yang berurutan
+	row := 1
+	expenseRows := 0
+	for _, categoryType := range []string{"expense", "income"} {
+		for _, category := range r.Categories {
+			if category.Type != categoryType {
+				continue
+			}
+			row++
+			if categoryType == "expense" {
+				expenseRows++
+			}
+			if err := setRow(f, sheet, row, category.Type, category.Category, category.Amount, category.PreviousAmount); err != nil {
+				return err
+			}
+		}
+	}
+
+	if row > 1 {
+		f.SetCellStyle(sheet, "C2", fmt.Sprintf("D%d", row), styles.amount)
+	}
+	f.SetColWidth(sheet, "A", "A", 10)
+	f.SetColWidth(sheet, "B", "B", 24)
+	f.SetColWidth(sheet, "C", "D", 16)
+
+	if expenseRows == 0 {
+		return nil
+	}
+
+	return f.AddChart(sheet, "F2", &excelize.Chart{
+		Type: excelize.Pie,
+		Series: []excelize.ChartSeries{{
+			Name:       "'Categories'!$C$1",
+			Categories: fmt.Sprintf("'Categories'!$B$2:$B$%d", expenseRows+1),
+			Values:     fmt.Sprintf("'Categories'!$C$2:$C$%d", expenseRows+1),
+		}},
+		Title:  []excelize.RichTextRun{{Text: "Expense by Category"}},
+		Legend: excelize.ChartLegend{Position: "right"},
+	})
+}
+
+func writeTopExpensesSheet(f *excelize.File, r *response.MonthlyReportResponse, styles xlsxStyles) error {
+	sheet := sheetTopExpenses
+
+	if err := setRow(f, sheet, 1, "Date", "Category", "Description", "Amount", "Currency", "Amount ("+r.Currency+")"); err != nil {
+		return err
+	}
+	f.SetCellStyle(sheet, "A1", "F1", styles.header)
+
+	for i, expense := range r.TopExpenses {
+		if err := setRow(f, sheet, i+2, expense.Date.Format("2006-01-02"), expense.Category, expense.Description,
+			expense.Amount, expense.Currency, expense.BaseAmount); err != nil {
+			return err
+		}
+	}
+
+	last := len(r.TopExpenses) + 1
+	if last > 1 {
+		f.SetCellStyle(sheet, "D2", fmt.Sprintf("D%d", last), styles.amount)
+		f.SetCellStyle(sheet, "F2", fmt.Sprintf("F%d", last), styles.amount)
+	}
+	f.SetColWidth(sheet, "A", "B", 14)
+	f.SetColWidth(sheet, "C", "C", 32)
+	f.SetColWidth(sheet, "D", "F", 14)
+
+	if last == 1 {
+		return nil
+	}
+
+	return f.AddChart(sheet, "H2", &excelize.Chart{
+		Type: excelize.Bar,
+		Series: []excelize.ChartSeries{{
+			Name:       "'Top Expenses'!$F$1",
+			Categories: fmt.Sprintf("'Top Expenses'!$C$2:$C$%d", last),
+			Values:     fmt.Sprintf("'Top Expenses'!$F$2:$F$%d", last),
+		}},
+		Title:  []excelize.RichTextRun{{Text: "Top Expenses"}},
+		Legend: excelize.ChartLegend{Position: "none"},
+	})
+}
+
+func writeBudgetsSheet(f *excelize.File, r *response.MonthlyReportResponse, styles xlsxStyles) error {
+	sheet := sheetBudgets
+
+	if err := setRow(f, sheet, 1, "Category", "Limit", "Spent", "Remaining", "Utilization", "Status"); err != nil {
+		return err
+	}
+	f.SetCellStyle(sheet, "A1", "F1", styles.header)
+
+	for i, budget := range r.Budgets {
+		status := "On track"
+		if budget.Overspent {
+			status = "Overspent"
+		}
+		if err := setRow(f, sheet, i+2, budget.CategoryName, budget.Limit, budget.Spent, budget.Remaining,
+			budget.UtilizationPercentage/100, status); err != nil {
+			return err
+		}
+	}
+
+	last := len(r.Budgets) + 1
+	if last > 1 {
+		f.SetCellStyle(sheet, "B2", fmt.Sprintf("D%d", last), styles.amount)
+		f.SetCellStyle(sheet, "E2", fmt.Sprintf("E%d", last), styles.percent)
+	}
+	f.SetColWidth(sheet, "A", "A", 24)
+	f.SetColWidth(sheet, "B", "F", 14)
+
+	if last == 1 {
+		return nil
+	}
+
+	return f.AddChart(sheet, "H2", &excelize.Chart{
+		Type: excelize.Col,
+		Series: []excelize.ChartSeries{
+			{Name: "'Budgets'!$B$1", Categories: fmt.Sprintf("'Budgets'!$A$2:$A$%d", last), Values: fmt.Sprintf("'Budgets'!$B$2:$B$%d", last)},
+			{Name: "'Budgets'!$C$1", Categories: fmt.Sprintf("'Budgets'!$A$2:$A$%d", last), Values: fmt.Sprintf("'Budgets'!$C$2:$C$%d", last)},
+		},
+		Title:  []excelize.RichTextRun{{Text: "Budget vs Spent"}},
+		Legend: excelize.ChartLegend{Position: "bottom"},
+	})
+}
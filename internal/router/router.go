@@ -2,9 +2,12 @@ package router
 
 import (
 	"go-electroshop/internal/controller"
+	"go-electroshop/internal/exchangerate"
+	"go-electroshop/internal/notifier"
 	"go-electroshop/internal/payload/response"
 	"go-electroshop/internal/repository"
 	"go-electroshop/internal/service"
+	"go-electroshop/internal/storage"
 	"go-electroshop/middleware"
 	"net/http"
 	"strings"
@@ -12,6 +15,7 @@ import (
 	_ "go-electroshop/docs"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
@@ -30,21 +34,77 @@ func InitRoutes(r *gin.Engine, db *gorm.DB) {
 	categoryService := &service.CategoryService{DB: db}
 	categoryController := &controller.CategoryController{CategoryService: categoryService}
 
+	// init category rule
+	categoryRuleService := service.NewCategoryRuleService(db)
+	categoryRuleController := controller.NewCategoryRuleController(categoryRuleService)
+
+	// init tag
+	tagService := service.NewTagService(db)
+	tagController := controller.NewTagController(tagService)
+
+	// init account
+	accountService := service.NewAccountService(db)
+	transferService := service.NewTransferService(db)
+	accountController := controller.NewAccountController(accountService, transferService)
+
+	// init budget
+	budgetService := service.NewBudgetService(db)
+	budgetController := controller.NewBudgetController(budgetService)
+
+	// init savings goal
+	savingsGoalService := service.NewSavingsGoalService(db)
+	savingsGoalController := controller.NewSavingsGoalController(savingsGoalService)
+
+	// init exchange rate
+	rateProvider, err := exchangerate.NewFromEnv()
+	if err != nil {
+		logrus.Fatalf("Failed to init exchange rate provider: %v", err)
+	}
+	exchangeRateController := controller.NewExchangeRateController(service.NewExchangeRateService(db, rateProvider))
+
 	// init transaction
-	transactionService := &service.TransactionService{DB: db}
+	transactionService := &service.TransactionService{DB: db, RateProvider: rateProvider}
 	transactionController := &controller.TransactionController{TransactionService: transactionService}
+	attachmentStorage, err := storage.NewFromEnv()
+	if err != nil {
+		logrus.Fatalf("Failed to init attachment storage: %v", err)
+	}
+	attachmentController := controller.NewAttachmentController(service.NewAttachmentService(db, attachmentStorage))
+	trashController := controller.NewTrashController(service.NewTrashService(db, attachmentStorage))
+	recurringTransactionService := service.NewRecurringTransactionService(db)
+	recurringTransactionController := controller.NewRecurringTransactionController(recurringTransactionService)
+
+	// init report
+	reportController := controller.NewReportController(service.NewReportService(db, notifier.NewLogNotifier()))
+
+	// init insight
+	insightController := controller.NewInsightController(service.NewInsightService(db))
+
+	// init ledger
+	ledgerController := controller.NewLedgerController(service.NewLedgerService(db, notifier.NewLogNotifier()))
 
 	// init chat assistant
 	chatAssistant := controller.NewElectroAssistant(db)
 
 	// init product
 	productService := service.NewProductService(db)
-	productController := controller.NewProductController(productService)
+	productViewService := service.NewProductViewService(db)
+	productController := controller.NewProductController(productService, productViewService)
+	priceAlertController := controller.NewPriceAlertController(productService.PriceAlertService)
+	stockSubscriptionController := controller.NewStockSubscriptionController(productService.StockNotificationService)
 
 	// init cart
 	cartRepository := &repository.CartRepository{DB: db}
 	cartController := controller.NewCartController(db, cartRepository)
 
+	// init loyalty
+	loyaltyService := service.NewLoyaltyService(db)
+	loyaltyController := controller.NewLoyaltyController(loyaltyService)
+
+	// init gift card
+	giftCardService := service.NewGiftCardService(db)
+	giftCardController := controller.NewGiftCardController(giftCardService)
+
 	// init admin dashboard controller
 	adminDashboardController := controller.NewAdminDashboardController(productService, userService)
 
@@ -72,10 +132,19 @@ func InitRoutes(r *gin.Engine, db *gorm.DB) {
 
 			// Product management (admin only)
 			adminRouter.GET("/products", productController.GetProductsHandler)
+			adminRouter.GET("/products/views", productController.GetProductViewAnalyticsHandler)
 			adminRouter.GET("/products/:id", productController.GetProductByIDHandler)
 			adminRouter.POST("/products", productController.CreateProductHandler)
 			adminRouter.PUT("/products/:id", productController.UpdateProductHandler)
 			adminRouter.DELETE("/products/:id", productController.DeleteProductHandler)
+
+			// Loyalty points management
+			adminRouter.POST("/loyalty/adjust", loyaltyController.AdjustLoyaltyPointsHandler)
+
+			// Gift card management
+			adminRouter.POST("/gift-cards", giftCardController.IssueGiftCardHandler)
+			adminRouter.GET("/gift-cards", giftCardController.GetGiftCardsHandler)
+			adminRouter.PUT("/gift-cards/:id/disable", giftCardController.DisableGiftCardHandler)
 		}
 
 		// auth endpoint
@@ -92,12 +161,38 @@ func InitRoutes(r *gin.Engine, db *gorm.DB) {
 			}
 		}
 
+		// user settings endpoint
+		userSettingsRouter := api.Group("/user")
+		userSettingsRouter.Use(middleware.Authentication())
+		{
+			userSettingsRouter.GET("/settings", userController.GetUserSettingsHandler)
+			userSettingsRouter.PUT("/settings", userController.UpdateUserSettingsHandler)
+		}
+
 		// Product endpoint (Public)
 		productRouter := api.Group("/product")
 		{
 			productRouter.GET("", productController.GetProductsHandler)
-			productRouter.GET("/:id", productController.GetProductByIDHandler)
+			productRouter.GET("/:id", middleware.OptionalAuthentication(), productController.GetProductByIDHandler)
 			productRouter.GET("/categories", productController.GetProductCategoriesHandler)
+			productRouter.GET("/recently-viewed", middleware.OptionalAuthentication(), productController.GetRecentlyViewedHandler)
+			productRouter.GET("/:id/price-history", productController.GetPriceHistoryHandler)
+			productRouter.POST("/:id/price-alert", middleware.Authentication(), priceAlertController.SubscribePriceAlertHandler)
+			productRouter.DELETE("/:id/price-alert", middleware.Authentication(), priceAlertController.UnsubscribePriceAlertHandler)
+			productRouter.POST("/:id/stock-subscription", middleware.Authentication(), stockSubscriptionController.SubscribeStockHandler)
+			productRouter.DELETE("/:id/stock-subscription", middleware.Authentication(), stockSubscriptionController.UnsubscribeStockHandler)
+		}
+
+		priceAlertRouter := api.Group("/price-alerts")
+		priceAlertRouter.Use(middleware.Authentication())
+		{
+			priceAlertRouter.GET("", priceAlertController.GetPriceAlertsHandler)
+		}
+
+		stockSubscriptionRouter := api.Group("/stock-subscriptions")
+		stockSubscriptionRouter.Use(middleware.Authentication())
+		{
+			stockSubscriptionRouter.GET("", stockSubscriptionController.GetStockSubscriptionsHandler)
 		}
 
 		cartRouter := api.Group("/cart")
@@ -110,6 +205,21 @@ func InitRoutes(r *gin.Engine, db *gorm.DB) {
 			cartRouter.DELETE("", cartController.ClearCartHandler)
 		}
 
+		// loyalty endpoint
+		loyaltyRouter := api.Group("/loyalty")
+		loyaltyRouter.Use(middleware.Authentication())
+		{
+			loyaltyRouter.GET("", loyaltyController.GetLoyaltyBalanceHandler)
+			loyaltyRouter.GET("/ledger", loyaltyController.GetLoyaltyLedgerHandler)
+		}
+
+		// gift card endpoint
+		giftCardRouter := api.Group("/gift-cards")
+		giftCardRouter.Use(middleware.Authentication())
+		{
+			giftCardRouter.POST("/check", giftCardController.CheckGiftCardHandler)
+		}
+
 		// dashboard endpoint
 		dashboardRouter := api.Group("/dashboard")
 		dashboardRouter.Use(middleware.Authentication())
@@ -135,7 +245,29 @@ func InitRoutes(r *gin.Engine, db *gorm.DB) {
 			transactionRouter.POST("", transactionController.CreateTransactionHandler)
 			transactionRouter.PUT("/:id", transactionController.UpdateTransactionHandler)
 			transactionRouter.DELETE("/:id", transactionController.DeleteTransactionHandler)
-			transactionRouter.GET("/export", transactionController.ExportTransactionsExcelHandler)
+			transactionRouter.POST("/bulk", transactionController.BulkTransactionsHandler)
+			transactionRouter.GET("/export", transactionController.ExportTransactionsHandler)
+			transactionRouter.POST("/import", transactionController.ImportTransactionsHandler)
+			transactionRouter.POST("/import/commit", transactionController.CommitImportHandler)
+			transactionRouter.GET("/trash", trashController.GetTrashedTransactionsHandler)
+			transactionRouter.POST("/:id/restore", trashController.RestoreTransactionHandler)
+			transactionRouter.DELETE("/:id/purge", trashController.PurgeTransactionHandler)
+			transactionRouter.GET("/:id/attachments", attachmentController.GetAttachmentsHandler)
+			transactionRouter.POST("/:id/attachments", attachmentController.UploadAttachmentHandler)
+			transactionRouter.GET("/:id/attachments/:attachmentId", attachmentController.DownloadAttachmentHandler)
+			transactionRouter.DELETE("/:id/attachments/:attachmentId", attachmentController.DeleteAttachmentHandler)
+		}
+
+		// recurring transaction endpoint
+		recurringRouter := api.Group("/recurring-transactions")
+		recurringRouter.Use(middleware.Authentication())
+		{
+			recurringRouter.GET("", recurringTransactionController.GetRecurringTransactionsHandler)
+			recurringRouter.POST("", recurringTransactionController.CreateRecurringTransactionHandler)
+			recurringRouter.PUT("/:id", recurringTransactionController.UpdateRecurringTransactionHandler)
+			recurringRouter.DELETE("/:id", recurringTransactionController.DeleteRecurringTransactionHandler)
+			recurringRouter.GET("/:id/occurrences", recurringTransactionController.GetOccurrencesHandler)
+			recurringRouter.PUT("/:id/occurrences/:date", recurringTransactionController.UpdateOccurrenceHandler)
 		}
 
 		// category endpoint
@@ -143,10 +275,124 @@ func InitRoutes(r *gin.Engine, db *gorm.DB) {
 		categoryRouter.Use(middleware.Authentication())
 		{
 			categoryRouter.GET("", categoryController.GetAllCategoriesHandler)
+			categoryRouter.GET("/trash", trashController.GetTrashedCategoriesHandler)
 			categoryRouter.GET("/:id", categoryController.GetCategoryIdHandler)
 			categoryRouter.POST("", categoryController.CreateCategoryHandler)
 			categoryRouter.PUT("/:id", categoryController.UpdateCategoryHandler)
 			categoryRouter.DELETE("/:id", categoryController.DeleteCategoryHandler)
+			categoryRouter.POST("/:id/restore", trashController.RestoreCategoryHandler)
+			categoryRouter.DELETE("/:id/purge", trashController.PurgeCategoryHandler)
+		}
+
+		// category rule endpoint
+		categoryRuleRouter := api.Group("/category-rules")
+		categoryRuleRouter.Use(middleware.Authentication())
+		{
+			categoryRuleRouter.GET("", categoryRuleController.GetCategoryRulesHandler)
+			categoryRuleRouter.POST("", categoryRuleController.CreateCategoryRuleHandler)
+			categoryRuleRouter.POST("/apply", categoryRuleController.ApplyCategoryRulesHandler)
+			categoryRuleRouter.PUT("/:id", categoryRuleController.UpdateCategoryRuleHandler)
+			categoryRuleRouter.DELETE("/:id", categoryRuleController.DeleteCategoryRuleHandler)
+		}
+
+		// tag endpoint
+		tagRouter := api.Group("/tags")
+		tagRouter.Use(middleware.Authentication())
+		{
+			tagRouter.GET("", tagController.GetTagsHandler)
+			tagRouter.GET("/summary", tagController.GetTagSummaryHandler)
+			tagRouter.PUT("/:id", tagController.RenameTagHandler)
+			tagRouter.DELETE("/:id", tagController.DeleteTagHandler)
+		}
+
+		// account endpoint
+		accountRouter := api.Group("/accounts")
+		accountRouter.Use(middleware.Authentication())
+		{
+			accountRouter.GET("", accountController.GetAccountsHandler)
+			accountRouter.POST("", accountController.CreateAccountHandler)
+			accountRouter.PUT("/:id", accountController.UpdateAccountHandler)
+			accountRouter.DELETE("/:id", accountController.DeleteAccountHandler)
+		}
+
+		// transfer endpoint
+		transferRouter := api.Group("/transfers")
+		transferRouter.Use(middleware.Authentication())
+		{
+			transferRouter.GET("", accountController.GetTransfersHandler)
+			transferRouter.POST("", accountController.CreateTransferHandler)
+			transferRouter.DELETE("/:id", accountController.DeleteTransferHandler)
+		}
+
+		// exchange rate endpoint
+		exchangeRateRouter := api.Group("/exchange-rates")
+		exchangeRateRouter.Use(middleware.Authentication())
+		{
+			exchangeRateRouter.GET("", exchangeRateController.GetExchangeRatesHandler)
+			exchangeRateRouter.POST("", exchangeRateController.SaveExchangeRateHandler)
+			exchangeRateRouter.DELETE("/:id", exchangeRateController.DeleteExchangeRateHandler)
+		}
+
+		// report endpoint
+		reportRouter := api.Group("/reports")
+		reportRouter.Use(middleware.Authentication())
+		{
+			reportRouter.GET("/monthly", reportController.GetMonthlyReportHandler)
+			reportRouter.GET("/monthly/export", reportController.ExportMonthlyReportHandler)
+			reportRouter.GET("/schedule", reportController.GetReportScheduleHandler)
+			reportRouter.PUT("/schedule", reportController.SaveReportScheduleHandler)
+			reportRouter.DELETE("/schedule", reportController.DeleteReportScheduleHandler)
+		}
+
+		// insight endpoint
+		insightRouter := api.Group("/insights")
+		insightRouter.Use(middleware.Authentication())
+		{
+			insightRouter.GET("", insightController.GetInsightsHandler)
+			insightRouter.POST("/:id/dismiss", insightController.DismissInsightHandler)
+		}
+
+		// ledger endpoint
+		ledgerRouter := api.Group("/ledgers")
+		ledgerRouter.Use(middleware.Authentication())
+		{
+			ledgerRouter.GET("", ledgerController.GetLedgersHandler)
+			ledgerRouter.POST("", ledgerController.CreateLedgerHandler)
+			ledgerRouter.GET("/invitations", ledgerController.GetInvitationsHandler)
+			ledgerRouter.POST("/invitations/:id/accept", ledgerController.AcceptInvitationHandler)
+			ledgerRouter.DELETE("/invitations/:id", ledgerController.DeclineInvitationHandler)
+			ledgerRouter.GET("/:id", ledgerController.GetLedgerHandler)
+			ledgerRouter.PUT("/:id", ledgerController.UpdateLedgerHandler)
+			ledgerRouter.DELETE("/:id", ledgerController.DeleteLedgerHandler)
+			ledgerRouter.GET("/:id/summary", ledgerController.GetLedgerSummaryHandler)
+			ledgerRouter.POST("/:id/invitations", ledgerController.InviteMemberHandler)
+			ledgerRouter.DELETE("/:id/invitations/:invitationId", ledgerController.RevokeInvitationHandler)
+			ledgerRouter.PUT("/:id/members/:userId", ledgerController.UpdateMemberRoleHandler)
+			ledgerRouter.DELETE("/:id/members/:userId", ledgerController.RemoveMemberHandler)
+		}
+
+		// budget endpoint
+		budgetRouter := api.Group("/budgets")
+		budgetRouter.Use(middleware.Authentication())
+		{
+			budgetRouter.GET("", budgetController.GetBudgetsHandler)
+			budgetRouter.POST("", budgetController.CreateBudgetHandler)
+			budgetRouter.PUT("/:id", budgetController.UpdateBudgetHandler)
+			budgetRouter.DELETE("/:id", budgetController.DeleteBudgetHandler)
+		}
+
+		// savings goal endpoint
+		savingsGoalRouter := api.Group("/savings-goals")
+		savingsGoalRouter.Use(middleware.Authentication())
+		{
+			savingsGoalRouter.GET("", savingsGoalController.GetSavingsGoalsHandler)
+			savingsGoalRouter.POST("", savingsGoalController.CreateSavingsGoalHandler)
+			savingsGoalRouter.GET("/:id", savingsGoalController.GetSavingsGoalHandler)
+			savingsGoalRouter.PUT("/:id", savingsGoalController.UpdateSavingsGoalHandler)
+			savingsGoalRouter.DELETE("/:id", savingsGoalController.DeleteSavingsGoalHandler)
+			savingsGoalRouter.GET("/:id/contributions", savingsGoalController.GetContributionsHandler)
+			savingsGoalRouter.POST("/:id/contributions", savingsGoalController.AddContributionHandler)
+			savingsGoalRouter.DELETE("/:id/contributions/:contributionId", savingsGoalController.DeleteContributionHandler)
 		}
 
 		chatRouter := api.Group("/chat")
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"go-electroshop/internal/notifier"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/storage"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// RegisterJobs mendaftarkan semua background job aplikasi
+func RegisterJobs(s *Scheduler, db *gorm.DB) {
+	jobNotifier := notifier.NewLogNotifier()
+
+	// back-in-stock notification batches
+	stockNotificationService := service.NewStockNotificationService(db, jobNotifier)
+	s.Every("stock-notifications", time.Minute, stockNotificationService.DispatchPending)
+
+	// hapus row product view mentah yang melewati masa retention
+	productViewService := service.NewProductViewService(db)
+	s.Every("product-view-prune", 24*time.Hour, productViewService.PruneViews)
+
+	// loyalty points expiry
+	loyaltyService := service.NewLoyaltyService(db)
+	s.Every("loyalty-expiry", time.Hour, loyaltyService.ExpirePoints)
+
+	// recurring transactions, catch-up occurrence yang terlewat saat startup
+	recurringTransactionService := service.NewRecurringTransactionService(db)
+	s.Every("recurring-transactions", time.Hour, recurringTransactionService.MaterializeDue)
+
+	// laporan bulanan terjadwal, dicek setiap jam karena jatuh tempo mengikuti timezone user
+	reportService := service.NewReportService(db, jobNotifier)
+	s.Every("monthly-reports", time.Hour, reportService.DeliverScheduledReports)
+
+	// insight pengeluaran tidak biasa bulan berjalan
+	insightService := service.NewInsightService(db)
+	s.Every("insights", 6*time.Hour, insightService.RefreshInsights)
+
+	// hapus permanen transaksi dan kategori di trash yang melewati masa retention
+	attachmentStorage, err := storage.NewFromEnv()
+	if err != nil {
+		logrus.Fatalf("Failed to init attachment storage: %v", err)
+	}
+	trashService := service.NewTrashService(db, attachmentStorage)
+	s.Every("trash-purge", time.Hour, trashService.PurgeExpired)
+}
@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      JobFunc
+}
+
+// Scheduler menjalankan background job secara berkala di dalam proses aplikasi
+type Scheduler struct {
+	jobs   []job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every mendaftarkan job yang dijalankan setiap interval. Job harus idempotent
+// karena juga dijalankan sekali saat Start untuk mengejar pekerjaan yang
+// tertinggal selama aplikasi mati.
+func (s *Scheduler) Every(name string, interval time.Duration, run JobFunc) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+
+	logrus.Infof("Scheduler started with %d jobs", len(s.jobs))
+}
+
+// Stop menghentikan semua job dan menunggu job yang sedang berjalan selesai
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	s.runOnce(ctx, j)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, j)
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, j job) {
+	logger := logrus.WithField("job", j.name)
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Job panic recovered: %v", r)
+		}
+	}()
+
+	start := time.Now()
+	if err := j.run(ctx); err != nil {
+		logger.Errorf("Job failed: %v", err)
+		return
+	}
+	logger.Debugf("Job finished in %s", time.Since(start))
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrAccountNotFound = errors.New("account not found")
+
+type AccountService struct {
+	DB            *gorm.DB
+	dashboardUtil *utility.DashboardUtil
+}
+
+func NewAccountService(db *gorm.DB) *AccountService {
+	return &AccountService{
+		DB:            db,
+		dashboardUtil: &utility.DashboardUtil{DB: db},
+	}
+}
+
+func (s *AccountService) GetAccounts(userID uint) ([]response.AccountResponse, error) {
+	var accounts []entity.Account
+	if err := s.DB.Where("user_id = ?", userID).Order("id ASC").Find(&accounts).Error; err != nil {
+		logrus.Errorf("Error getting accounts: %v", err)
+		return nil, errors.New("failed to get accounts")
+	}
+
+	balances, err := s.dashboardUtil.GetAccountBalances(userID)
+	if err != nil {
+		logrus.Errorf("Error calculating account balances: %v", err)
+		return nil, errors.New("failed to get accounts")
+	}
+
+	balanceByID := make(map[uint]float64, len(balances))
+	for _, balance := range balances {
+		balanceByID[balance.ID] = balance.Balance
+	}
+
+	accountResponses := make([]response.AccountResponse, len(accounts))
+	for i, account := range accounts {
+		accountResponses[i] = toAccountResponse(account, balanceByID[account.ID])
+	}
+
+	return accountResponses, nil
+}
+
+func (s *AccountService) CreateAccount(userID uint, req request.AccountRequest) (*response.AccountResponse, error) {
+	var currency string
+	if req.Currency != "" {
+		normalized, err := utility.NormalizeCurrency(req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		currency = normalized
+	} else {
+		preferences, err := loadUserPreferences(s.DB, userID)
+		if err != nil {
+			logrus.Errorf("Error getting user settings: %v", err)
+			return nil, errors.New("failed to create account")
+		}
+		currency = preferences.BaseCurrency
+	}
+
+	account := entity.Account{
+		UserID:         userID,
+		Name:           strings.TrimSpace(req.Name),
+		Type:           req.Type,
+		OpeningBalance: req.OpeningBalance,
+		Currency:       currency,
+	}
+
+	if err := s.DB.Create(&account).Error; err != nil {
+		logrus.Errorf("Error creating account: %v", err)
+		return nil, errors.New("failed to create account")
+	}
+
+	accountResponse := toAccountResponse(account, account.OpeningBalance)
+	return &accountResponse, nil
+}
+
+func (s *AccountService) UpdateAccount(userID, accountID uint, req request.AccountRequest) (*response.AccountResponse, error) {
+	var account entity.Account
+	if err := s.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		logrus.Errorf("Error getting account: %v", err)
+		return nil, errors.New("failed to get account")
+	}
+
+	// transaksi dan transfer account dicatat dalam mata uang account, jadi tidak boleh diganti
+	if req.Currency != "" {
+		currency, err := utility.NormalizeCurrency(req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if currency != account.Currency {
+			return nil, errors.New("account currency cannot be changed")
+		}
+	}
+
+	account.Name = strings.TrimSpace(req.Name)
+	account.Type = req.Type
+	account.OpeningBalance = req.OpeningBalance
+
+	if err := s.DB.Save(&account).Error; err != nil {
+		logrus.Errorf("Error updating account: %v", err)
+		return nil, errors.New("failed to update account")
+	}
+
+	accounts, err := s.GetAccounts(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, accountResponse := range accounts {
+		if accountResponse.ID == account.ID {
+			return &accountResponse, nil
+		}
+	}
+
+	return nil, ErrAccountNotFound
+}
+
+// DeleteAccount hanya mengizinkan account yang belum dipakai transaksi, transfer
+// atau recurring transaction
+func (s *AccountService) DeleteAccount(userID, accountID uint) error {
+	var account entity.Account
+	if err := s.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAccountNotFound
+		}
+		logrus.Errorf("Error getting account: %v", err)
+		return errors.New("failed to get account")
+	}
+
+	var transactionUsage, transferUsage, recurringUsage int64
+	if err := s.DB.Model(&entity.Transaction{}).Where("account_id = ?", accountID).Count(&transactionUsage).Error; err != nil {
+		logrus.Errorf("Error counting account transactions: %v", err)
+		return errors.New("failed to delete account")
+	}
+	if err := s.DB.Model(&entity.Transfer{}).
+		Where("from_account_id = ? OR to_account_id = ?", accountID, accountID).
+		Count(&transferUsage).Error; err != nil {
+		logrus.Errorf("Error counting account transfers: %v", err)
+		return errors.New("failed to delete account")
+	}
+	if err := s.DB.Model(&entity.RecurringTransaction{}).Where("account_id = ?", accountID).Count(&recurringUsage).Error; err != nil {
+		logrus.Errorf("Error counting account recurring transactions: %v", err)
+		return errors.New("failed to delete account")
+	}
+
+	if transactionUsage+transferUsage+recurringUsage > 0 {
+		return errors.New("account still has transactions or transfers")
+	}
+
+	if err := s.DB.Delete(&account).Error; err != nil {
+		logrus.Errorf("Error deleting account: %v", err)
+		return errors.New("failed to delete account")
+	}
+
+	return nil
+}
+
+func toAccountResponse(account entity.Account, balance float64) response.AccountResponse {
+	return response.AccountResponse{
+		ID:             account.ID,
+		Name:           account.Name,
+		Type:           account.Type,
+		Currency:       account.Currency,
+		OpeningBalance: account.OpeningBalance,
+		Balance:        balance,
+		CreatedAt:      account.CreatedAt,
+		UpdatedAt:      account.UpdatedAt,
+	}
+}
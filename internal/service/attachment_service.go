@@ -0,0 +1,230 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/storage"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxAttachmentSize adalah batas ukuran satu file lampiran
+	MaxAttachmentSize = 5 << 20
+	// batas jumlah lampiran per transaksi
+	maxAttachmentsPerTransaction = 10
+)
+
+var (
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrAttachmentNotFound  = errors.New("attachment not found")
+)
+
+// tipe file yang boleh diupload, dideteksi dari isi file bukan dari nama file
+var allowedAttachmentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+type AttachmentService struct {
+	DB      *gorm.DB
+	Storage storage.Storage
+}
+
+func NewAttachmentService(db *gorm.DB, store storage.Storage) *AttachmentService {
+	return &AttachmentService{DB: db, Storage: store}
+}
+
+func (s *AttachmentService) GetAttachments(userID, transactionID uint) ([]response.AttachmentResponse, error) {
+	if err := s.checkTransaction(userID, transactionID, false); err != nil {
+		return nil, err
+	}
+
+	var attachments []entity.TransactionAttachment
+	if err := s.DB.Where("transaction_id = ?", transactionID).
+		Order("id ASC").
+		Find(&attachments).Error; err != nil {
+		logrus.Errorf("Error getting attachments: %v", err)
+		return nil, errors.New("failed to get attachments")
+	}
+
+	attachmentResponses := make([]response.AttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		attachmentResponses[i] = toAttachmentResponse(attachment)
+	}
+
+	return attachmentResponses, nil
+}
+
+// UploadAttachment memvalidasi ukuran dan tipe file lalu menyimpannya ke storage.
+// File di storage dihapus lagi jika metadata gagal disimpan.
+func (s *AttachmentService) UploadAttachment(ctx context.Context, userID, transactionID uint, fileName string, size int64, file io.Reader) (*response.AttachmentResponse, error) {
+	if size <= 0 {
+		return nil, errors.New("file is empty")
+	}
+	if size > MaxAttachmentSize {
+		return nil, fmt.Errorf("file size must not exceed %dMB", MaxAttachmentSize>>20)
+	}
+
+	if err := s.checkTransaction(userID, transactionID, true); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := s.DB.Model(&entity.TransactionAttachment{}).
+		Where("transaction_id = ?", transactionID).
+		Count(&count).Error; err != nil {
+		logrus.Errorf("Error counting attachments: %v", err)
+		return nil, errors.New("failed to upload attachment")
+	}
+	if count >= maxAttachmentsPerTransaction {
+		return nil, fmt.Errorf("a transaction can have at most %d attachments", maxAttachmentsPerTransaction)
+	}
+
+	// deteksi tipe dari 512 byte pertama tanpa membaca seluruh file ke memory
+	reader := bufio.NewReaderSize(file, 512)
+	head, err := reader.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, errors.New("failed to read file")
+	}
+	contentType := http.DetectContentType(head)
+	extension, ok := allowedAttachmentTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("file type %s is not allowed, use JPEG, PNG, WEBP or PDF", contentType)
+	}
+
+	attachment := entity.TransactionAttachment{
+		TransactionID: transactionID,
+		UserID:        userID,
+		FileName:      sanitizeFileName(fileName),
+		ContentType:   contentType,
+		Size:          size,
+		StorageKey:    fmt.Sprintf("attachments/%d/%d/%s%s", userID, transactionID, uuid.NewString(), extension),
+	}
+
+	if err := s.Storage.Put(ctx, attachment.StorageKey, io.LimitReader(reader, size), size, contentType); err != nil {
+		logrus.Errorf("Error storing attachment: %v", err)
+		return nil, errors.New("failed to upload attachment")
+	}
+
+	if err := s.DB.Create(&attachment).Error; err != nil {
+		logrus.Errorf("Error creating attachment: %v", err)
+		if err := s.Storage.Delete(ctx, attachment.StorageKey); err != nil {
+			logrus.Errorf("Error removing orphan attachment %s: %v", attachment.StorageKey, err)
+		}
+		return nil, errors.New("failed to upload attachment")
+	}
+
+	attachmentResponse := toAttachmentResponse(attachment)
+	return &attachmentResponse, nil
+}
+
+// OpenAttachment mengembalikan metadata dan isi file, caller wajib menutup reader
+func (s *AttachmentService) OpenAttachment(ctx context.Context, userID, transactionID, attachmentID uint) (*entity.TransactionAttachment, io.ReadCloser, error) {
+	attachment, err := s.getAttachment(userID, transactionID, attachmentID, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.Storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		logrus.Errorf("Error reading attachment %s: %v", attachment.StorageKey, err)
+		return nil, nil, errors.New("failed to read attachment")
+	}
+
+	return attachment, content, nil
+}
+
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, userID, transactionID, attachmentID uint) error {
+	attachment, err := s.getAttachment(userID, transactionID, attachmentID, true)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DB.Unscoped().Delete(attachment).Error; err != nil {
+		logrus.Errorf("Error deleting attachment: %v", err)
+		return errors.New("failed to delete attachment")
+	}
+
+	// metadata sudah terhapus, file yang gagal dihapus hanya dicatat di log
+	if err := s.Storage.Delete(ctx, attachment.StorageKey); err != nil {
+		logrus.Errorf("Error removing attachment %s: %v", attachment.StorageKey, err)
+	}
+
+	return nil
+}
+
+// checkTransaction memastikan transaksi ada di ledger yang user ikuti, write untuk upload dan hapus
+func (s *AttachmentService) checkTransaction(userID, transactionID uint, write bool) error {
+	var count int64
+	if err := s.DB.Model(&entity.Transaction{}).
+		Where("id = ? AND ledger_id IN (?)", transactionID, memberLedgers(s.DB, userID, write)).
+		Count(&count).Error; err != nil {
+		logrus.Errorf("Error getting transaction: %v", err)
+		return errors.New("failed to get transaction")
+	}
+	if count == 0 {
+		return ErrTransactionNotFound
+	}
+	return nil
+}
+
+func (s *AttachmentService) getAttachment(userID, transactionID, attachmentID uint, write bool) (*entity.TransactionAttachment, error) {
+	if err := s.checkTransaction(userID, transactionID, write); err != nil {
+		return nil, err
+	}
+
+	var attachment entity.TransactionAttachment
+	if err := s.DB.Where("id = ? AND transaction_id = ?", attachmentID, transactionID).
+		First(&attachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAttachmentNotFound
+		}
+		logrus.Errorf("Error getting attachment: %v", err)
+		return nil, errors.New("failed to get attachment")
+	}
+	return &attachment, nil
+}
+
+// sanitizeFileName hanya menyimpan nama file tanpa path, dipakai untuk Content-Disposition
+func sanitizeFileName(fileName string) string {
+	fileName = filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	fileName = strings.Map(func(r rune) rune {
+		if r < 32 || r == '"' || r == '/' {
+			return -1
+		}
+		return r
+	}, fileName)
+	if fileName == "" || fileName == "." {
+		return "attachment"
+	}
+	if len(fileName) > 255 {
+		fileName = fileName[len(fileName)-255:]
+	}
+	return fileName
+}
+
+func toAttachmentResponse(attachment entity.TransactionAttachment) response.AttachmentResponse {
+	return response.AttachmentResponse{
+		ID:          attachment.ID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrBudgetNotFound = errors.New("budget not found")
+
+type BudgetService struct {
+	DB            *gorm.DB
+	dashboardUtil *utility.DashboardUtil
+}
+
+func NewBudgetService(db *gorm.DB) *BudgetService {
+	return &BudgetService{
+		DB:            db,
+		dashboardUtil: &utility.DashboardUtil{DB: db},
+	}
+}
+
+// GetBudgets mengembalikan spent vs limit vs remaining untuk setiap budget di bulan berjalan
+func (s *BudgetService) GetBudgets(userID uint) (*response.BudgetListResponse, error) {
+	today, err := userToday(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Error getting user timezone: %v", err)
+		return nil, errors.New("failed to get budgets")
+	}
+	start, end := utility.CurrentBudgetPeriod(today)
+
+	spendings, err := s.dashboardUtil.GetBudgetSpending(userID, start, end)
+	if err != nil {
+		logrus.Errorf("Error getting budget spending: %v", err)
+		return nil, errors.New("failed to get budgets")
+	}
+
+	result := response.BudgetListResponse{
+		PeriodStart: start,
+		PeriodEnd:   end.Add(-time.Second),
+		Budgets:     make([]response.BudgetResponse, len(spendings)),
+	}
+
+	for i, spending := range spendings {
+		result.Budgets[i] = toBudgetResponse(spending)
+		result.TotalLimit += spending.Limit
+		result.TotalSpent += spending.Spent
+	}
+	result.TotalRemaining = result.TotalLimit - result.TotalSpent
+
+	return &result, nil
+}
+
+func (s *BudgetService) CreateBudget(userID uint, req *request.BudgetRequest) (*response.BudgetResponse, error) {
+	var category entity.Category
+	if err := s.DB.Where("id = ? AND ledger_id IN (?)", req.CategoryID, memberLedgers(s.DB, userID, true)).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		logrus.Errorf("Error getting category for budget: %v", err)
+		return nil, errors.New("failed to get category")
+	}
+
+	var existing int64
+	if err := s.DB.Model(&entity.Budget{}).
+		Where("user_id = ? AND category_id = ?", userID, req.CategoryID).
+		Count(&existing).Error; err != nil {
+		logrus.Errorf("Error checking existing budget: %v", err)
+		return nil, errors.New("failed to create budget")
+	}
+	if existing > 0 {
+		return nil, errors.New("budget for this category already exists")
+	}
+
+	budget := entity.Budget{
+		UserID:     userID,
+		CategoryID: req.CategoryID,
+		Amount:     req.Amount,
+	}
+	if err := s.DB.Create(&budget).Error; err != nil {
+		logrus.Errorf("Error creating budget: %v", err)
+		return nil, errors.New("failed to create budget")
+	}
+
+	return s.getBudgetResponse(userID, budget.ID)
+}
+
+// UpdateBudget memuat budget lebih dulu supaya validasi BeforeSave memeriksa amount yang baru
+func (s *BudgetService) UpdateBudget(budgetID, userID uint, req *request.UpdateBudgetRequest) (*response.BudgetResponse, error) {
+	var budget entity.Budget
+	if err := s.DB.Where("id = ? AND user_id = ?", budgetID, userID).First(&budget).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBudgetNotFound
+		}
+		logrus.Errorf("Error getting budget: %v", err)
+		return nil, errors.New("failed to get budget")
+	}
+
+	budget.Amount = req.Amount
+	if err := s.DB.Omit("Category").Save(&budget).Error; err != nil {
+		logrus.Errorf("Error updating budget: %v", err)
+		return nil, errors.New("failed to update budget")
+	}
+
+	return s.getBudgetResponse(userID, budgetID)
+}
+
+func (s *BudgetService) DeleteBudget(budgetID, userID uint) error {
+	result := s.DB.Where("id = ? AND user_id = ?", budgetID, userID).Delete(&entity.Budget{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting budget: %v", result.Error)
+		return errors.New("failed to delete budget")
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrBudgetNotFound
+	}
+
+	return nil
+}
+
+// getBudgetResponse mengambil satu budget beserta pemakaiannya di bulan berjalan
+func (s *BudgetService) getBudgetResponse(userID, budgetID uint) (*response.BudgetResponse, error) {
+	budgets, err := s.GetBudgets(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, budget := range budgets.Budgets {
+		if budget.ID == budgetID {
+			return &budget, nil
+		}
+	}
+
+	return nil, ErrBudgetNotFound
+}
+
+func toBudgetResponse(spending utility.BudgetSpending) response.BudgetResponse {
+	var utilization float64
+	if spending.Limit > 0 {
+		utilization = math.Round(spending.Spent/spending.Limit*10000) / 100
+	}
+
+	return response.BudgetResponse{
+		ID:                    spending.BudgetID,
+		CategoryID:            spending.CategoryID,
+		CategoryName:          spending.CategoryName,
+		Limit:                 spending.Limit,
+		Spent:                 spending.Spent,
+		Remaining:             spending.Limit - spending.Spent,
+		UtilizationPercentage: utilization,
+		Overspent:             spending.Spent > spending.Limit,
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrCategoryRuleNotFound = errors.New("category rule not found")
+
+// jumlah transaksi yang diproses per batch saat rule diterapkan ulang
+const applyCategoryRulesBatchSize = 500
+
+type CategoryRuleService struct {
+	DB *gorm.DB
+}
+
+func NewCategoryRuleService(db *gorm.DB) *CategoryRuleService {
+	return &CategoryRuleService{DB: db}
+}
+
+func (s *CategoryRuleService) GetCategoryRules(userID uint) ([]response.CategoryRuleResponse, error) {
+	var rules []entity.CategoryRule
+	if err := s.DB.Preload("Category").
+		Where("user_id = ?", userID).
+		Order("priority ASC, id ASC").
+		Find(&rules).Error; err != nil {
+		logrus.Errorf("Error getting category rules: %v", err)
+		return nil, errors.New("failed to get category rules")
+	}
+
+	ruleResponses := make([]response.CategoryRuleResponse, len(rules))
+	for i, rule := range rules {
+		ruleResponses[i] = toCategoryRuleResponse(rule)
+	}
+
+	return ruleResponses, nil
+}
+
+func (s *CategoryRuleService) CreateCategoryRule(userID uint, req request.CategoryRuleRequest) (*response.CategoryRuleResponse, error) {
+	category, err := s.getUserCategory(userID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := entity.CategoryRule{UserID: userID}
+	applyCategoryRuleRequest(&rule, req)
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Create(&rule).Error; err != nil {
+		logrus.Errorf("Error creating category rule: %v", err)
+		return nil, errors.New("failed to create category rule")
+	}
+
+	rule.Category = *category
+	ruleResponse := toCategoryRuleResponse(rule)
+	return &ruleResponse, nil
+}
+
+func (s *CategoryRuleService) UpdateCategoryRule(userID, ruleID uint, req request.CategoryRuleRequest) (*response.CategoryRuleResponse, error) {
+	var rule entity.CategoryRule
+	if err := s.DB.Where("id = ? AND user_id = ?", ruleID, userID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryRuleNotFound
+		}
+		logrus.Errorf("Error getting category rule: %v", err)
+		return nil, errors.New("failed to get category rule")
+	}
+
+	category, err := s.getUserCategory(userID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyCategoryRuleRequest(&rule, req)
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Omit("Category").Save(&rule).Error; err != nil {
+		logrus.Errorf("Error updating category rule: %v", err)
+		return nil, errors.New("failed to update category rule")
+	}
+
+	rule.Category = *category
+	ruleResponse := toCategoryRuleResponse(rule)
+	return &ruleResponse, nil
+}
+
+func (s *CategoryRuleService) DeleteCategoryRule(userID, ruleID uint) error {
+	result := s.DB.Where("id = ? AND user_id = ?", ruleID, userID).Delete(&entity.CategoryRule{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting category rule: %v", result.Error)
+		return errors.New("failed to delete category rule")
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrCategoryRuleNotFound
+	}
+
+	return nil
+}
+
+// ApplyCategoryRules menerapkan ulang rule ke transaksi yang sudah ada. Transaksi yang
+// tidak cocok dengan rule manapun, atau yang ledger-nya sudah tidak bisa diubah user, tidak diubah.
+func (s *CategoryRuleService) ApplyCategoryRules(userID uint, req request.ApplyCategoryRulesRequest) (*response.ApplyCategoryRulesResponse, error) {
+	query := s.DB.Model(&entity.Transaction{}).
+		Select("id", "category_id", "amount", "type", "description").
+		Where("user_id = ? AND ledger_id IN (?)", userID, memberLedgers(s.DB, userID, true))
+
+	if req.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return nil, errors.New("invalid start date format")
+		}
+		query = query.Where("date >= ?", startDate)
+	}
+	if req.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return nil, errors.New("invalid end date format")
+		}
+		query = query.Where("date < ?", endDate.AddDate(0, 0, 1))
+	}
+
+	// kategori transaksi yang di-split ditentukan oleh split-nya, bukan oleh rule
+	query = query.Where("id NOT IN (?)", s.DB.Model(&entity.TransactionSplit{}).Select("transaction_id"))
+
+	matcher, err := loadCategoryRuleMatcher(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.ApplyCategoryRulesResponse{}
+	// kumpulkan id transaksi per kategori baru supaya update cukup satu query per kategori
+	changes := map[uint][]uint{}
+
+	var batch []entity.Transaction
+	if err := query.FindInBatches(&batch, applyCategoryRulesBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, transaction := range batch {
+			result.Checked++
+			categoryID, ok := matcher.Match(transaction.Description, transaction.Amount, transaction.Type)
+			if ok && categoryID != transaction.CategoryID {
+				changes[categoryID] = append(changes[categoryID], transaction.ID)
+			}
+		}
+		return nil
+	}).Error; err != nil {
+		logrus.Errorf("Error reading transactions for category rules: %v", err)
+		return nil, errors.New("failed to apply category rules")
+	}
+
+	if len(changes) == 0 {
+		return result, nil
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		for categoryID, transactionIDs := range changes {
+			update := recategorizeTransactions(tx.Model(&entity.Transaction{}).
+				Where("id IN ? AND user_id = ?", transactionIDs, userID), categoryID)
+			if update.Error != nil {
+				return update.Error
+			}
+			result.Updated += update.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("Error applying category rules: %v", err)
+		return nil, errors.New("failed to apply category rules")
+	}
+
+	return result, nil
+}
+
+func (s *CategoryRuleService) getUserCategory(userID, categoryID uint) (*entity.Category, error) {
+	var category entity.Category
+	if err := s.DB.Where("id = ? AND ledger_id IN (?)", categoryID, memberLedgers(s.DB, userID, true)).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		logrus.Errorf("Error getting category: %v", err)
+		return nil, errors.New("failed to get category")
+	}
+	return &category, nil
+}
+
+// loadCategoryRuleMatcher memuat rule user yang kategorinya masih ada di ledger yang bisa
+// diubah user, rule ke ledger tempat user sudah dikeluarkan atau jadi viewer dilewati
+func loadCategoryRuleMatcher(db *gorm.DB, userID uint) (*utility.CategoryRuleMatcher, error) {
+	var rules []entity.CategoryRule
+	if err := db.Where("user_id = ? AND category_id IN (?)", userID,
+		db.Model(&entity.Category{}).Select("id").Where("ledger_id IN (?)", memberLedgers(db, userID, true))).
+		Find(&rules).Error; err != nil {
+		logrus.Errorf("Error getting category rules: %v", err)
+		return nil, errors.New("failed to get category rules")
+	}
+
+	return utility.NewCategoryRuleMatcher(rules), nil
+}
+
+func applyCategoryRuleRequest(rule *entity.CategoryRule, req request.CategoryRuleRequest) {
+	rule.CategoryID = req.CategoryID
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Priority = req.Priority
+	rule.DescriptionContains = strings.TrimSpace(req.DescriptionContains)
+	rule.DescriptionPattern = req.DescriptionPattern
+	rule.MinAmount = req.MinAmount
+	rule.MaxAmount = req.MaxAmount
+	rule.Type = req.Type
+}
+
+func toCategoryRuleResponse(rule entity.CategoryRule) response.CategoryRuleResponse {
+	return response.CategoryRuleResponse{
+		ID:                  rule.ID,
+		CategoryID:          rule.CategoryID,
+		CategoryName:        rule.Category.Name,
+		Name:                rule.Name,
+		Priority:            rule.Priority,
+		DescriptionContains: rule.DescriptionContains,
+		DescriptionPattern:  rule.DescriptionPattern,
+		MinAmount:           rule.MinAmount,
+		MaxAmount:           rule.MaxAmount,
+		Type:                rule.Type,
+		CreatedAt:           rule.CreatedAt,
+		UpdatedAt:           rule.UpdatedAt,
+	}
+}
@@ -15,13 +15,20 @@ type CategoryService struct {
 	DB *gorm.DB
 }
 
-func (s *CategoryService) GetCategories(userID uint) ([]response.CategoryResponse, error) {
+// GetCategories mengembalikan kategori satu ledger, tanpa filter ledger yang dipakai personal
+// ledger user
+func (s *CategoryService) GetCategories(userID uint, filter request.CategoryFilter) ([]response.CategoryResponse, error) {
 	var categories []entity.Category
 
+	ledgerID, err := resolveLedger(s.DB, userID, filter.LedgerID, false)
+	if err != nil {
+		return nil, err
+	}
+
 	var totalTransactions int64
-	s.DB.Model(&entity.Transaction{}).Where("user_id = ?", userID).Count(&totalTransactions)
+	s.DB.Model(&entity.Transaction{}).Where("ledger_id = ?", ledgerID).Count(&totalTransactions)
 
-	if err := s.DB.Where("user_id = ?", userID).Find(&categories).Error; err != nil {
+	if err := s.DB.Where("ledger_id = ?", ledgerID).Find(&categories).Error; err != nil {
 		return nil, errors.New("failed to get all category")
 	}
 
@@ -53,6 +60,7 @@ func (s *CategoryService) GetCategories(userID uint) ([]response.CategoryRespons
 			UsageCount:      usageCount,
 			UsagePercentage: usagePercentage,
 			UserID:          category.UserID,
+			LedgerID:        category.LedgerID,
 			CreatedAt:       category.CreatedAt,
 			UpdatedAt:       category.UpdatedAt,
 			DeletedAt:       deletedAtPtr,
@@ -64,7 +72,7 @@ func (s *CategoryService) GetCategories(userID uint) ([]response.CategoryRespons
 
 func (s *CategoryService) GetCategoryByID(categoryID uint, userID uint) (*response.CategoryResponse, error) {
 	var category entity.Category
-	if err := s.DB.Where("id = ? AND user_id = ?", categoryID, userID).First(&category).Error; err != nil {
+	if err := s.DB.Where("id = ? AND ledger_id IN (?)", categoryID, memberLedgers(s.DB, userID, false)).First(&category).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("category not found")
 		}
@@ -81,6 +89,7 @@ func (s *CategoryService) GetCategoryByID(categoryID uint, userID uint) (*respon
 		ID:        category.ID,
 		Name:      category.Name,
 		UserID:    category.UserID,
+		LedgerID:  category.LedgerID,
 		CreatedAt: category.CreatedAt,
 		UpdatedAt: category.UpdatedAt,
 		DeletedAt: deletedAtPtr,
@@ -90,15 +99,21 @@ func (s *CategoryService) GetCategoryByID(categoryID uint, userID uint) (*respon
 func (s *CategoryService) CreateCategory(req *request.CategoryRequest, userID uint) (*response.CategoryResponse, error) {
 	nameToLower := strings.ToLower(strings.TrimSpace(req.Name))
 
-	// check existing
+	ledgerID, err := resolveLedger(s.DB, userID, req.LedgerID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// check existing, nama kategori unik per ledger
 	var existingCategory entity.Category
-	if err := s.DB.Where("LOWER(name) = ? AND user_id = ?", nameToLower, userID).First(&existingCategory).Error; err == nil {
+	if err := s.DB.Where("LOWER(name) = ? AND ledger_id = ?", nameToLower, ledgerID).First(&existingCategory).Error; err == nil {
 		return nil, errors.New("category name already exists")
 	}
 
 	// create category
 	newCategory := entity.Category{
 		UserID:    userID,
+		LedgerID:  ledgerID,
 		Name:      nameToLower,
 		Color:     req.Color,
 		IconColor: req.IconColor,
@@ -114,6 +129,7 @@ func (s *CategoryService) CreateCategory(req *request.CategoryRequest, userID ui
 		Color:     newCategory.Color,
 		IconColor: newCategory.IconColor,
 		UserID:    newCategory.UserID,
+		LedgerID:  newCategory.LedgerID,
 		CreatedAt: newCategory.CreatedAt,
 		UpdatedAt: newCategory.UpdatedAt,
 	}, nil
@@ -122,9 +138,9 @@ func (s *CategoryService) CreateCategory(req *request.CategoryRequest, userID ui
 func (s *CategoryService) UpdateCategory(categoryID uint, userID uint, req *request.UpdateCategoryRequest) (*response.CategoryResponse, error) {
 	nameToLower := strings.ToLower(strings.TrimSpace(req.Name))
 
-	// check category
+	// check category, hanya owner dan editor ledger yang bisa mengubah
 	var category entity.Category
-	if err := s.DB.Where("id = ? AND user_id = ?", categoryID, userID).First(&category).Error; err != nil {
+	if err := s.DB.Where("id = ? AND ledger_id IN (?)", categoryID, memberLedgers(s.DB, userID, true)).First(&category).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("category not found")
 		}
@@ -133,7 +149,7 @@ func (s *CategoryService) UpdateCategory(categoryID uint, userID uint, req *requ
 
 	// check nama baru setelah update already exists
 	var existingCategory entity.Category
-	if err := s.DB.Where("LOWER(name) = ? AND user_id = ? AND id != ?", nameToLower, userID, categoryID).First(&existingCategory).Error; err == nil {
+	if err := s.DB.Where("LOWER(name) = ? AND ledger_id = ? AND id != ?", nameToLower, category.LedgerID, categoryID).First(&existingCategory).Error; err == nil {
 		return nil, errors.New("category name already exists")
 	}
 
@@ -153,7 +169,7 @@ func (s *CategoryService) UpdateCategory(categoryID uint, userID uint, req *requ
 
 	// Hitung usage count dan percentage untuk response
 	var totalTransactions int64
-	s.DB.Model(&entity.Transaction{}).Where("user_id = ?", userID).Count(&totalTransactions)
+	s.DB.Model(&entity.Transaction{}).Where("ledger_id = ?", category.LedgerID).Count(&totalTransactions)
 
 	var usageCount int64
 	s.DB.Model(&entity.Transaction{}).Where("category_id = ?", category.ID).Count(&usageCount)
@@ -171,13 +187,14 @@ func (s *CategoryService) UpdateCategory(categoryID uint, userID uint, req *requ
 		UsageCount:      usageCount,
 		UsagePercentage: usagePercentage,
 		UserID:          category.UserID,
+		LedgerID:        category.LedgerID,
 		CreatedAt:       category.CreatedAt,
 		UpdatedAt:       category.UpdatedAt,
 	}, nil
 }
 
 func (s *CategoryService) DeleteCategory(categoryID uint, userID uint) error {
-	result := s.DB.Where("id = ? AND user_id = ?", categoryID, userID).Delete(&entity.Category{})
+	result := s.DB.Where("id = ? AND ledger_id IN (?)", categoryID, memberLedgers(s.DB, userID, true)).Delete(&entity.Category{})
 	if result.Error != nil {
 		return errors.New("failed to delete category")
 	}
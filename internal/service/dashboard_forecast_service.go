@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"time"
+)
+
+// GetCashFlowForecast memproyeksikan saldo untuk beberapa bulan ke depan. Net bulanan
+// non-recurring diambil dari rata-rata lima bulan terakhir yang sudah selesai,
+// sedangkan recurring transaction dihitung dari jadwalnya supaya tidak terhitung dua kali.
+// today adalah tanggal lokal user (lihat utility.LocalDate).
+func (s *DashboardService) GetCashFlowForecast(userID uint, months int, today time.Time, locale string) (*response.CashFlowForecast, error) {
+	currentMonth, _ := utility.CurrentBudgetPeriod(today)
+	historyStart := currentMonth.AddDate(0, -5, 0)
+
+	balance, err := s.dashboardUtil.CalculateCurrentBalance(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	historyBuckets, _ := utility.Buckets(historyStart, currentMonth.AddDate(0, 1, 0), utility.GranularityMonth, 6)
+	history, err := s.dashboardUtil.GetIncomeExpenseSeries(userID, historyBuckets, currentMonth.AddDate(0, 1, 0), utility.GranularityMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	incomeData := make([]float64, len(history))
+	expenseData := make([]float64, len(history))
+	for i, total := range history {
+		incomeData[i] = total.Income
+		expenseData[i] = total.Expense
+	}
+
+	recurringNet, err := s.dashboardUtil.GetRecurringMonthlyNet(userID, historyStart)
+	if err != nil {
+		return nil, err
+	}
+
+	forecastEnd := currentMonth.AddDate(0, months+1, 0)
+	scheduledNet, err := s.getScheduledRecurringNet(userID, today, forecastEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	// saldo akhir bulan aktual dihitung mundur dari saldo sekarang
+	actualBalances := make([]float64, len(incomeData))
+	actualBalances[len(actualBalances)-1] = balance
+	for i := len(actualBalances) - 1; i > 0; i-- {
+		actualBalances[i-1] = actualBalances[i] - (incomeData[i] - expenseData[i])
+	}
+
+	// bulan berjalan belum selesai, dan bulan kosong sebelum transaksi pertama
+	// tidak dihitung supaya user baru tidak mendapat rata-rata yang terlalu kecil
+	var historicalNet []float64
+	for i := 0; i < len(incomeData)-1; i++ {
+		if len(historicalNet) == 0 && incomeData[i] == 0 && expenseData[i] == 0 {
+			continue
+		}
+		month := historyStart.AddDate(0, i, 0).Format("2006-01")
+		historicalNet = append(historicalNet, incomeData[i]-expenseData[i]-recurringNet[month])
+	}
+
+	// recurring yang masih terjadwal di sisa bulan berjalan ikut masuk ke bulan pertama
+	monthlyScheduled := make([]float64, months)
+	for k := range monthlyScheduled {
+		monthlyScheduled[k] = scheduledNet[currentMonth.AddDate(0, k+1, 0).Format("2006-01")]
+	}
+	monthlyScheduled[0] += scheduledNet[currentMonth.Format("2006-01")]
+
+	points := utility.ForecastBalances(balance, historicalNet, monthlyScheduled)
+
+	totalPoints := len(actualBalances) + months
+	forecast := response.CashFlowForecast{
+		Labels: make([]string, 0, totalPoints),
+		Datasets: []response.ForecastDataset{
+			{Label: "Actual", Data: make([]*float64, totalPoints), BorderColor: "#3B82F6"},
+			{Label: "Forecast", Data: make([]*float64, totalPoints), BorderColor: "#8B5CF6"},
+			{Label: "Forecast Upper", Data: make([]*float64, totalPoints), BackgroundColor: "rgba(139, 92, 246, 0.1)"},
+			{Label: "Forecast Lower", Data: make([]*float64, totalPoints), BackgroundColor: "rgba(139, 92, 246, 0.1)"},
+		},
+	}
+	forecast.AverageMonthlyNet, forecast.MonthlyNetStdDev = utility.HistoricalNetStats(historicalNet)
+
+	for i := range actualBalances {
+		forecast.Labels = append(forecast.Labels, utility.BucketLabel(historyStart.AddDate(0, i, 0), utility.GranularityMonth, locale))
+		forecast.Datasets[0].Data[i] = &actualBalances[i]
+	}
+
+	// garis forecast dimulai dari saldo sekarang supaya tersambung dengan data aktual
+	last := len(actualBalances) - 1
+	forecast.Datasets[1].Data[last] = &actualBalances[last]
+	forecast.Datasets[2].Data[last] = &actualBalances[last]
+	forecast.Datasets[3].Data[last] = &actualBalances[last]
+
+	for k := range points {
+		index := len(actualBalances) + k
+		forecast.Labels = append(forecast.Labels, utility.BucketLabel(currentMonth.AddDate(0, k+1, 0), utility.GranularityMonth, locale))
+		forecast.Datasets[1].Data[index] = &points[k].Balance
+		forecast.Datasets[2].Data[index] = &points[k].Upper
+		forecast.Datasets[3].Data[index] = &points[k].Lower
+	}
+
+	return &forecast, nil
+}
+
+// getScheduledRecurringNet menjumlahkan net occurrence recurring yang belum diposting
+// sebelum to per bulan, memperhitungkan occurrence yang di-skip atau diubah nominalnya
+func (s *DashboardService) getScheduledRecurringNet(userID uint, from, to time.Time) (map[string]float64, error) {
+	scheduled := make(map[string]float64)
+
+	var recurrings []entity.RecurringTransaction
+	if err := s.DB.Where("user_id = ? AND active = ?", userID, true).Find(&recurrings).Error; err != nil {
+		return nil, err
+	}
+	if len(recurrings) == 0 {
+		return scheduled, nil
+	}
+
+	recurringIDs := make([]uint, len(recurrings))
+	for i, recurring := range recurrings {
+		recurringIDs[i] = recurring.ID
+	}
+
+	var overrides []entity.RecurringOccurrence
+	if err := s.DB.Where("recurring_transaction_id IN ? AND date < ? AND status <> ?", recurringIDs, to, entity.OccurrencePosted).
+		Find(&overrides).Error; err != nil {
+		return nil, err
+	}
+
+	overrideByKey := make(map[string]entity.RecurringOccurrence, len(overrides))
+	for _, override := range overrides {
+		overrideByKey[occurrenceKey(override.RecurringTransactionID, override.Date)] = override
+	}
+
+	for _, recurring := range recurrings {
+		for n := recurring.OccurrenceCount; n < recurring.OccurrenceCount+recurringMaxLookahead; n++ {
+			date := utility.RecurrenceDate(recurring.StartDate, recurring.Frequency, recurring.Interval, n)
+			if isOccurrenceOutOfRange(recurring, n, date) || !date.Before(to) {
+				break
+			}
+			amount := recurring.Amount
+			if override, ok := overrideByKey[occurrenceKey(recurring.ID, date)]; ok {
+				if override.Status == entity.OccurrenceSkipped {
+					continue
+				}
+				if override.Amount != nil {
+					amount = *override.Amount
+				}
+			}
+
+			if recurring.Type == "expense" {
+				amount = -amount
+			}
+			// occurrence mulai dari OccurrenceCount belum diposting, yang terlambat
+			// (misalnya scheduler sempat mati) masuk ke bulan from
+			if date.Before(from) {
+				date = from
+			}
+			scheduled[date.Format("2006-01")] += amount
+		}
+	}
+
+	return scheduled, nil
+}
+
+func occurrenceKey(recurringID uint, date time.Time) string {
+	return fmt.Sprintf("%d|%s", recurringID, date.Format("2006-01-02"))
+}
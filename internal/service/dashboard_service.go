@@ -3,6 +3,8 @@ package service
 import (
 	"errors"
 	"fmt"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
 	"go-electroshop/internal/payload/response"
 	"go-electroshop/internal/utility"
 	"sync"
@@ -12,6 +14,15 @@ import (
 	"gorm.io/gorm"
 )
 
+// batas jumlah titik per series, misalnya granularity day maksimal satu tahun
+const maxChartBuckets = 366
+
+var (
+	ErrInvalidChartDate    = errors.New("invalid date format, use YYYY-MM-DD")
+	ErrInvalidChartRange   = errors.New("from date must not be after to date")
+	ErrTooManyChartBuckets = errors.New("date range is too large for the selected granularity")
+)
+
 type DashboardService struct {
 	DB            *gorm.DB
 	dashboardUtil *utility.DashboardUtil
@@ -27,10 +38,19 @@ func NewDashboardService(db *gorm.DB) *DashboardService {
 func (s *DashboardService) GetFinancialOverview(userID uint) (*response.RespFinancialOverview, error) {
 	logrus.Info("Getting financial overview for user: ", userID)
 
-	var overview response.RespFinancialOverview
+	preferences, err := loadUserPreferences(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Failed to get user timezone: %v", err)
+		return nil, errors.New("failed to get financial overview")
+	}
+	today := utility.LocalDate(time.Now(), preferences.Location)
+	monthStart, _ := utility.CurrentBudgetPeriod(today)
+	startOfMonth := monthStart.Format("2006-01-02")
+
+	overview := response.RespFinancialOverview{Currency: preferences.BaseCurrency}
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	errChan := make(chan error, 4)
+	errChan := make(chan error, 6)
 
 	// get current balance
 	wg.Add(1)
@@ -51,7 +71,6 @@ func (s *DashboardService) GetFinancialOverview(userID uint) (*response.RespFina
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		startOfMonth := time.Now().UTC().Format("2006-01-01")
 		income, err := s.dashboardUtil.CalculateMonthlyIncome(userID, startOfMonth)
 		if err != nil {
 			logrus.Errorf("Failed to calculate monthly income: %v", err)
@@ -67,7 +86,6 @@ func (s *DashboardService) GetFinancialOverview(userID uint) (*response.RespFina
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		startOfMonth := time.Now().UTC().Format("2006-01-01")
 		expense, err := s.dashboardUtil.CalculateMonthlyExpense(userID, startOfMonth)
 		if err != nil {
 			errChan <- err
@@ -93,6 +111,44 @@ func (s *DashboardService) GetFinancialOverview(userID uint) (*response.RespFina
 		mu.Unlock()
 	}()
 
+	// get balance per account
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		accounts, err := s.dashboardUtil.GetAccountBalances(userID)
+		if err != nil {
+			logrus.Errorf("Failed to calculate account balances: %v", err)
+			errChan <- err
+			return
+		}
+		if accounts == nil {
+			accounts = []response.AccountBalance{}
+		}
+		mu.Lock()
+		overview.Accounts = accounts
+		mu.Unlock()
+	}()
+
+	// get savings goal progress
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var goals []entity.SavingsGoal
+		if err := s.DB.Where("user_id = ?", userID).Order("id ASC").Find(&goals).Error; err != nil {
+			logrus.Errorf("Failed to get savings goals: %v", err)
+			errChan <- err
+			return
+		}
+		goalResponses, err := buildSavingsGoalResponses(s.dashboardUtil, userID, goals, today)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		mu.Lock()
+		overview.SavingsGoals = goalResponses
+		mu.Unlock()
+	}()
+
 	go func() {
 		wg.Wait()
 		close(errChan)
@@ -108,13 +164,32 @@ func (s *DashboardService) GetFinancialOverview(userID uint) (*response.RespFina
 	return &overview, nil
 }
 
-func (s *DashboardService) GetDashboardCharts(userID uint) (*response.RespDashboardCharts, error) {
+func (s *DashboardService) GetDashboardCharts(userID uint, filter request.DashboardChartsFilter) (*response.RespDashboardCharts, error) {
 	logrus.Info("Getting dashboard charts for user: ", userID)
 
-	var charts response.RespDashboardCharts
+	if filter.Granularity == "" {
+		filter.Granularity = utility.GranularityMonth
+	}
+	if filter.ForecastMonths <= 0 {
+		filter.ForecastMonths = 6
+	}
+
+	preferences, err := loadUserPreferences(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Failed to get user timezone: %v", err)
+		return nil, errors.New("failed to get dashboard charts")
+	}
+	today := utility.LocalDate(time.Now(), preferences.Location)
+
+	buckets, from, to, err := resolveChartRange(filter, today)
+	if err != nil {
+		return nil, err
+	}
+
+	charts := response.RespDashboardCharts{Currency: preferences.BaseCurrency}
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	errChan := make(chan error, 3)
+	errChan := make(chan error, 5)
 
 	defer close(errChan)
 
@@ -122,13 +197,22 @@ func (s *DashboardService) GetDashboardCharts(userID uint) (*response.RespDashbo
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		labels, incomeData, expenseData, err := s.dashboardUtil.GetLastSixMonthsData(userID)
+		series, err := s.dashboardUtil.GetIncomeExpenseSeries(userID, buckets, to, filter.Granularity)
 		if err != nil {
 			logrus.Errorf("Failed to get income vs expense data: %v", err)
 			errChan <- err
 			return
 		}
 
+		labels := make([]string, len(series))
+		incomeData := make([]float64, len(series))
+		expenseData := make([]float64, len(series))
+		for i, total := range series {
+			labels[i] = utility.BucketLabel(total.Bucket, filter.Granularity, preferences.Locale)
+			incomeData[i] = total.Income
+			expenseData[i] = total.Expense
+		}
+
 		mu.Lock()
 		charts.IncomeVsExpense = response.RespIncomeVsExpense{
 			Labels: labels,
@@ -154,7 +238,7 @@ func (s *DashboardService) GetDashboardCharts(userID uint) (*response.RespDashbo
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		labels, data, err := s.dashboardUtil.GetCategoryDistribution(userID)
+		labels, data, err := s.dashboardUtil.GetCategoryDistribution(userID, from, to)
 		if err != nil {
 			logrus.Errorf("Failed to get category distribution data: %v", err)
 			errChan <- err
@@ -184,7 +268,7 @@ func (s *DashboardService) GetDashboardCharts(userID uint) (*response.RespDashbo
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		labels, data, err := s.dashboardUtil.GetTopExpenseCategories(userID, 5)
+		labels, data, err := s.dashboardUtil.GetTopExpenseCategories(userID, from, to, 5)
 		if err != nil {
 			logrus.Errorf("Failed to get top expenses data: %v", err)
 			errChan <- err
@@ -207,6 +291,62 @@ func (s *DashboardService) GetDashboardCharts(userID uint) (*response.RespDashbo
 		mu.Unlock()
 	}()
 
+	// get budget utilization
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		start, end := utility.CurrentBudgetPeriod(today)
+		spendings, err := s.dashboardUtil.GetBudgetSpending(userID, start, end)
+		if err != nil {
+			logrus.Errorf("Failed to get budget utilization data: %v", err)
+			errChan <- err
+			return
+		}
+
+		labels := make([]string, len(spendings))
+		spentData := make([]float64, len(spendings))
+		limitData := make([]float64, len(spendings))
+		for i, spending := range spendings {
+			labels[i] = spending.CategoryName
+			spentData[i] = spending.Spent
+			limitData[i] = spending.Limit
+		}
+
+		mu.Lock()
+		charts.BudgetUtilization = response.BudgetUtilization{
+			Labels: labels,
+			Datasets: []response.ChartDataset{
+				{
+					Label:           "Spent",
+					Data:            spentData,
+					BackgroundColor: "#EF4444",
+				},
+				{
+					Label:           "Budget",
+					Data:            limitData,
+					BackgroundColor: "#3B82F6",
+				},
+			},
+		}
+		mu.Unlock()
+	}()
+
+	// get cash flow forecast
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		forecast, err := s.GetCashFlowForecast(userID, filter.ForecastMonths, today, preferences.Locale)
+		if err != nil {
+			logrus.Errorf("Failed to get cash flow forecast: %v", err)
+			errChan <- err
+			return
+		}
+
+		mu.Lock()
+		charts.CashFlowForecast = *forecast
+		mu.Unlock()
+	}()
+
 	wg.Wait()
 
 	select {
@@ -220,3 +360,36 @@ func (s *DashboardService) GetDashboardCharts(userID uint) (*response.RespDashbo
 	logrus.Info("Successfully retrieved dashboard charts")
 	return &charts, nil
 }
+
+// resolveChartRange mengubah filter chart menjadi rentang [from, to) beserta bucket-nya.
+// Default-nya enam bulan terakhir termasuk bulan berjalan, today adalah tanggal lokal user.
+func resolveChartRange(filter request.DashboardChartsFilter, today time.Time) ([]time.Time, time.Time, time.Time, error) {
+	from := today.AddDate(0, 0, 1-today.Day()).AddDate(0, -5, 0)
+	to := today.AddDate(0, 0, 1)
+
+	if filter.From != "" {
+		parsed, err := time.Parse("2006-01-02", filter.From)
+		if err != nil {
+			return nil, from, to, ErrInvalidChartDate
+		}
+		from = parsed
+	}
+	if filter.To != "" {
+		parsed, err := time.Parse("2006-01-02", filter.To)
+		if err != nil {
+			return nil, from, to, ErrInvalidChartDate
+		}
+		// to inklusif di request, eksklusif di query
+		to = parsed.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		return nil, from, to, ErrInvalidChartRange
+	}
+
+	buckets, ok := utility.Buckets(from, to, filter.Granularity, maxChartBuckets)
+	if !ok {
+		return nil, from, to, ErrTooManyChartBuckets
+	}
+
+	return buckets, from, to, nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/exchangerate"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+type ExchangeRateService struct {
+	DB       *gorm.DB
+	Provider exchangerate.Provider // nil berarti hanya kurs yang diinput user
+}
+
+func NewExchangeRateService(db *gorm.DB, provider exchangerate.Provider) *ExchangeRateService {
+	return &ExchangeRateService{DB: db, Provider: provider}
+}
+
+func (s *ExchangeRateService) GetExchangeRates(userID uint, filter request.ExchangeRateFilter) ([]response.ExchangeRateResponse, error) {
+	query := s.DB.Where("user_id = ?", userID)
+	if filter.Currency != "" {
+		currency, err := utility.NormalizeCurrency(filter.Currency)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("from_currency = ? OR to_currency = ?", currency, currency)
+	}
+
+	var rates []entity.ExchangeRate
+	if err := query.Order("date DESC, from_currency ASC, to_currency ASC").Find(&rates).Error; err != nil {
+		logrus.Errorf("Error getting exchange rates: %v", err)
+		return nil, errors.New("failed to get exchange rates")
+	}
+
+	rateResponses := make([]response.ExchangeRateResponse, len(rates))
+	for i, rate := range rates {
+		rateResponses[i] = toExchangeRateResponse(rate)
+	}
+
+	return rateResponses, nil
+}
+
+// SaveExchangeRate menyimpan kurs manual, kurs dengan pasangan dan tanggal yang sama ditimpa
+func (s *ExchangeRateService) SaveExchangeRate(userID uint, req request.ExchangeRateRequest) (*response.ExchangeRateResponse, error) {
+	fromCurrency, err := utility.NormalizeCurrency(req.FromCurrency)
+	if err != nil {
+		return nil, err
+	}
+	toCurrency, err := utility.NormalizeCurrency(req.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if fromCurrency == toCurrency {
+		return nil, errors.New("exchange rate currencies must be different")
+	}
+
+	preferences, err := loadUserPreferences(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Error getting user timezone: %v", err)
+		return nil, errors.New("failed to save exchange rate")
+	}
+
+	date := utility.LocalDate(time.Now(), preferences.Location)
+	if req.Date != "" {
+		date, err = utility.ParseLocalDate(req.Date, preferences.Location)
+		if err != nil {
+			return nil, errors.New("invalid date format")
+		}
+	}
+
+	rate := entity.ExchangeRate{
+		UserID:       userID,
+		FromCurrency: fromCurrency,
+		ToCurrency:   toCurrency,
+		Date:         date,
+		Rate:         req.Rate,
+		Source:       entity.ExchangeRateManual,
+	}
+
+	if err := s.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "from_currency"}, {Name: "to_currency"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"rate":       req.Rate,
+			"source":     entity.ExchangeRateManual,
+			"updated_at": time.Now(),
+		}),
+	}).Create(&rate).Error; err != nil {
+		logrus.Errorf("Error saving exchange rate: %v", err)
+		return nil, errors.New("failed to save exchange rate")
+	}
+
+	if err := s.DB.Where("user_id = ? AND from_currency = ? AND to_currency = ? AND date = ?", userID, fromCurrency, toCurrency, date).
+		First(&rate).Error; err != nil {
+		logrus.Errorf("Error getting exchange rate: %v", err)
+		return nil, errors.New("failed to save exchange rate")
+	}
+
+	rateResponse := toExchangeRateResponse(rate)
+	return &rateResponse, nil
+}
+
+func (s *ExchangeRateService) DeleteExchangeRate(userID, rateID uint) error {
+	result := s.DB.Where("id = ? AND user_id = ?", rateID, userID).Delete(&entity.ExchangeRate{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting exchange rate: %v", result.Error)
+		return errors.New("failed to delete exchange rate")
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrExchangeRateNotFound
+	}
+
+	return nil
+}
+
+// ensureExchangeRate memastikan user punya kurs from ke to (atau kebalikannya) yang berlaku
+// pada date. Jika belum ada, kurs diambil dari rate provider lalu disimpan sebagai kurs user.
+func ensureExchangeRate(db *gorm.DB, provider exchangerate.Provider, userID uint, from, to string, date time.Time) error {
+	var count int64
+	if err := db.Model(&entity.ExchangeRate{}).
+		Where("user_id = ? AND date <= ?", userID, date).
+		Where("(from_currency = ? AND to_currency = ?) OR (from_currency = ? AND to_currency = ?)", from, to, to, from).
+		Count(&count).Error; err != nil {
+		logrus.Errorf("Error checking exchange rate: %v", err)
+		return errors.New("failed to get exchange rate")
+	}
+	if count > 0 {
+		return nil
+	}
+
+	missing := fmt.Errorf("no exchange rate from %s to %s on %s, add one first", from, to, date.Format("2006-01-02"))
+	if provider == nil {
+		return missing
+	}
+
+	value, err := provider.Rate(context.Background(), from, to, date)
+	if err != nil {
+		if errors.Is(err, exchangerate.ErrRateNotFound) {
+			return missing
+		}
+		logrus.Errorf("Error fetching exchange rate from %s provider: %v", provider.Name(), err)
+		return errors.New("failed to get exchange rate")
+	}
+
+	rate := entity.ExchangeRate{
+		UserID:       userID,
+		FromCurrency: from,
+		ToCurrency:   to,
+		Date:         date,
+		Rate:         value,
+		Source:       provider.Name(),
+	}
+	if err := db.Create(&rate).Error; err != nil {
+		logrus.Errorf("Error saving exchange rate: %v", err)
+		return errors.New("failed to save exchange rate")
+	}
+
+	return nil
+}
+
+func toExchangeRateResponse(rate entity.ExchangeRate) response.ExchangeRateResponse {
+	return response.ExchangeRateResponse{
+		ID:           rate.ID,
+		FromCurrency: rate.FromCurrency,
+		ToCurrency:   rate.ToCurrency,
+		Rate:         rate.Rate,
+		Date:         rate.Date,
+		Source:       rate.Source,
+		UpdatedAt:    rate.UpdatedAt,
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrGiftCardNotFound = errors.New("gift card not found")
+	ErrGiftCardUnusable = errors.New("gift card is disabled, expired or has no balance")
+)
+
+// reference usage saat gift card diterbitkan, tidak boleh dipakai sebagai reference checkout
+const giftCardIssueReference = "issue"
+
+type GiftCardService struct {
+	DB *gorm.DB
+}
+
+func NewGiftCardService(db *gorm.DB) *GiftCardService {
+	return &GiftCardService{DB: db}
+}
+
+// Issue membuat gift card baru. Kode asli hanya dikembalikan sekali di sini.
+func (s *GiftCardService) Issue(adminID uint, req request.IssueGiftCardRequest) (*response.GiftCardResponse, error) {
+	var expiresAt *time.Time
+	if req.ExpiresAt != "" {
+		parsed, err := time.Parse("2006-01-02", req.ExpiresAt)
+		if err != nil {
+			return nil, errors.New("invalid expires_at format")
+		}
+		// berlaku sampai akhir hari tersebut
+		endOfDay := parsed.AddDate(0, 0, 1).Add(-time.Second)
+		if endOfDay.Before(time.Now()) {
+			return nil, errors.New("expires_at must be in the future")
+		}
+		expiresAt = &endOfDay
+	}
+
+	amount := math.Round(req.Amount*100) / 100
+
+	// ulangi jika kode kebetulan sudah dipakai
+	for attempt := 0; attempt < 3; attempt++ {
+		code, err := utility.GenerateGiftCardCode()
+		if err != nil {
+			logrus.Errorf("Error generating gift card code: %v", err)
+			return nil, errors.New("failed to generate gift card code")
+		}
+
+		codeHash := utility.HashGiftCardCode(code)
+		var existing int64
+		if err := s.DB.Model(&entity.GiftCard{}).Where("code_hash = ?", codeHash).Count(&existing).Error; err != nil {
+			logrus.Errorf("Error checking gift card code: %v", err)
+			return nil, errors.New("failed to issue gift card")
+		}
+		if existing > 0 {
+			continue
+		}
+
+		normalized := utility.NormalizeGiftCardCode(code)
+		giftCard := entity.GiftCard{
+			CodeHash:       codeHash,
+			CodeLast4:      normalized[len(normalized)-4:],
+			InitialBalance: amount,
+			Balance:        amount,
+			IssuedBy:       adminID,
+			Note:           strings.TrimSpace(req.Note),
+			ExpiresAt:      expiresAt,
+		}
+
+		err = s.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&giftCard).Error; err != nil {
+				return err
+			}
+
+			return tx.Create(&entity.GiftCardUsage{
+				GiftCardID:   giftCard.ID,
+				Amount:       amount,
+				BalanceAfter: amount,
+				Reference:    giftCardIssueReference,
+			}).Error
+		})
+		if err != nil {
+			logrus.Errorf("Error issuing gift card: %v", err)
+			return nil, errors.New("failed to issue gift card")
+		}
+
+		giftCardResponse := toGiftCardResponse(giftCard)
+		giftCardResponse.Code = code
+		return &giftCardResponse, nil
+	}
+
+	return nil, errors.New("failed to generate unique gift card code")
+}
+
+func (s *GiftCardService) GetGiftCards(filter request.GiftCardFilter) (*response.GiftCardListResponse, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+
+	var total int64
+	if err := s.DB.Model(&entity.GiftCard{}).Count(&total).Error; err != nil {
+		logrus.Errorf("Error counting gift cards: %v", err)
+		return nil, errors.New("failed to get gift cards")
+	}
+
+	var giftCards []entity.GiftCard
+	if err := s.DB.Order("created_at DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&giftCards).Error; err != nil {
+		logrus.Errorf("Error getting gift cards: %v", err)
+		return nil, errors.New("failed to get gift cards")
+	}
+
+	giftCardResponses := make([]response.GiftCardResponse, len(giftCards))
+	for i, giftCard := range giftCards {
+		giftCardResponses[i] = toGiftCardResponse(giftCard)
+	}
+
+	return &response.GiftCardListResponse{
+		GiftCards: giftCardResponses,
+		Pagination: response.Pagination{
+			CurrentPage: filter.Page,
+			TotalPage:   int(math.Ceil(float64(total) / float64(filter.Limit))),
+			TotalItems:  total,
+			ItemPerPage: filter.Limit,
+		},
+	}, nil
+}
+
+func (s *GiftCardService) DisableGiftCard(giftCardID uint) error {
+	result := s.DB.Model(&entity.GiftCard{}).Where("id = ?", giftCardID).Update("disabled", true)
+	if result.Error != nil {
+		logrus.Errorf("Error disabling gift card: %v", result.Error)
+		return errors.New("failed to disable gift card")
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrGiftCardNotFound
+	}
+
+	return nil
+}
+
+func (s *GiftCardService) CheckBalance(code string) (*response.GiftCardBalanceResponse, error) {
+	var giftCard entity.GiftCard
+	if err := s.DB.Where("code_hash = ?", utility.HashGiftCardCode(code)).First(&giftCard).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGiftCardNotFound
+		}
+		logrus.Errorf("Error getting gift card: %v", err)
+		return nil, errors.New("failed to get gift card")
+	}
+
+	return &response.GiftCardBalanceResponse{
+		MaskedCode: maskGiftCardCode(giftCard.CodeLast4),
+		Balance:    giftCard.Balance,
+		ExpiresAt:  giftCard.ExpiresAt,
+		Usable:     isGiftCardUsable(giftCard),
+	}, nil
+}
+
+// Redeem memakai saldo gift card untuk pembayaran di checkout. Jika saldo kurang dari
+// amount, hanya sisa saldo yang dipakai. Row gift card dikunci selama transaksi
+// sehingga checkout yang berjalan bersamaan tidak bisa memakai saldo yang sama dua kali,
+// dan retry dengan reference yang sama mengembalikan hasil sebelumnya.
+// Belum ada pemanggilnya karena repo ini belum punya alur checkout, integrasinya menyusul
+// bersama fitur checkout.
+func (s *GiftCardService) Redeem(userID uint, code string, amount float64, reference string) (*response.GiftCardRedemptionResponse, error) {
+	if amount <= 0 {
+		return nil, errors.New("amount must be greater than 0")
+	}
+	if reference == "" {
+		return nil, errors.New("reference is required")
+	}
+	if reference == giftCardIssueReference {
+		return nil, errors.New("reference is reserved")
+	}
+
+	var result response.GiftCardRedemptionResponse
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var giftCard entity.GiftCard
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("code_hash = ?", utility.HashGiftCardCode(code)).
+			First(&giftCard).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrGiftCardNotFound
+			}
+			return err
+		}
+
+		result.MaskedCode = maskGiftCardCode(giftCard.CodeLast4)
+		result.Reference = reference
+
+		// hanya usage pemakaian saldo (amount negatif) yang dianggap redeem sebelumnya
+		var existing entity.GiftCardUsage
+		if err := tx.Where("gift_card_id = ? AND reference = ? AND amount < 0", giftCard.ID, reference).
+			Limit(1).
+			Find(&existing).Error; err != nil {
+			return err
+		}
+		if existing.ID != 0 {
+			result.AppliedAmount = -existing.Amount
+			result.RemainingBalance = giftCard.Balance
+			return nil
+		}
+
+		if !isGiftCardUsable(giftCard) {
+			return ErrGiftCardUnusable
+		}
+
+		applied := math.Min(math.Round(amount*100)/100, giftCard.Balance)
+		remaining := math.Round((giftCard.Balance-applied)*100) / 100
+
+		update := tx.Model(&entity.GiftCard{}).
+			Where("id = ? AND balance >= ?", giftCard.ID, applied).
+			Update("balance", remaining)
+		if update.Error != nil {
+			return update.Error
+		}
+		if update.RowsAffected == 0 {
+			return ErrGiftCardUnusable
+		}
+
+		if err := tx.Create(&entity.GiftCardUsage{
+			GiftCardID:   giftCard.ID,
+			UserID:       &userID,
+			Amount:       -applied,
+			BalanceAfter: remaining,
+			Reference:    reference,
+		}).Error; err != nil {
+			return err
+		}
+
+		result.AppliedAmount = applied
+		result.RemainingBalance = remaining
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrGiftCardNotFound) || errors.Is(err, ErrGiftCardUnusable) {
+			return nil, err
+		}
+		logrus.Errorf("Error redeeming gift card: %v", err)
+		return nil, errors.New("failed to redeem gift card")
+	}
+
+	return &result, nil
+}
+
+func isGiftCardUsable(giftCard entity.GiftCard) bool {
+	if giftCard.Disabled || giftCard.Balance <= 0 {
+		return false
+	}
+	if giftCard.ExpiresAt != nil && giftCard.ExpiresAt.Before(time.Now()) {
+		return false
+	}
+	return true
+}
+
+func maskGiftCardCode(last4 string) string {
+	return "XXXX-XXXX-XXXX-" + last4
+}
+
+func toGiftCardResponse(giftCard entity.GiftCard) response.GiftCardResponse {
+	return response.GiftCardResponse{
+		ID:             giftCard.ID,
+		MaskedCode:     maskGiftCardCode(giftCard.CodeLast4),
+		InitialBalance: giftCard.InitialBalance,
+		Balance:        giftCard.Balance,
+		Note:           giftCard.Note,
+		ExpiresAt:      giftCard.ExpiresAt,
+		Disabled:       giftCard.Disabled,
+		CreatedAt:      giftCard.CreatedAt,
+	}
+}
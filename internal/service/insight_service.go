@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/report"
+	"go-electroshop/internal/utility"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInsightNotFound = errors.New("insight not found")
+
+const (
+	// jumlah bulan penuh sebelum bulan berjalan yang dipakai sebagai baseline
+	insightHistoryMonths = 6
+	// insight bulan-bulan sebelumnya tetap ada di feed selama rentang ini
+	insightRetentionMonths = 3
+)
+
+// urutan feed, yang paling penting di atas
+const insightSeverityOrder = "CASE severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END"
+
+type InsightService struct {
+	DB            *gorm.DB
+	dashboardUtil *utility.DashboardUtil
+}
+
+func NewInsightService(db *gorm.DB) *InsightService {
+	return &InsightService{
+		DB:            db,
+		dashboardUtil: &utility.DashboardUtil{DB: db},
+	}
+}
+
+// GetInsights mengembalikan insights feed user, insight yang sudah di-dismiss disembunyikan
+// kecuali diminta
+func (s *InsightService) GetInsights(userID uint, filter request.InsightFilter) ([]response.InsightResponse, error) {
+	preferences, err := loadUserPreferences(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Error getting user preferences: %v", err)
+		return nil, errors.New("failed to get insights")
+	}
+
+	query := s.DB.Where("user_id = ?", userID)
+	if !filter.IncludeDismissed {
+		query = query.Where("dismissed_at IS NULL")
+	}
+
+	var insights []entity.Insight
+	if err := query.Order(insightSeverityOrder).Order("period DESC, amount DESC").Find(&insights).Error; err != nil {
+		logrus.Errorf("Error getting insights: %v", err)
+		return nil, errors.New("failed to get insights")
+	}
+
+	result := make([]response.InsightResponse, 0, len(insights))
+	for _, insight := range insights {
+		result = append(result, response.InsightResponse{
+			ID:            insight.ID,
+			Kind:          insight.Kind,
+			Severity:      insight.Severity,
+			Message:       insight.Message,
+			CategoryID:    insight.CategoryID,
+			TransactionID: insight.TransactionID,
+			Period:        insight.Period.Format("2006-01"),
+			Amount:        insight.Amount,
+			Baseline:      insight.Baseline,
+			Currency:      preferences.BaseCurrency,
+			DismissedAt:   insight.DismissedAt,
+			CreatedAt:     insight.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// DismissInsight menyembunyikan insight dari feed. Insight yang sama tidak muncul lagi saat
+// dihitung ulang karena status dismiss disimpan per key.
+func (s *InsightService) DismissInsight(userID, insightID uint) error {
+	update := s.DB.Model(&entity.Insight{}).
+		Where("id = ? AND user_id = ?", insightID, userID).
+		UpdateColumn("dismissed_at", gorm.Expr("COALESCE(dismissed_at, ?)", time.Now()))
+	if update.Error != nil {
+		logrus.Errorf("Error dismissing insight: %v", update.Error)
+		return errors.New("failed to dismiss insight")
+	}
+	if update.RowsAffected == 0 {
+		return ErrInsightNotFound
+	}
+
+	return nil
+}
+
+// RefreshInsights dijalankan scheduler untuk menghitung ulang insight bulan berjalan semua
+// user yang punya pengeluaran. Kegagalan satu user tidak menghentikan user lain.
+func (s *InsightService) RefreshInsights(ctx context.Context) error {
+	now := time.Now()
+	db := s.DB.WithContext(ctx)
+
+	var userIDs []uint
+	if err := db.Model(&entity.Transaction{}).
+		Where("type = ? AND date >= ?", "expense", now.AddDate(0, -(insightHistoryMonths+1), 0)).
+		Distinct().
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return fmt.Errorf("failed to get users: %v", err)
+	}
+	// user yang sudah tidak punya pengeluaran tetap diproses supaya insight lamanya dibersihkan
+	var insightUserIDs []uint
+	if err := db.Model(&entity.Insight{}).Distinct().Pluck("user_id", &insightUserIDs).Error; err != nil {
+		return fmt.Errorf("failed to get users: %v", err)
+	}
+
+	for _, userID := range mergeUserIDs(userIDs, insightUserIDs) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := s.refreshUserInsights(ctx, userID, now); err != nil {
+			logrus.Errorf("Failed to refresh insights for user %d: %v", userID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *InsightService) refreshUserInsights(ctx context.Context, userID uint, now time.Time) error {
+	preferences, err := loadUserPreferences(s.DB, userID)
+	if err != nil {
+		return err
+	}
+
+	monthStart := utility.BucketStart(utility.LocalDate(now, preferences.Location), utility.GranularityMonth)
+	history, err := s.dashboardUtil.GetExpenseLines(userID, monthStart.AddDate(0, -insightHistoryMonths, 0), monthStart)
+	if err != nil {
+		return err
+	}
+	current, err := s.dashboardUtil.GetExpenseLines(userID, monthStart, monthStart.AddDate(0, 1, 0))
+	if err != nil {
+		return err
+	}
+
+	detected := utility.DetectSpendingInsights(history, current, monthStart)
+	insights := make([]entity.Insight, 0, len(detected))
+	keys := make([]string, 0, len(detected))
+	for _, d := range detected {
+		insight := entity.Insight{
+			UserID:     userID,
+			Key:        d.Key,
+			Kind:       d.Kind,
+			Severity:   d.Severity,
+			Message:    insightMessage(d, preferences),
+			CategoryID: &d.CategoryID,
+			Period:     monthStart,
+			Amount:     d.Amount,
+			Baseline:   d.Baseline,
+		}
+		if d.TransactionID != 0 {
+			insight.TransactionID = &d.TransactionID
+		}
+		insights = append(insights, insight)
+		keys = append(keys, d.Key)
+	}
+
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(insights) > 0 {
+			// dismissed_at sengaja tidak ikut diperbarui
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"kind", "severity", "message", "category_id", "transaction_id", "period", "amount", "baseline", "updated_at"}),
+			}).Create(&insights).Error; err != nil {
+				return err
+			}
+		}
+
+		// insight bulan berjalan yang sudah tidak terdeteksi, mis. transaksinya dihapus
+		stale := tx.Where("user_id = ? AND period >= ?", userID, monthStart)
+		if len(keys) > 0 {
+			stale = stale.Where("key NOT IN ?", keys)
+		}
+		if err := stale.Delete(&entity.Insight{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND period < ?", userID, monthStart.AddDate(0, -insightRetentionMonths, 0)).
+			Delete(&entity.Insight{}).Error
+	})
+}
+
+func insightMessage(insight utility.SpendingInsight, preferences *userPreferences) string {
+	amount := preferences.BaseCurrency + " " + report.FormatAmount(insight.Amount, preferences.Locale)
+	baseline := preferences.BaseCurrency + " " + report.FormatAmount(insight.Baseline, preferences.Locale)
+
+	if insight.Kind == entity.InsightKindUnusualTransaction {
+		return fmt.Sprintf("Unusual %s expense of %s on %s, %.1fx your typical %s",
+			insight.Category, amount, insight.Date.Format("2 Jan 2006"), insight.Ratio(), baseline)
+	}
+
+	increase := math.Round((insight.Ratio() - 1) * 100)
+	return fmt.Sprintf("You spent %.0f%% more on %s in %s: %s so far vs %s in a typical month",
+		increase, insight.Category, utility.BucketLabel(insight.Date, utility.GranularityMonth, preferences.Locale), amount, baseline)
+}
+
+func mergeUserIDs(groups ...[]uint) []uint {
+	seen := map[uint]bool{}
+	var userIDs []uint
+	for _, group := range groups {
+		for _, userID := range group {
+			if !seen[userID] {
+				seen[userID] = true
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+	return userIDs
+}
@@ -0,0 +1,678 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/notifier"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrLedgerNotFound           = errors.New("ledger not found")
+	ErrLedgerReadOnly           = errors.New("viewers cannot change this ledger")
+	ErrLedgerOwnerOnly          = errors.New("only the ledger owner can do this")
+	ErrPersonalLedger           = errors.New("personal ledger cannot be shared or deleted")
+	ErrLedgerNotEmpty           = errors.New("ledger still has transactions, delete or purge them first")
+	ErrLedgerMemberNotFound     = errors.New("ledger member not found")
+	ErrLedgerMemberExists       = errors.New("user is already a member of this ledger")
+	ErrLedgerInvitationNotFound = errors.New("invitation not found")
+)
+
+// masa berlaku undangan ledger
+const ledgerInvitationTTL = 7 * 24 * time.Hour
+
+type LedgerService struct {
+	DB            *gorm.DB
+	Notifier      notifier.Notifier
+	dashboardUtil *utility.DashboardUtil
+}
+
+func NewLedgerService(db *gorm.DB, n notifier.Notifier) *LedgerService {
+	return &LedgerService{
+		DB:            db,
+		Notifier:      n,
+		dashboardUtil: &utility.DashboardUtil{DB: db},
+	}
+}
+
+// GetLedgers mengembalikan semua ledger yang bisa diakses user, personal ledger paling atas
+func (s *LedgerService) GetLedgers(userID uint) ([]response.LedgerResponse, error) {
+	// user yang register setelah migrasi belum punya personal ledger
+	if _, err := personalLedgerID(s.DB, userID); err != nil {
+		logrus.Errorf("Error getting personal ledger: %v", err)
+		return nil, errors.New("failed to get ledgers")
+	}
+
+	var ledgers []response.LedgerResponse
+	if err := s.DB.Table("ledgers").
+		Select("ledgers.id, ledgers.name, ledgers.personal, ledgers.owner_id, ledgers.created_at, ledger_members.role, "+
+			"(SELECT COUNT(*) FROM ledger_members AS members WHERE members.ledger_id = ledgers.id) AS member_count").
+		Joins("JOIN ledger_members ON ledger_members.ledger_id = ledgers.id").
+		Where("ledger_members.user_id = ? AND ledgers.deleted_at IS NULL", userID).
+		Order("ledgers.personal DESC, ledgers.name ASC").
+		Scan(&ledgers).Error; err != nil {
+		logrus.Errorf("Error getting ledgers: %v", err)
+		return nil, errors.New("failed to get ledgers")
+	}
+
+	return ledgers, nil
+}
+
+// GetLedger mengembalikan ledger beserta member-nya, undangan yang belum diterima hanya
+// ditampilkan untuk owner
+func (s *LedgerService) GetLedger(userID, ledgerID uint) (*response.LedgerDetailResponse, error) {
+	ledger, role, err := s.memberLedger(userID, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []entity.LedgerMember
+	if err := s.DB.Preload("User").
+		Where("ledger_id = ?", ledgerID).
+		Order("created_at ASC").
+		Find(&members).Error; err != nil {
+		logrus.Errorf("Error getting ledger members: %v", err)
+		return nil, errors.New("failed to get ledger")
+	}
+
+	result := &response.LedgerDetailResponse{
+		LedgerResponse: toLedgerResponse(*ledger, role, int64(len(members))),
+		Members:        make([]response.LedgerMemberResponse, len(members)),
+	}
+	for i, member := range members {
+		result.Members[i] = response.LedgerMemberResponse{
+			UserID:   member.UserID,
+			Name:     member.User.Name,
+			Email:    member.User.Email,
+			Role:     member.Role,
+			JoinedAt: member.CreatedAt,
+		}
+	}
+
+	if role == entity.LedgerRoleOwner {
+		var invitations []entity.LedgerInvitation
+		if err := s.DB.Where("ledger_id = ? AND expires_at > ?", ledgerID, time.Now()).
+			Order("created_at ASC").
+			Find(&invitations).Error; err != nil {
+			logrus.Errorf("Error getting ledger invitations: %v", err)
+			return nil, errors.New("failed to get ledger")
+		}
+		for _, invitation := range invitations {
+			invitation.Ledger = *ledger
+			result.Invitations = append(result.Invitations, toLedgerInvitationResponse(invitation))
+		}
+	}
+
+	return result, nil
+}
+
+// CreateLedger membuat ledger bersama dengan user sebagai owner
+func (s *LedgerService) CreateLedger(userID uint, req request.LedgerRequest) (*response.LedgerResponse, error) {
+	ledger := entity.Ledger{
+		Name:    strings.TrimSpace(req.Name),
+		OwnerID: userID,
+		Members: []entity.LedgerMember{{UserID: userID, Role: entity.LedgerRoleOwner}},
+	}
+	if err := s.DB.Create(&ledger).Error; err != nil {
+		logrus.Errorf("Error creating ledger: %v", err)
+		return nil, errors.New("failed to create ledger")
+	}
+
+	result := toLedgerResponse(ledger, entity.LedgerRoleOwner, 1)
+	return &result, nil
+}
+
+func (s *LedgerService) UpdateLedger(userID, ledgerID uint, req request.LedgerRequest) (*response.LedgerResponse, error) {
+	ledger, err := s.ownedLedger(userID, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger.Name = strings.TrimSpace(req.Name)
+	if err := s.DB.Save(ledger).Error; err != nil {
+		logrus.Errorf("Error updating ledger: %v", err)
+		return nil, errors.New("failed to update ledger")
+	}
+
+	var memberCount int64
+	if err := s.DB.Model(&entity.LedgerMember{}).Where("ledger_id = ?", ledgerID).Count(&memberCount).Error; err != nil {
+		logrus.Errorf("Error counting ledger members: %v", err)
+		return nil, errors.New("failed to update ledger")
+	}
+
+	result := toLedgerResponse(*ledger, entity.LedgerRoleOwner, memberCount)
+	return &result, nil
+}
+
+// DeleteLedger menghapus ledger bersama yang sudah tidak punya transaksi, termasuk yang ada di
+// trash. Kategori ledger ikut dihapus.
+func (s *LedgerService) DeleteLedger(userID, ledgerID uint) error {
+	ledger, err := s.ownedLedger(userID, ledgerID)
+	if err != nil {
+		return err
+	}
+	if ledger.Personal {
+		return ErrPersonalLedger
+	}
+
+	var transactionCount int64
+	if err := s.DB.Unscoped().Model(&entity.Transaction{}).Where("ledger_id = ?", ledgerID).Count(&transactionCount).Error; err != nil {
+		logrus.Errorf("Error counting ledger transactions: %v", err)
+		return errors.New("failed to delete ledger")
+	}
+	if transactionCount > 0 {
+		return ErrLedgerNotEmpty
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ledger_id = ?", ledgerID).Delete(&entity.LedgerInvitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ledger_id = ?", ledgerID).Delete(&entity.LedgerMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ledger_id = ?", ledgerID).Delete(&entity.Category{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(ledger).Error
+	})
+	if err != nil {
+		logrus.Errorf("Error deleting ledger: %v", err)
+		return errors.New("failed to delete ledger")
+	}
+
+	return nil
+}
+
+// InviteMember mengundang email untuk join ledger. Undangan ulang ke email yang sama
+// memperbarui role dan masa berlakunya. Jika email sudah terdaftar, user tersebut diberi notifikasi.
+func (s *LedgerService) InviteMember(ctx context.Context, userID, ledgerID uint, req request.LedgerInvitationRequest) (*response.LedgerInvitationResponse, error) {
+	ledger, err := s.ownedLedger(userID, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if ledger.Personal {
+		return nil, ErrPersonalLedger
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	var invitees []entity.User
+	if err := s.DB.Select("id").Where("LOWER(email) = ?", email).Limit(1).Find(&invitees).Error; err != nil {
+		logrus.Errorf("Error getting invited user: %v", err)
+		return nil, errors.New("failed to invite member")
+	}
+	if len(invitees) > 0 {
+		role, err := ledgerRole(s.DB, invitees[0].ID, ledgerID)
+		if err != nil {
+			logrus.Errorf("Error getting ledger member: %v", err)
+			return nil, errors.New("failed to invite member")
+		}
+		if role != "" {
+			return nil, ErrLedgerMemberExists
+		}
+	}
+
+	invitation := entity.LedgerInvitation{
+		LedgerID:  ledgerID,
+		Email:     email,
+		Role:      req.Role,
+		InvitedBy: userID,
+		ExpiresAt: time.Now().Add(ledgerInvitationTTL),
+		Ledger:    *ledger,
+	}
+	if err := s.DB.Omit("Ledger").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ledger_id"}, {Name: "email"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "invited_by", "expires_at", "updated_at"}),
+	}).Create(&invitation).Error; err != nil {
+		logrus.Errorf("Error creating ledger invitation: %v", err)
+		return nil, errors.New("failed to invite member")
+	}
+
+	if len(invitees) > 0 {
+		msg := notifier.Message{
+			Subject: "Invitation to " + ledger.Name,
+			Body: fmt.Sprintf("You have been invited to join the ledger %q as %s. The invitation expires on %s.",
+				ledger.Name, req.Role, invitation.ExpiresAt.Format("2 Jan 2006")),
+		}
+		// undangan tetap berlaku walaupun notifikasi gagal, user bisa melihatnya di list undangan
+		if err := s.Notifier.Notify(ctx, invitees[0].ID, msg); err != nil {
+			logrus.Errorf("Failed to notify ledger invitation to user %d: %v", invitees[0].ID, err)
+		}
+	}
+
+	result := toLedgerInvitationResponse(invitation)
+	return &result, nil
+}
+
+// RevokeInvitation membatalkan undangan yang belum diterima
+func (s *LedgerService) RevokeInvitation(userID, ledgerID, invitationID uint) error {
+	if _, err := s.ownedLedger(userID, ledgerID); err != nil {
+		return err
+	}
+
+	result := s.DB.Where("id = ? AND ledger_id = ?", invitationID, ledgerID).Delete(&entity.LedgerInvitation{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting ledger invitation: %v", result.Error)
+		return errors.New("failed to revoke invitation")
+	}
+	if result.RowsAffected == 0 {
+		return ErrLedgerInvitationNotFound
+	}
+
+	return nil
+}
+
+// GetInvitations mengembalikan undangan ledger yang masih berlaku untuk email user
+func (s *LedgerService) GetInvitations(userID uint) ([]response.LedgerInvitationResponse, error) {
+	email, err := s.userEmail(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var invitations []entity.LedgerInvitation
+	if err := s.DB.Preload("Ledger").
+		Where("email = ? AND expires_at > ?", email, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		logrus.Errorf("Error getting ledger invitations: %v", err)
+		return nil, errors.New("failed to get invitations")
+	}
+
+	result := make([]response.LedgerInvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		result[i] = toLedgerInvitationResponse(invitation)
+	}
+
+	return result, nil
+}
+
+// AcceptInvitation menjadikan user member ledger sesuai role di undangan
+func (s *LedgerService) AcceptInvitation(userID, invitationID uint) error {
+	email, err := s.userEmail(userID)
+	if err != nil {
+		return err
+	}
+
+	var invitation entity.LedgerInvitation
+	if err := s.DB.Where("id = ? AND email = ? AND expires_at > ?", invitationID, email, time.Now()).
+		First(&invitation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrLedgerInvitationNotFound
+		}
+		logrus.Errorf("Error getting ledger invitation: %v", err)
+		return errors.New("failed to accept invitation")
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// user yang sudah jadi member tetap dengan role lamanya
+		member := entity.LedgerMember{LedgerID: invitation.LedgerID, UserID: userID, Role: invitation.Role}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&member).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&invitation).Error
+	})
+	if err != nil {
+		logrus.Errorf("Error accepting ledger invitation: %v", err)
+		return errors.New("failed to accept invitation")
+	}
+
+	return nil
+}
+
+func (s *LedgerService) DeclineInvitation(userID, invitationID uint) error {
+	email, err := s.userEmail(userID)
+	if err != nil {
+		return err
+	}
+
+	result := s.DB.Where("id = ? AND email = ?", invitationID, email).Delete(&entity.LedgerInvitation{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting ledger invitation: %v", result.Error)
+		return errors.New("failed to decline invitation")
+	}
+	if result.RowsAffected == 0 {
+		return ErrLedgerInvitationNotFound
+	}
+
+	return nil
+}
+
+// UpdateMemberRole mengubah role member selain owner
+func (s *LedgerService) UpdateMemberRole(userID, ledgerID, memberID uint, req request.LedgerMemberRequest) error {
+	ledger, err := s.ownedLedger(userID, ledgerID)
+	if err != nil {
+		return err
+	}
+	if memberID == ledger.OwnerID {
+		return errors.New("owner role cannot be changed")
+	}
+
+	result := s.DB.Model(&entity.LedgerMember{}).
+		Where("ledger_id = ? AND user_id = ?", ledgerID, memberID).
+		UpdateColumns(map[string]interface{}{"role": req.Role, "updated_at": time.Now()})
+	if result.Error != nil {
+		logrus.Errorf("Error updating ledger member: %v", result.Error)
+		return errors.New("failed to update member")
+	}
+	if result.RowsAffected == 0 {
+		return ErrLedgerMemberNotFound
+	}
+
+	return nil
+}
+
+// RemoveMember mengeluarkan member dari ledger. Owner bisa mengeluarkan siapa saja, member lain
+// hanya bisa keluar sendiri. Transaksi yang sudah dicatat member tetap ada di ledger.
+func (s *LedgerService) RemoveMember(userID, ledgerID, memberID uint) error {
+	ledger, role, err := s.memberLedger(userID, ledgerID)
+	if err != nil {
+		return err
+	}
+	if memberID == ledger.OwnerID {
+		return errors.New("owner cannot leave the ledger")
+	}
+	if memberID != userID && role != entity.LedgerRoleOwner {
+		return ErrLedgerOwnerOnly
+	}
+
+	result := s.DB.Where("ledger_id = ? AND user_id = ?", ledgerID, memberID).Delete(&entity.LedgerMember{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting ledger member: %v", result.Error)
+		return errors.New("failed to remove member")
+	}
+	if result.RowsAffected == 0 {
+		return ErrLedgerMemberNotFound
+	}
+
+	return nil
+}
+
+// GetLedgerSummary menjumlahkan transaksi semua member ledger pada bulan month (format 2006-01),
+// default bulan berjalan menurut timezone user. Nominal dalam base currency user.
+func (s *LedgerService) GetLedgerSummary(userID, ledgerID uint, filter request.LedgerSummaryFilter) (*response.LedgerSummaryResponse, error) {
+	var start time.Time
+	if filter.Month != "" {
+		var err error
+		if start, err = time.Parse("2006-01", filter.Month); err != nil {
+			return nil, errors.New("invalid month format, use YYYY-MM")
+		}
+	}
+
+	if _, _, err := s.memberLedger(userID, ledgerID); err != nil {
+		return nil, err
+	}
+
+	preferences, err := loadUserPreferences(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Error getting user preferences: %v", err)
+		return nil, errors.New("failed to get ledger summary")
+	}
+	if start.IsZero() {
+		start = utility.BucketStart(utility.LocalDate(time.Now(), preferences.Location), utility.GranularityMonth)
+	}
+	end := start.AddDate(0, 1, 0)
+
+	memberTotals, err := s.dashboardUtil.GetLedgerMemberTotals(ledgerID, userID, start, end)
+	if err != nil {
+		logrus.Errorf("Error getting ledger member totals: %v", err)
+		return nil, errors.New("failed to get ledger summary")
+	}
+	categoryTotals, err := s.dashboardUtil.GetLedgerCategoryTotals(ledgerID, userID, start, end)
+	if err != nil {
+		logrus.Errorf("Error getting ledger category totals: %v", err)
+		return nil, errors.New("failed to get ledger summary")
+	}
+
+	summary := &response.LedgerSummaryResponse{
+		LedgerID:    ledgerID,
+		Month:       start.Format("2006-01"),
+		Label:       utility.BucketLabel(start, utility.GranularityMonth, preferences.Locale),
+		PeriodStart: start,
+		PeriodEnd:   end.AddDate(0, 0, -1),
+		Currency:    preferences.BaseCurrency,
+		Members:     make([]response.LedgerMemberTotal, len(memberTotals)),
+		Categories:  make([]response.LedgerCategoryTotal, len(categoryTotals)),
+	}
+	for i, total := range memberTotals {
+		summary.Income += total.Income
+		summary.Expense += total.Expense
+		summary.Members[i] = response.LedgerMemberTotal{
+			UserID:       total.UserID,
+			Name:         total.Name,
+			Income:       total.Income,
+			Expense:      total.Expense,
+			Transactions: total.Transactions,
+		}
+	}
+	summary.Net = summary.Income - summary.Expense
+	for i, total := range categoryTotals {
+		summary.Categories[i] = response.LedgerCategoryTotal{Category: total.Category, Type: total.Type, Amount: total.Total}
+	}
+
+	return summary, nil
+}
+
+// memberLedger mengembalikan ledger beserta role user, ErrLedgerNotFound jika user bukan member
+func (s *LedgerService) memberLedger(userID, ledgerID uint) (*entity.Ledger, string, error) {
+	var ledger entity.Ledger
+	if err := s.DB.First(&ledger, ledgerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrLedgerNotFound
+		}
+		logrus.Errorf("Error getting ledger: %v", err)
+		return nil, "", errors.New("failed to get ledger")
+	}
+
+	role, err := ledgerRole(s.DB, userID, ledgerID)
+	if err != nil {
+		logrus.Errorf("Error getting ledger member: %v", err)
+		return nil, "", errors.New("failed to get ledger")
+	}
+	if role == "" {
+		return nil, "", ErrLedgerNotFound
+	}
+
+	return &ledger, role, nil
+}
+
+func (s *LedgerService) ownedLedger(userID, ledgerID uint) (*entity.Ledger, error) {
+	ledger, role, err := s.memberLedger(userID, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if role != entity.LedgerRoleOwner {
+		return nil, ErrLedgerOwnerOnly
+	}
+	return ledger, nil
+}
+
+func (s *LedgerService) userEmail(userID uint) (string, error) {
+	var users []entity.User
+	if err := s.DB.Select("email").Where("id = ?", userID).Limit(1).Find(&users).Error; err != nil {
+		logrus.Errorf("Error getting user: %v", err)
+		return "", errors.New("failed to get user")
+	}
+	if len(users) == 0 {
+		return "", errors.New("user not found")
+	}
+	return strings.ToLower(users[0].Email), nil
+}
+
+// personalLedgerID mengembalikan personal ledger user, dibuat jika belum ada untuk user yang
+// register setelah migrasi
+func personalLedgerID(db *gorm.DB, userID uint) (uint, error) {
+	find := func() ([]uint, error) {
+		var ledgerIDs []uint
+		err := db.Model(&entity.Ledger{}).
+			Where("owner_id = ? AND personal = ?", userID, true).
+			Limit(1).
+			Pluck("id", &ledgerIDs).Error
+		return ledgerIDs, err
+	}
+
+	ledgerIDs, err := find()
+	if err != nil {
+		return 0, err
+	}
+	if len(ledgerIDs) > 0 {
+		return ledgerIDs[0], nil
+	}
+
+	ledger := entity.Ledger{
+		Name:     "Personal",
+		OwnerID:  userID,
+		Personal: true,
+		Members:  []entity.LedgerMember{{UserID: userID, Role: entity.LedgerRoleOwner}},
+	}
+	if err := db.Create(&ledger).Error; err != nil {
+		// request lain sudah membuatnya lebih dulu, lihat idx_ledgers_personal_owner
+		if ledgerIDs, findErr := find(); findErr == nil && len(ledgerIDs) > 0 {
+			return ledgerIDs[0], nil
+		}
+		return 0, err
+	}
+
+	return ledger.ID, nil
+}
+
+// ledgerRole mengembalikan role user di ledger, kosong jika user bukan member
+func ledgerRole(db *gorm.DB, userID, ledgerID uint) (string, error) {
+	var roles []string
+	if err := db.Model(&entity.LedgerMember{}).
+		Joins("JOIN ledgers ON ledgers.id = ledger_members.ledger_id AND ledgers.deleted_at IS NULL").
+		Where("ledger_members.ledger_id = ? AND ledger_members.user_id = ?", ledgerID, userID).
+		Limit(1).
+		Pluck("ledger_members.role", &roles).Error; err != nil || len(roles) == 0 {
+		return "", err
+	}
+	return roles[0], nil
+}
+
+// resolveLedger menentukan ledger yang dipakai request kategori dan transaksi: ledgerID jika
+// diisi dan user adalah member-nya, selain itu personal ledger user. Untuk perubahan data
+// (write) role viewer ditolak.
+func resolveLedger(db *gorm.DB, userID, ledgerID uint, write bool) (uint, error) {
+	if ledgerID == 0 {
+		personalID, err := personalLedgerID(db, userID)
+		if err != nil {
+			logrus.Errorf("Error getting personal ledger: %v", err)
+			return 0, errors.New("failed to get ledger")
+		}
+		return personalID, nil
+	}
+
+	role, err := ledgerRole(db, userID, ledgerID)
+	if err != nil {
+		logrus.Errorf("Error getting ledger member: %v", err)
+		return 0, errors.New("failed to get ledger")
+	}
+	if role == "" {
+		return 0, ErrLedgerNotFound
+	}
+	if write && role == entity.LedgerRoleViewer {
+		return 0, ErrLedgerReadOnly
+	}
+
+	return ledgerID, nil
+}
+
+// memberLedgers adalah subquery ID ledger yang bisa dilihat user, atau yang bisa diubah user
+// (owner dan editor) jika write
+func memberLedgers(db *gorm.DB, userID uint, write bool) *gorm.DB {
+	query := db.Session(&gorm.Session{NewDB: true}).
+		Model(&entity.LedgerMember{}).
+		Select("ledger_id").
+		Where("user_id = ?", userID)
+	if write {
+		query = query.Where("role IN ?", []string{entity.LedgerRoleOwner, entity.LedgerRoleEditor})
+	}
+	return query
+}
+
+// categoryLedgerID mengembalikan ledger kategori, transaksi selalu dicatat di ledger kategorinya
+func categoryLedgerID(db *gorm.DB, categoryID uint) (uint, error) {
+	var ledgerIDs []uint
+	if err := db.Unscoped().Model(&entity.Category{}).Where("id = ?", categoryID).Limit(1).Pluck("ledger_id", &ledgerIDs).Error; err != nil {
+		return 0, err
+	}
+	if len(ledgerIDs) == 0 {
+		return 0, errors.New("category not found")
+	}
+	return ledgerIDs[0], nil
+}
+
+// writableCategoryLedgerID mengembalikan ledger kategori selama user masih owner atau editor
+// ledger tersebut. Dipakai proses otomatis yang mencatat transaksi atas nama user, supaya
+// member yang sudah dikeluarkan atau diturunkan jadi viewer tidak bisa menulis lagi.
+func writableCategoryLedgerID(db *gorm.DB, userID, categoryID uint) (uint, error) {
+	ledgerID, err := categoryLedgerID(db, categoryID)
+	if err != nil {
+		return 0, err
+	}
+
+	role, err := ledgerRole(db, userID, ledgerID)
+	if err != nil {
+		return 0, err
+	}
+	if role == "" {
+		return 0, ErrLedgerNotFound
+	}
+	if role == entity.LedgerRoleViewer {
+		return 0, ErrLedgerReadOnly
+	}
+
+	return ledgerID, nil
+}
+
+// categoryLedger adalah subquery ledger kategori, dipakai saat kategori transaksi diganti massal
+// supaya transaksi ikut pindah ke ledger kategori barunya
+func categoryLedger(db *gorm.DB, categoryID uint) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Model(&entity.Category{}).
+		Select("ledger_id").
+		Where("id = ?", categoryID)
+}
+
+// recategorizeTransactions mengganti kategori transaksi yang dipilih query beserta ledger-nya.
+// Memakai UpdateColumns supaya hook validasi Transaction tidak jalan pada model kosong.
+func recategorizeTransactions(query *gorm.DB, categoryID uint) *gorm.DB {
+	return query.UpdateColumns(map[string]interface{}{
+		"category_id": categoryID,
+		"ledger_id":   categoryLedger(query, categoryID),
+		"updated_at":  time.Now(),
+	})
+}
+
+func toLedgerResponse(ledger entity.Ledger, role string, memberCount int64) response.LedgerResponse {
+	return response.LedgerResponse{
+		ID:          ledger.ID,
+		Name:        ledger.Name,
+		Personal:    ledger.Personal,
+		OwnerID:     ledger.OwnerID,
+		Role:        role,
+		MemberCount: memberCount,
+		CreatedAt:   ledger.CreatedAt,
+	}
+}
+
+func toLedgerInvitationResponse(invitation entity.LedgerInvitation) response.LedgerInvitationResponse {
+	return response.LedgerInvitationResponse{
+		ID:        invitation.ID,
+		LedgerID:  invitation.LedgerID,
+		Ledger:    invitation.Ledger.Name,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientPoints = errors.New("insufficient loyalty points")
+	ErrInvalidPoints      = errors.New("points must be greater than 0")
+)
+
+// poin yang didapat dari order berlaku selama 12 bulan
+const loyaltyPointsValidityMonths = 12
+
+type LoyaltyService struct {
+	DB *gorm.DB
+}
+
+func NewLoyaltyService(db *gorm.DB) *LoyaltyService {
+	return &LoyaltyService{DB: db}
+}
+
+// loyaltyRupiahPerPoint adalah jumlah belanja (rupiah) untuk mendapatkan 1 poin
+func loyaltyRupiahPerPoint() float64 {
+	if value, err := strconv.ParseFloat(os.Getenv("LOYALTY_RUPIAH_PER_POINT"), 64); err == nil && value > 0 {
+		return value
+	}
+	return 10000
+}
+
+// loyaltyPointValue adalah nilai rupiah 1 poin saat ditukar di checkout
+func loyaltyPointValue() float64 {
+	if value, err := strconv.ParseFloat(os.Getenv("LOYALTY_POINT_VALUE"), 64); err == nil && value > 0 {
+		return value
+	}
+	return 100
+}
+
+func (s *LoyaltyService) GetBalance(userID uint) (*response.LoyaltyBalanceResponse, error) {
+	var account entity.LoyaltyAccount
+	if err := s.DB.Where("user_id = ?", userID).Limit(1).Find(&account).Error; err != nil {
+		logrus.Errorf("Error getting loyalty account: %v", err)
+		return nil, errors.New("failed to get loyalty balance")
+	}
+
+	return &response.LoyaltyBalanceResponse{
+		Points:         account.Balance,
+		RedeemValue:    float64(account.Balance) * loyaltyPointValue(),
+		PointValue:     loyaltyPointValue(),
+		RupiahPerPoint: loyaltyRupiahPerPoint(),
+	}, nil
+}
+
+func (s *LoyaltyService) GetLedger(userID uint, filter request.LoyaltyLedgerFilter) (*response.LoyaltyLedgerResponse, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+
+	query := s.DB.Model(&entity.LoyaltyLedgerEntry{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logrus.Errorf("Error counting loyalty ledger: %v", err)
+		return nil, errors.New("failed to get loyalty ledger")
+	}
+
+	var entries []entity.LoyaltyLedgerEntry
+	if err := query.Order("id DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&entries).Error; err != nil {
+		logrus.Errorf("Error getting loyalty ledger: %v", err)
+		return nil, errors.New("failed to get loyalty ledger")
+	}
+
+	entryResponses := make([]response.LoyaltyLedgerEntryResponse, len(entries))
+	for i, entry := range entries {
+		entryResponses[i] = toLoyaltyEntryResponse(entry)
+	}
+
+	return &response.LoyaltyLedgerResponse{
+		Entries: entryResponses,
+		Pagination: response.Pagination{
+			CurrentPage: filter.Page,
+			TotalPage:   int(math.Ceil(float64(total) / float64(filter.Limit))),
+			TotalItems:  total,
+			ItemPerPage: filter.Limit,
+		},
+	}, nil
+}
+
+// AwardOrderPoints memberikan poin untuk order yang sudah delivered. Dipanggil
+// sekali per order, pemanggilan ulang dengan reference yang sama tidak menambah poin.
+// Belum ada pemanggilnya karena repo ini belum punya alur order, integrasinya menyusul
+// bersama fitur order.
+func (s *LoyaltyService) AwardOrderPoints(userID uint, orderReference string, amount float64) (*response.LoyaltyLedgerEntryResponse, error) {
+	points := int64(math.Floor(amount / loyaltyRupiahPerPoint()))
+	if points <= 0 {
+		return nil, ErrInvalidPoints
+	}
+
+	expiresAt := time.Now().AddDate(0, loyaltyPointsValidityMonths, 0)
+	entry := entity.LoyaltyLedgerEntry{
+		UserID:      userID,
+		Type:        entity.LoyaltyEntryEarn,
+		Reference:   &orderReference,
+		Points:      points,
+		Description: fmt.Sprintf("Points earned from order %s", orderReference),
+		ExpiresAt:   &expiresAt,
+	}
+
+	if err := s.DB.Transaction(func(tx *gorm.DB) error {
+		return s.appendEntry(tx, &entry)
+	}); err != nil {
+		logrus.Errorf("Error awarding loyalty points: %v", err)
+		return nil, errors.New("failed to award loyalty points")
+	}
+
+	entryResponse := toLoyaltyEntryResponse(entry)
+	return &entryResponse, nil
+}
+
+// Redeem menukar poin sebagai potongan pembayaran di checkout. Reference
+// (misalnya ID checkout) membuat retry dari checkout yang sama tidak memotong poin dua kali.
+// Seperti AwardOrderPoints, belum dipanggil sampai alur checkout tersedia.
+func (s *LoyaltyService) Redeem(userID uint, points int64, reference string) (*response.LoyaltyRedemptionResponse, error) {
+	if points <= 0 {
+		return nil, ErrInvalidPoints
+	}
+
+	entry := entity.LoyaltyLedgerEntry{
+		UserID:      userID,
+		Type:        entity.LoyaltyEntryRedeem,
+		Reference:   &reference,
+		Points:      -points,
+		Description: fmt.Sprintf("Points redeemed at checkout %s", reference),
+	}
+
+	if err := s.DB.Transaction(func(tx *gorm.DB) error {
+		return s.appendEntry(tx, &entry)
+	}); err != nil {
+		if errors.Is(err, ErrInsufficientPoints) {
+			return nil, err
+		}
+		logrus.Errorf("Error redeeming loyalty points: %v", err)
+		return nil, errors.New("failed to redeem loyalty points")
+	}
+
+	return &response.LoyaltyRedemptionResponse{
+		Entry:    toLoyaltyEntryResponse(entry),
+		Discount: float64(-entry.Points) * loyaltyPointValue(),
+	}, nil
+}
+
+// Adjust menambah atau mengurangi poin secara manual oleh admin
+func (s *LoyaltyService) Adjust(req request.LoyaltyAdjustRequest) (*response.LoyaltyLedgerEntryResponse, error) {
+	var user entity.User
+	if err := s.DB.First(&user, req.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		logrus.Errorf("Error getting user for loyalty adjustment: %v", err)
+		return nil, errors.New("failed to get user")
+	}
+
+	entry := entity.LoyaltyLedgerEntry{
+		UserID:      req.UserID,
+		Type:        entity.LoyaltyEntryAdjust,
+		Points:      req.Points,
+		Description: req.Description,
+	}
+
+	if err := s.DB.Transaction(func(tx *gorm.DB) error {
+		return s.appendEntry(tx, &entry)
+	}); err != nil {
+		if errors.Is(err, ErrInsufficientPoints) {
+			return nil, err
+		}
+		logrus.Errorf("Error adjusting loyalty points: %v", err)
+		return nil, errors.New("failed to adjust loyalty points")
+	}
+
+	entryResponse := toLoyaltyEntryResponse(entry)
+	return &entryResponse, nil
+}
+
+// ExpirePoints menghanguskan poin earn yang sudah lewat masa berlaku. Poin yang
+// ditukar dianggap memakai poin paling lama terlebih dahulu (FIFO).
+func (s *LoyaltyService) ExpirePoints(ctx context.Context) error {
+	now := time.Now()
+
+	var userIDs []uint
+	if err := s.DB.Model(&entity.LoyaltyLedgerEntry{}).
+		Where("type = ? AND expires_at <= ?", entity.LoyaltyEntryEarn, now).
+		Distinct("user_id").
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return fmt.Errorf("failed to get users with expiring points: %v", err)
+	}
+
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			account, err := s.lockAccount(tx, userID)
+			if err != nil {
+				return err
+			}
+
+			var earnedDue, consumed int64
+			if err := tx.Model(&entity.LoyaltyLedgerEntry{}).
+				Select("COALESCE(SUM(points), 0)").
+				Where("user_id = ? AND type = ? AND expires_at <= ?", userID, entity.LoyaltyEntryEarn, now).
+				Scan(&earnedDue).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&entity.LoyaltyLedgerEntry{}).
+				Select("COALESCE(-SUM(points), 0)").
+				Where("user_id = ? AND points < 0", userID).
+				Scan(&consumed).Error; err != nil {
+				return err
+			}
+
+			expired := earnedDue - consumed
+			if expired > account.Balance {
+				expired = account.Balance
+			}
+			if expired <= 0 {
+				return nil
+			}
+
+			entry := entity.LoyaltyLedgerEntry{
+				UserID:      userID,
+				Type:        entity.LoyaltyEntryExpire,
+				Points:      -expired,
+				Description: "Points expired",
+			}
+			return s.appendEntryLocked(tx, account, &entry)
+		})
+		if err != nil {
+			logrus.Errorf("Failed to expire loyalty points for user %d: %v", userID, err)
+		}
+	}
+
+	return nil
+}
+
+// appendEntry menambah entry ledger dan memperbarui saldo dalam satu transaksi DB
+func (s *LoyaltyService) appendEntry(tx *gorm.DB, entry *entity.LoyaltyLedgerEntry) error {
+	account, err := s.lockAccount(tx, entry.UserID)
+	if err != nil {
+		return err
+	}
+
+	// entry dengan reference yang sama sudah pernah dicatat
+	if entry.Reference != nil {
+		var existing entity.LoyaltyLedgerEntry
+		if err := tx.Where("user_id = ? AND type = ? AND reference = ?", entry.UserID, entry.Type, *entry.Reference).
+			Limit(1).
+			Find(&existing).Error; err != nil {
+			return err
+		}
+		if existing.ID != 0 {
+			*entry = existing
+			return nil
+		}
+	}
+
+	return s.appendEntryLocked(tx, account, entry)
+}
+
+func (s *LoyaltyService) appendEntryLocked(tx *gorm.DB, account *entity.LoyaltyAccount, entry *entity.LoyaltyLedgerEntry) error {
+	newBalance := account.Balance + entry.Points
+	if newBalance < 0 {
+		return ErrInsufficientPoints
+	}
+
+	if err := tx.Model(&entity.LoyaltyAccount{}).
+		Where("user_id = ?", account.UserID).
+		Update("balance", newBalance).Error; err != nil {
+		return err
+	}
+
+	entry.Balance = newBalance
+	return tx.Create(entry).Error
+}
+
+// lockAccount mengambil saldo user dengan row lock, membuat akun jika belum ada
+func (s *LoyaltyService) lockAccount(tx *gorm.DB, userID uint) (*entity.LoyaltyAccount, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.LoyaltyAccount{UserID: userID}).Error; err != nil {
+		return nil, err
+	}
+
+	var account entity.LoyaltyAccount
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&account).Error; err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func toLoyaltyEntryResponse(entry entity.LoyaltyLedgerEntry) response.LoyaltyLedgerEntryResponse {
+	var reference string
+	if entry.Reference != nil {
+		reference = *entry.Reference
+	}
+
+	return response.LoyaltyLedgerEntryResponse{
+		ID:          entry.ID,
+		Type:        entry.Type,
+		Points:      entry.Points,
+		Balance:     entry.Balance,
+		Reference:   reference,
+		Description: entry.Description,
+		ExpiresAt:   entry.ExpiresAt,
+		CreatedAt:   entry.CreatedAt,
+	}
+}
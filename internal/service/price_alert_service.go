@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/notifier"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PriceAlertService struct {
+	DB       *gorm.DB
+	Notifier notifier.Notifier
+}
+
+func NewPriceAlertService(db *gorm.DB, n notifier.Notifier) *PriceAlertService {
+	return &PriceAlertService{DB: db, Notifier: n}
+}
+
+// Subscribe membuat atau memperbarui price alert user untuk sebuah produk
+func (s *PriceAlertService) Subscribe(userID, productID uint, targetPrice float64) (*response.PriceAlertResponse, error) {
+	var product entity.Product
+	if err := s.DB.First(&product, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		logrus.Errorf("Error getting product for price alert: %v", err)
+		return nil, errors.New("failed to get product")
+	}
+
+	if targetPrice >= product.Price {
+		return nil, errors.New("target price must be lower than the current price")
+	}
+
+	alert := entity.PriceAlert{
+		UserID:      userID,
+		ProductID:   productID,
+		TargetPrice: targetPrice,
+		Active:      true,
+	}
+
+	// satu alert per user per produk, subscribe ulang akan mengaktifkan kembali alert
+	if err := s.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"target_price": targetPrice,
+			"active":       true,
+			"triggered_at": nil,
+			"updated_at":   time.Now(),
+		}),
+	}).Create(&alert).Error; err != nil {
+		logrus.Errorf("Error saving price alert: %v", err)
+		return nil, errors.New("failed to save price alert")
+	}
+
+	if err := s.DB.Where("user_id = ? AND product_id = ?", userID, productID).First(&alert).Error; err != nil {
+		logrus.Errorf("Error getting price alert: %v", err)
+		return nil, errors.New("failed to save price alert")
+	}
+	alert.Product = product
+
+	return toPriceAlertResponse(alert), nil
+}
+
+func (s *PriceAlertService) Unsubscribe(userID, productID uint) error {
+	result := s.DB.Where("user_id = ? AND product_id = ?", userID, productID).Delete(&entity.PriceAlert{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting price alert: %v", result.Error)
+		return errors.New("failed to delete price alert")
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("price alert not found")
+	}
+
+	return nil
+}
+
+func (s *PriceAlertService) GetUserAlerts(userID uint) ([]response.PriceAlertResponse, error) {
+	var alerts []entity.PriceAlert
+	if err := s.DB.Preload("Product").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&alerts).Error; err != nil {
+		logrus.Errorf("Error getting price alerts: %v", err)
+		return nil, errors.New("failed to get price alerts")
+	}
+
+	alertResponses := make([]response.PriceAlertResponse, len(alerts))
+	for i, alert := range alerts {
+		alertResponses[i] = *toPriceAlertResponse(alert)
+	}
+
+	return alertResponses, nil
+}
+
+// TriggerAlerts menonaktifkan alert yang target price-nya sudah tercapai lalu mengirim notifikasi
+func (s *PriceAlertService) TriggerAlerts(ctx context.Context, product entity.Product) error {
+	var alerts []entity.PriceAlert
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("product_id = ? AND active = ? AND target_price >= ?", product.ID, true, product.Price).
+			Find(&alerts).Error; err != nil {
+			return err
+		}
+
+		if len(alerts) == 0 {
+			return nil
+		}
+
+		alertIDs := make([]uint, len(alerts))
+		for i, alert := range alerts {
+			alertIDs[i] = alert.ID
+		}
+
+		return tx.Model(&entity.PriceAlert{}).
+			Where("id IN ?", alertIDs).
+			Updates(map[string]interface{}{
+				"active":       false,
+				"triggered_at": time.Now(),
+			}).Error
+	})
+	if err != nil {
+		logrus.Errorf("Error triggering price alerts: %v", err)
+		return errors.New("failed to trigger price alerts")
+	}
+
+	for _, alert := range alerts {
+		msg := notifier.Message{
+			Subject: fmt.Sprintf("Price drop: %s", product.Name),
+			Body: fmt.Sprintf("%s is now Rp%s, at or below your target price of Rp%s.",
+				product.Name,
+				utility.FormatNumber(int64(product.Price)),
+				utility.FormatNumber(int64(alert.TargetPrice))),
+		}
+		if err := s.Notifier.Notify(ctx, alert.UserID, msg); err != nil {
+			logrus.Errorf("Failed to send price alert %d: %v", alert.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func toPriceAlertResponse(alert entity.PriceAlert) *response.PriceAlertResponse {
+	return &response.PriceAlertResponse{
+		ID:           alert.ID,
+		ProductID:    alert.ProductID,
+		ProductName:  alert.Product.Name,
+		CurrentPrice: alert.Product.Price,
+		TargetPrice:  alert.TargetPrice,
+		Active:       alert.Active,
+		TriggeredAt:  alert.TriggeredAt,
+		CreatedAt:    alert.CreatedAt,
+	}
+}
@@ -1,23 +1,34 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"go-electroshop/internal/notifier"
 	"go-electroshop/internal/payload/entity"
 	"go-electroshop/internal/payload/request"
 	"go-electroshop/internal/payload/response"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type ProductService struct {
-	DB *gorm.DB
+	DB                       *gorm.DB
+	PriceAlertService        *PriceAlertService
+	StockNotificationService *StockNotificationService
 }
 
 func NewProductService(db *gorm.DB) *ProductService {
-	return &ProductService{DB: db}
+	productNotifier := notifier.NewLogNotifier()
+
+	return &ProductService{
+		DB:                       db,
+		PriceAlertService:        NewPriceAlertService(db, productNotifier),
+		StockNotificationService: NewStockNotificationService(db, productNotifier),
+	}
 }
 
 func (s *ProductService) GetProducts(filter request.ProductFilter) (*response.ProductListResponse, error) {
@@ -48,9 +59,23 @@ func (s *ProductService) GetProducts(filter request.ProductFilter) (*response.Pr
 		return nil, errors.New("failed to count products")
 	}
 
+	// Sorting
+	switch filter.Sort {
+	case "price_asc":
+		query = query.Order("products.price ASC")
+	case "price_desc":
+		query = query.Order("products.price DESC")
+	case "popularity":
+		since := time.Now().UTC().AddDate(0, 0, -popularityWindowDays).Format("2006-01-02")
+		query = query.Select("products.*").
+			Joins("LEFT JOIN (SELECT product_id, SUM(view_count) AS views FROM product_view_stats WHERE date >= ? GROUP BY product_id) pv ON pv.product_id = products.id", since).
+			Order("COALESCE(pv.views, 0) DESC")
+	}
+	query = query.Order("products.created_at DESC")
+
 	// Pagination
 	offset := (filter.Page - 1) * filter.Limit
-	if err := query.Order("created_at DESC").
+	if err := query.
 		Offset(offset).
 		Limit(filter.Limit).
 		Find(&products).
@@ -68,6 +93,7 @@ func (s *ProductService) GetProducts(filter request.ProductFilter) (*response.Pr
 			Category:  product.Category,
 			Name:      product.Name,
 			Price:     product.Price,
+			Stock:     product.Stock,
 			ImageLink: product.ImageLink,
 			CreatedAt: product.CreatedAt,
 			UpdatedAt: product.UpdatedAt,
@@ -102,6 +128,7 @@ func (s *ProductService) GetProductByID(productID uint) (*response.ProductRespon
 		Category:  product.Category,
 		Name:      product.Name,
 		Price:     product.Price,
+		Stock:     product.Stock,
 		ImageLink: product.ImageLink,
 		CreatedAt: product.CreatedAt,
 		UpdatedAt: product.UpdatedAt,
@@ -114,6 +141,7 @@ func (s *ProductService) CreateProduct(req *request.ProductRequest) (*response.P
 		Category:  strings.TrimSpace(req.Category),
 		Name:      strings.TrimSpace(req.Name),
 		Price:     req.Price,
+		Stock:     req.Stock,
 		ImageLink: req.ImageLink,
 	}
 
@@ -129,6 +157,7 @@ func (s *ProductService) CreateProduct(req *request.ProductRequest) (*response.P
 		Category:  product.Category,
 		Name:      product.Name,
 		Price:     product.Price,
+		Stock:     product.Stock,
 		ImageLink: product.ImageLink,
 		CreatedAt: product.CreatedAt,
 		UpdatedAt: product.UpdatedAt,
@@ -146,30 +175,100 @@ func (s *ProductService) UpdateProduct(productID uint, req *request.UpdateProduc
 		return nil, errors.New("failed to get product")
 	}
 
+	oldPrice := product.Price
+	wasInStock := product.InStock()
+
 	// Update fields
 	product.Thumbnail = req.Thumbnail
 	product.Category = strings.TrimSpace(req.Category)
 	product.Name = strings.TrimSpace(req.Name)
 	product.Price = req.Price
 	product.ImageLink = req.ImageLink
+	if req.Stock != nil {
+		product.Stock = req.Stock
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&product).Error; err != nil {
+			return err
+		}
 
-	if err := s.DB.Save(&product).Error; err != nil {
+		// catat perubahan harga
+		if product.Price != oldPrice {
+			history := entity.ProductPriceHistory{
+				ProductID: product.ID,
+				OldPrice:  oldPrice,
+				NewPrice:  product.Price,
+				ChangedAt: time.Now(),
+			}
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
 		logrus.Errorf("Error updating product: %v", err)
 		return nil, errors.New("failed to update product")
 	}
 
+	if product.Price < oldPrice {
+		if err := s.PriceAlertService.TriggerAlerts(context.Background(), product); err != nil {
+			logrus.Errorf("Error triggering price alerts for product %d: %v", product.ID, err)
+		}
+	}
+
+	// stok terisi kembali, kirim batch pertama notifikasi back-in-stock
+	if !wasInStock && product.InStock() {
+		if err := s.StockNotificationService.NotifyRestock(context.Background(), product.ID); err != nil {
+			logrus.Errorf("Error sending restock notifications for product %d: %v", product.ID, err)
+		}
+	}
+
 	return &response.ProductResponse{
 		ID:        product.ID,
 		Thumbnail: product.Thumbnail,
 		Category:  product.Category,
 		Name:      product.Name,
 		Price:     product.Price,
+		Stock:     product.Stock,
 		ImageLink: product.ImageLink,
 		CreatedAt: product.CreatedAt,
 		UpdatedAt: product.UpdatedAt,
 	}, nil
 }
 
+func (s *ProductService) GetPriceHistory(productID uint) ([]response.PriceHistoryResponse, error) {
+	var product entity.Product
+	if err := s.DB.First(&product, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		logrus.Errorf("Error getting product for price history: %v", err)
+		return nil, errors.New("failed to get product")
+	}
+
+	var histories []entity.ProductPriceHistory
+	if err := s.DB.Where("product_id = ?", productID).
+		Order("changed_at DESC").
+		Find(&histories).Error; err != nil {
+		logrus.Errorf("Error getting price history: %v", err)
+		return nil, errors.New("failed to get price history")
+	}
+
+	historyResponse := make([]response.PriceHistoryResponse, len(histories))
+	for i, history := range histories {
+		historyResponse[i] = response.PriceHistoryResponse{
+			OldPrice:  history.OldPrice,
+			NewPrice:  history.NewPrice,
+			ChangedAt: history.ChangedAt,
+		}
+	}
+
+	return historyResponse, nil
+}
+
 func (s *ProductService) DeleteProduct(productID uint) error {
 	result := s.DB.Delete(&entity.Product{}, productID)
 
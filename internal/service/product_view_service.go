@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// popularityWindowDays adalah rentang hari view yang dihitung untuk sort popularity
+const popularityWindowDays = 30
+
+const (
+	// batas jumlah produk dan rentang tanggal satu request analytics
+	maxViewAnalyticsLimit = 100
+	maxViewAnalyticsDays  = 366
+	// jumlah row view yang dihapus per batch oleh retention job
+	productViewPruneBatchSize = 1000
+)
+
+// productViewRetentionDays adalah lama row view mentah disimpan. Statistik harian tetap
+// disimpan, row mentah hanya dibutuhkan untuk recently viewed.
+func productViewRetentionDays() int {
+	if value, err := strconv.Atoi(os.Getenv("PRODUCT_VIEW_RETENTION_DAYS")); err == nil && value > 0 {
+		return value
+	}
+	return 90
+}
+
+type ProductViewService struct {
+	DB *gorm.DB
+}
+
+func NewProductViewService(db *gorm.DB) *ProductViewService {
+	return &ProductViewService{DB: db}
+}
+
+// RecordView menyimpan satu view produk dan menaikkan counter harian produk tersebut
+func (s *ProductViewService) RecordView(productID uint, userID *uint, sessionID string) error {
+	if userID == nil && sessionID == "" {
+		return errors.New("viewer is unknown")
+	}
+
+	now := time.Now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		view := entity.ProductView{
+			ProductID: productID,
+			UserID:    userID,
+			SessionID: sessionID,
+			ViewedAt:  now,
+		}
+		if err := tx.Create(&view).Error; err != nil {
+			logrus.Errorf("Error recording product view: %v", err)
+			return errors.New("failed to record product view")
+		}
+
+		stat := entity.ProductViewStat{ProductID: productID, Date: day, ViewCount: 1}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "product_id"}, {Name: "date"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"view_count": gorm.Expr("product_view_stats.view_count + 1"),
+			}),
+		}).Create(&stat).Error; err != nil {
+			logrus.Errorf("Error updating product view stat: %v", err)
+			return errors.New("failed to record product view")
+		}
+
+		return nil
+	})
+}
+
+// GetRecentlyViewed mengambil produk terakhir yang dilihat user atau session anonim
+func (s *ProductViewService) GetRecentlyViewed(userID *uint, sessionID string, limit int) (*response.RecentlyViewedResponse, error) {
+	result := &response.RecentlyViewedResponse{Products: []response.RecentlyViewedProduct{}}
+	if userID == nil && sessionID == "" {
+		return result, nil
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+
+	type recentView struct {
+		ProductID uint
+		ViewedAt  time.Time
+	}
+
+	query := s.DB.Model(&entity.ProductView{}).
+		Select("product_id, MAX(viewed_at) AS viewed_at")
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	} else {
+		query = query.Where("session_id = ? AND user_id IS NULL", sessionID)
+	}
+
+	var views []recentView
+	if err := query.Group("product_id").
+		Order("viewed_at DESC").
+		Limit(limit).
+		Scan(&views).Error; err != nil {
+		logrus.Errorf("Error getting recently viewed products: %v", err)
+		return nil, errors.New("failed to get recently viewed products")
+	}
+
+	if len(views) == 0 {
+		return result, nil
+	}
+
+	productIDs := make([]uint, len(views))
+	for i, view := range views {
+		productIDs[i] = view.ProductID
+	}
+
+	var products []entity.Product
+	if err := s.DB.Where("id IN ?", productIDs).Find(&products).Error; err != nil {
+		logrus.Errorf("Error getting recently viewed products: %v", err)
+		return nil, errors.New("failed to get recently viewed products")
+	}
+
+	productByID := make(map[uint]entity.Product, len(products))
+	for _, product := range products {
+		productByID[product.ID] = product
+	}
+
+	// pertahankan urutan view terbaru, lewati produk yang sudah dihapus
+	for _, view := range views {
+		product, ok := productByID[view.ProductID]
+		if !ok {
+			continue
+		}
+		result.Products = append(result.Products, response.RecentlyViewedProduct{
+			Product: response.ProductResponse{
+				ID:        product.ID,
+				Thumbnail: product.Thumbnail,
+				Category:  product.Category,
+				Name:      product.Name,
+				Price:     product.Price,
+				Stock:     product.Stock,
+				ImageLink: product.ImageLink,
+				CreatedAt: product.CreatedAt,
+				UpdatedAt: product.UpdatedAt,
+			},
+			ViewedAt: view.ViewedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// GetViewAnalytics mengambil jumlah view per produk per hari untuk admin
+func (s *ProductViewService) GetViewAnalytics(filter request.ProductViewFilter) (*response.ProductViewAnalyticsResponse, error) {
+	to := time.Now().UTC()
+	if filter.To != "" {
+		parsed, err := time.Parse("2006-01-02", filter.To)
+		if err != nil {
+			return nil, errors.New("invalid to date format")
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(popularityWindowDays - 1))
+	if filter.From != "" {
+		parsed, err := time.Parse("2006-01-02", filter.From)
+		if err != nil {
+			return nil, errors.New("invalid from date format")
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return nil, errors.New("from date must be before to date")
+	}
+	if to.Sub(from) >= maxViewAnalyticsDays*24*time.Hour {
+		return nil, fmt.Errorf("date range cannot exceed %d days", maxViewAnalyticsDays)
+	}
+
+	if filter.Limit <= 0 || filter.Limit > maxViewAnalyticsLimit {
+		filter.Limit = 10
+	}
+
+	type productTotal struct {
+		ProductID   uint
+		ProductName string
+		TotalViews  int64
+	}
+
+	totalsQuery := s.DB.Table("product_view_stats").
+		Select("product_view_stats.product_id, products.name AS product_name, SUM(product_view_stats.view_count) AS total_views").
+		Joins("JOIN products ON products.id = product_view_stats.product_id AND products.deleted_at IS NULL").
+		Where("product_view_stats.date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if filter.ProductID != 0 {
+		totalsQuery = totalsQuery.Where("product_view_stats.product_id = ?", filter.ProductID)
+	}
+
+	var totals []productTotal
+	if err := totalsQuery.Group("product_view_stats.product_id, products.name").
+		Order("total_views DESC").
+		Limit(filter.Limit).
+		Scan(&totals).Error; err != nil {
+		logrus.Errorf("Error getting product view totals: %v", err)
+		return nil, errors.New("failed to get product view analytics")
+	}
+
+	result := &response.ProductViewAnalyticsResponse{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Products: make([]response.ProductViewAnalytics, 0, len(totals)),
+	}
+	if len(totals) == 0 {
+		return result, nil
+	}
+
+	productIDs := make([]uint, len(totals))
+	for i, total := range totals {
+		productIDs[i] = total.ProductID
+	}
+
+	var stats []entity.ProductViewStat
+	if err := s.DB.Where("product_id IN ? AND date BETWEEN ? AND ?", productIDs, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("date ASC").
+		Find(&stats).Error; err != nil {
+		logrus.Errorf("Error getting product view stats: %v", err)
+		return nil, errors.New("failed to get product view analytics")
+	}
+
+	dailyByProduct := make(map[uint][]response.DailyViewCount)
+	for _, stat := range stats {
+		dailyByProduct[stat.ProductID] = append(dailyByProduct[stat.ProductID], response.DailyViewCount{
+			Date:  stat.Date.Format("2006-01-02"),
+			Views: stat.ViewCount,
+		})
+	}
+
+	for _, total := range totals {
+		result.Products = append(result.Products, response.ProductViewAnalytics{
+			ProductID:   total.ProductID,
+			ProductName: total.ProductName,
+			TotalViews:  total.TotalViews,
+			Daily:       dailyByProduct[total.ProductID],
+		})
+	}
+
+	return result, nil
+}
+
+// PruneViews menghapus row view mentah yang melewati masa retention secara bertahap.
+// Dipanggil oleh scheduler.
+func (s *ProductViewService) PruneViews(ctx context.Context) error {
+	cutoff := time.Now().UTC().AddDate(0, 0, -productViewRetentionDays())
+	db := s.DB.WithContext(ctx)
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		result := db.Where("id IN (?)", db.Model(&entity.ProductView{}).
+			Select("id").
+			Where("viewed_at < ?", cutoff).
+			Limit(productViewPruneBatchSize)).
+			Delete(&entity.ProductView{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to prune product views: %v", result.Error)
+		}
+		if result.RowsAffected < productViewPruneBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,587 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// batas occurrence yang diposting per template dalam satu kali jalan,
+	// sisanya dilanjutkan pada jalan berikutnya
+	recurringMaxCatchUp = 366
+	// batas pencarian tanggal saat memvalidasi override occurrence
+	recurringMaxLookahead = 1000
+)
+
+var (
+	ErrRecurringTransactionNotFound = errors.New("recurring transaction not found")
+	ErrInvalidRecurrenceEndDate     = errors.New("end_date must be after start_date")
+	ErrOccurrenceAlreadyProcessed   = errors.New("occurrence has already been processed, update the transaction instead")
+	ErrNotUpcomingOccurrence        = errors.New("date is not an upcoming occurrence of this schedule")
+)
+
+type RecurringTransactionService struct {
+	DB *gorm.DB
+}
+
+func NewRecurringTransactionService(db *gorm.DB) *RecurringTransactionService {
+	return &RecurringTransactionService{DB: db}
+}
+
+func (s *RecurringTransactionService) GetRecurringTransactions(userID uint) ([]response.RecurringTransactionResponse, error) {
+	var recurrings []entity.RecurringTransaction
+	if err := s.DB.Preload("Category").
+		Where("user_id = ?", userID).
+		Order("active DESC, next_run_date ASC").
+		Find(&recurrings).Error; err != nil {
+		logrus.Errorf("Error getting recurring transactions: %v", err)
+		return nil, errors.New("failed to get recurring transactions")
+	}
+
+	recurringResponses := make([]response.RecurringTransactionResponse, len(recurrings))
+	for i, recurring := range recurrings {
+		recurringResponses[i] = toRecurringTransactionResponse(recurring)
+	}
+
+	return recurringResponses, nil
+}
+
+// CreateRecurringTransaction menyimpan template dan langsung memposting occurrence
+// yang tanggalnya sudah lewat atau hari ini
+func (s *RecurringTransactionService) CreateRecurringTransaction(userID uint, req request.CreateRecurringTransactionRequest) (*response.RecurringTransactionResponse, error) {
+	category, err := s.getUserCategory(userID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateUserAccount(userID, req.AccountID); err != nil {
+		return nil, err
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start_date format")
+	}
+
+	endDate, err := parseOptionalDate(req.EndDate)
+	if err != nil {
+		return nil, errors.New("invalid end_date format")
+	}
+	if endDate != nil && endDate.Before(startDate) {
+		return nil, ErrInvalidRecurrenceEndDate
+	}
+
+	interval := req.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+
+	recurring := entity.RecurringTransaction{
+		UserID:         userID,
+		CategoryID:     req.CategoryID,
+		AccountID:      req.AccountID,
+		Amount:         req.Amount,
+		Type:           req.Type,
+		Description:    req.Description,
+		Frequency:      req.Frequency,
+		Interval:       interval,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		MaxOccurrences: req.MaxOccurrences,
+		NextRunDate:    startDate,
+		Active:         true,
+	}
+
+	today, err := userToday(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Error getting user timezone: %v", err)
+		return nil, errors.New("failed to create recurring transaction")
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&recurring).Error; err != nil {
+			return err
+		}
+		_, err := s.materialize(tx, &recurring, today)
+		return err
+	})
+	if err != nil {
+		logrus.Errorf("Error creating recurring transaction: %v", err)
+		return nil, errors.New("failed to create recurring transaction")
+	}
+
+	recurring.Category = *category
+	recurringResponse := toRecurringTransactionResponse(recurring)
+	return &recurringResponse, nil
+}
+
+// UpdateRecurringTransaction mengubah template untuk occurrence berikutnya.
+// Transaksi yang sudah diposting tidak ikut berubah.
+func (s *RecurringTransactionService) UpdateRecurringTransaction(userID, recurringID uint, req request.UpdateRecurringTransactionRequest) (*response.RecurringTransactionResponse, error) {
+	category, err := s.getUserCategory(userID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateUserAccount(userID, req.AccountID); err != nil {
+		return nil, err
+	}
+
+	endDate, err := parseOptionalDate(req.EndDate)
+	if err != nil {
+		return nil, errors.New("invalid end_date format")
+	}
+
+	var recurring entity.RecurringTransaction
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", recurringID, userID).
+			First(&recurring).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecurringTransactionNotFound
+			}
+			return err
+		}
+
+		if endDate != nil && endDate.Before(recurring.StartDate) {
+			return ErrInvalidRecurrenceEndDate
+		}
+
+		recurring.CategoryID = req.CategoryID
+		recurring.AccountID = req.AccountID
+		recurring.Amount = req.Amount
+		recurring.Type = req.Type
+		recurring.Description = req.Description
+		recurring.EndDate = endDate
+		recurring.MaxOccurrences = req.MaxOccurrences
+		active := recurring.Active
+		if req.Active != nil {
+			active = *req.Active
+		}
+
+		// occurrence selama jadwal dinonaktifkan tidak diposting saat diaktifkan kembali
+		if active && !recurring.Active {
+			today, err := userToday(tx, userID)
+			if err != nil {
+				return err
+			}
+			for recurring.NextRunDate.Before(today) {
+				recurring.OccurrenceCount++
+				recurring.NextRunDate = utility.RecurrenceDate(recurring.StartDate, recurring.Frequency, recurring.Interval, recurring.OccurrenceCount)
+			}
+		}
+		recurring.Active = active && !isRecurrenceFinished(recurring)
+
+		return tx.Omit("Category").Save(&recurring).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrRecurringTransactionNotFound) || errors.Is(err, ErrInvalidRecurrenceEndDate) {
+			return nil, err
+		}
+		logrus.Errorf("Error updating recurring transaction: %v", err)
+		return nil, errors.New("failed to update recurring transaction")
+	}
+
+	recurring.Category = *category
+	recurringResponse := toRecurringTransactionResponse(recurring)
+	return &recurringResponse, nil
+}
+
+// DeleteRecurringTransaction menghentikan jadwal, transaksi yang sudah diposting tetap ada
+func (s *RecurringTransactionService) DeleteRecurringTransaction(userID, recurringID uint) error {
+	result := s.DB.Where("id = ? AND user_id = ?", recurringID, userID).Delete(&entity.RecurringTransaction{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting recurring transaction: %v", result.Error)
+		return errors.New("failed to delete recurring transaction")
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrRecurringTransactionNotFound
+	}
+
+	return nil
+}
+
+// GetOccurrences mengembalikan occurrence berikutnya (termasuk override) dan riwayat terakhir
+func (s *RecurringTransactionService) GetOccurrences(userID, recurringID uint, filter request.OccurrenceFilter) (*response.RecurringOccurrenceListResponse, error) {
+	if filter.Upcoming <= 0 {
+		filter.Upcoming = 5
+	}
+
+	var recurring entity.RecurringTransaction
+	if err := s.DB.Where("id = ? AND user_id = ?", recurringID, userID).First(&recurring).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecurringTransactionNotFound
+		}
+		logrus.Errorf("Error getting recurring transaction: %v", err)
+		return nil, errors.New("failed to get recurring transaction")
+	}
+
+	var overrides []entity.RecurringOccurrence
+	if err := s.DB.Where("recurring_transaction_id = ? AND date >= ?", recurring.ID, recurring.NextRunDate).
+		Find(&overrides).Error; err != nil {
+		logrus.Errorf("Error getting occurrence overrides: %v", err)
+		return nil, errors.New("failed to get occurrences")
+	}
+
+	overrideByDate := make(map[string]entity.RecurringOccurrence, len(overrides))
+	for _, override := range overrides {
+		overrideByDate[override.Date.Format("2006-01-02")] = override
+	}
+
+	result := response.RecurringOccurrenceListResponse{
+		Upcoming: []response.RecurringOccurrenceResponse{},
+		Recent:   []response.RecurringOccurrenceResponse{},
+	}
+
+	if recurring.Active {
+		for n := recurring.OccurrenceCount; len(result.Upcoming) < filter.Upcoming; n++ {
+			date := utility.RecurrenceDate(recurring.StartDate, recurring.Frequency, recurring.Interval, n)
+			if isOccurrenceOutOfRange(recurring, n, date) {
+				break
+			}
+
+			occurrence, ok := overrideByDate[date.Format("2006-01-02")]
+			if !ok {
+				occurrence = entity.RecurringOccurrence{Date: date}
+			}
+
+			occurrenceResponse := toOccurrenceResponse(recurring, occurrence)
+			if !ok {
+				occurrenceResponse.Status = "upcoming"
+			}
+			result.Upcoming = append(result.Upcoming, occurrenceResponse)
+		}
+	}
+
+	var recent []entity.RecurringOccurrence
+	if err := s.DB.Where("recurring_transaction_id = ? AND date < ?", recurring.ID, recurring.NextRunDate).
+		Order("date DESC").
+		Limit(10).
+		Find(&recent).Error; err != nil {
+		logrus.Errorf("Error getting recent occurrences: %v", err)
+		return nil, errors.New("failed to get occurrences")
+	}
+
+	for _, occurrence := range recent {
+		result.Recent = append(result.Recent, toOccurrenceResponse(recurring, occurrence))
+	}
+
+	return &result, nil
+}
+
+// UpdateOccurrence melewati atau mengubah nominal/deskripsi satu occurrence yang belum diposting
+func (s *RecurringTransactionService) UpdateOccurrence(userID, recurringID uint, dateParam string, req request.UpdateOccurrenceRequest) (*response.RecurringOccurrenceResponse, error) {
+	date, err := time.Parse("2006-01-02", dateParam)
+	if err != nil {
+		return nil, errors.New("invalid date format")
+	}
+
+	var recurring entity.RecurringTransaction
+	var occurrence entity.RecurringOccurrence
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// lock template supaya tidak bentrok dengan scheduler yang sedang memposting
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", recurringID, userID).
+			First(&recurring).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecurringTransactionNotFound
+			}
+			return err
+		}
+
+		if date.Before(recurring.NextRunDate) {
+			return ErrOccurrenceAlreadyProcessed
+		}
+		if !recurring.Active || !isScheduledOccurrence(recurring, date) {
+			return ErrNotUpcomingOccurrence
+		}
+
+		occurrence = entity.RecurringOccurrence{
+			RecurringTransactionID: recurring.ID,
+			Date:                   date,
+			Status:                 entity.OccurrenceScheduled,
+			Amount:                 req.Amount,
+			Description:            req.Description,
+		}
+		if req.Skip {
+			occurrence.Status = entity.OccurrenceSkipped
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "recurring_transaction_id"}, {Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "amount", "description", "updated_at"}),
+		}).Create(&occurrence).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrRecurringTransactionNotFound) ||
+			errors.Is(err, ErrOccurrenceAlreadyProcessed) ||
+			errors.Is(err, ErrNotUpcomingOccurrence) {
+			return nil, err
+		}
+		logrus.Errorf("Error updating occurrence: %v", err)
+		return nil, errors.New("failed to update occurrence")
+	}
+
+	occurrenceResponse := toOccurrenceResponse(recurring, occurrence)
+	return &occurrenceResponse, nil
+}
+
+// MaterializeDue memposting semua occurrence yang sudah jatuh tempo. Dipanggil oleh
+// scheduler, termasuk saat startup untuk mengejar occurrence yang terlewat selama downtime.
+func (s *RecurringTransactionService) MaterializeDue(ctx context.Context) error {
+	now := time.Now()
+	// "hari ini" berbeda per timezone user, ambil kandidat sampai tanggal di timezone
+	// paling depan (UTC+14) lalu cek lagi dengan tanggal lokal masing-masing user
+	latestToday := utility.LocalDate(now, time.FixedZone("UTC+14", 14*60*60))
+
+	type dueRecurring struct {
+		ID          uint
+		NextRunDate time.Time
+		Timezone    string
+	}
+
+	var dues []dueRecurring
+	if err := s.DB.Model(&entity.RecurringTransaction{}).
+		Select("recurring_transactions.id, recurring_transactions.next_run_date, users.timezone").
+		Joins("JOIN users ON users.id = recurring_transactions.user_id").
+		Where("recurring_transactions.active = ? AND recurring_transactions.next_run_date <= ?", true, latestToday).
+		Scan(&dues).Error; err != nil {
+		return fmt.Errorf("failed to get due recurring transactions: %v", err)
+	}
+
+	for _, due := range dues {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		recurringID := due.ID
+		today := utility.LocalDate(now, utility.LoadLocation(due.Timezone))
+		if due.NextRunDate.After(today) {
+			continue
+		}
+
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			var recurring entity.RecurringTransaction
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				First(&recurring, recurringID).Error; err != nil {
+				return err
+			}
+
+			posted, err := s.materialize(tx, &recurring, today)
+			if posted > 0 {
+				logrus.Infof("Posted %d occurrence(s) for recurring transaction %d", posted, recurring.ID)
+			}
+			return err
+		})
+		if errors.Is(err, ErrLedgerNotFound) || errors.Is(err, ErrLedgerReadOnly) {
+			// pemilik recurring sudah bukan owner/editor ledger kategorinya, occurrence tidak
+			// diposting sampai aksesnya dikembalikan atau kategorinya diganti
+			logrus.Warnf("Skipped recurring transaction %d: %v", recurringID, err)
+		} else if err != nil {
+			logrus.Errorf("Failed to materialize recurring transaction %d: %v", recurringID, err)
+		}
+	}
+
+	return nil
+}
+
+// materialize memposting occurrence recurring sampai today. Harus dipanggil di dalam
+// transaksi DB dengan row recurring sudah dikunci.
+func (s *RecurringTransactionService) materialize(tx *gorm.DB, recurring *entity.RecurringTransaction, today time.Time) (int, error) {
+	posted := 0
+	currency := ""
+	var ledgerID uint
+
+	for i := 0; i < recurringMaxCatchUp && recurring.Active && !recurring.NextRunDate.After(today); i++ {
+		date := recurring.NextRunDate
+
+		var existing entity.RecurringOccurrence
+		if err := tx.Where("recurring_transaction_id = ? AND date = ?", recurring.ID, date).
+			Limit(1).
+			Find(&existing).Error; err != nil {
+			return posted, err
+		}
+
+		occurrence := existing
+		if existing.ID == 0 {
+			occurrence = entity.RecurringOccurrence{
+				RecurringTransactionID: recurring.ID,
+				Date:                   date,
+				Status:                 entity.OccurrenceScheduled,
+			}
+		}
+
+		if occurrence.Status == entity.OccurrenceScheduled {
+			// mata uang dan ledger hanya dicari jika memang ada occurrence yang diposting
+			if currency == "" {
+				var err error
+				if currency, err = transactionCurrency(tx, recurring.UserID, recurring.AccountID); err != nil {
+					return posted, err
+				}
+				if ledgerID, err = writableCategoryLedgerID(tx, recurring.UserID, recurring.CategoryID); err != nil {
+					return posted, err
+				}
+			}
+
+			transaction := entity.Transaction{
+				UserID:      recurring.UserID,
+				LedgerID:    ledgerID,
+				CategoryID:  recurring.CategoryID,
+				AccountID:   recurring.AccountID,
+				Amount:      recurring.Amount,
+				Currency:    currency,
+				Type:        recurring.Type,
+				Description: recurring.Description,
+				Date:        date,
+			}
+			if occurrence.Amount != nil {
+				transaction.Amount = *occurrence.Amount
+			}
+			if occurrence.Description != nil {
+				transaction.Description = *occurrence.Description
+			}
+
+			if err := tx.Create(&transaction).Error; err != nil {
+				return posted, err
+			}
+
+			occurrence.Status = entity.OccurrencePosted
+			occurrence.TransactionID = &transaction.ID
+			if err := tx.Save(&occurrence).Error; err != nil {
+				return posted, err
+			}
+			posted++
+		}
+
+		recurring.OccurrenceCount++
+		recurring.NextRunDate = utility.RecurrenceDate(recurring.StartDate, recurring.Frequency, recurring.Interval, recurring.OccurrenceCount)
+		if isRecurrenceFinished(*recurring) {
+			recurring.Active = false
+		}
+	}
+
+	return posted, tx.Model(recurring).
+		Select("occurrence_count", "next_run_date", "active").
+		Updates(recurring).Error
+}
+
+func (s *RecurringTransactionService) getUserCategory(userID, categoryID uint) (*entity.Category, error) {
+	var category entity.Category
+	if err := s.DB.Where("id = ? AND ledger_id IN (?)", categoryID, memberLedgers(s.DB, userID, true)).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		logrus.Errorf("Error getting category: %v", err)
+		return nil, errors.New("failed to get category")
+	}
+	return &category, nil
+}
+
+func (s *RecurringTransactionService) validateUserAccount(userID uint, accountID *uint) error {
+	if accountID == nil {
+		return nil
+	}
+
+	var count int64
+	if err := s.DB.Model(&entity.Account{}).Where("id = ? AND user_id = ?", *accountID, userID).Count(&count).Error; err != nil {
+		logrus.Errorf("Error getting account: %v", err)
+		return errors.New("failed to get account")
+	}
+	if count == 0 {
+		return errors.New("account not found")
+	}
+
+	return nil
+}
+
+// isRecurrenceFinished bernilai true jika occurrence berikutnya sudah di luar batas jadwal
+func isRecurrenceFinished(recurring entity.RecurringTransaction) bool {
+	return isOccurrenceOutOfRange(recurring, recurring.OccurrenceCount, recurring.NextRunDate)
+}
+
+func isOccurrenceOutOfRange(recurring entity.RecurringTransaction, n int, date time.Time) bool {
+	if recurring.MaxOccurrences != nil && n >= *recurring.MaxOccurrences {
+		return true
+	}
+	return recurring.EndDate != nil && date.After(*recurring.EndDate)
+}
+
+func isScheduledOccurrence(recurring entity.RecurringTransaction, date time.Time) bool {
+	for n := recurring.OccurrenceCount; n < recurring.OccurrenceCount+recurringMaxLookahead; n++ {
+		occurrenceDate := utility.RecurrenceDate(recurring.StartDate, recurring.Frequency, recurring.Interval, n)
+		if isOccurrenceOutOfRange(recurring, n, occurrenceDate) || occurrenceDate.After(date) {
+			return false
+		}
+		if occurrenceDate.Equal(date) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+func toRecurringTransactionResponse(recurring entity.RecurringTransaction) response.RecurringTransactionResponse {
+	var nextRunDate *time.Time
+	if recurring.Active {
+		nextRunDate = &recurring.NextRunDate
+	}
+
+	return response.RecurringTransactionResponse{
+		ID:              recurring.ID,
+		CategoryID:      recurring.CategoryID,
+		Category:        recurring.Category.Name,
+		AccountID:       recurring.AccountID,
+		Amount:          recurring.Amount,
+		Type:            recurring.Type,
+		Description:     recurring.Description,
+		Frequency:       recurring.Frequency,
+		Interval:        recurring.Interval,
+		StartDate:       recurring.StartDate,
+		EndDate:         recurring.EndDate,
+		MaxOccurrences:  recurring.MaxOccurrences,
+		OccurrenceCount: recurring.OccurrenceCount,
+		NextRunDate:     nextRunDate,
+		Active:          recurring.Active,
+		CreatedAt:       recurring.CreatedAt,
+		UpdatedAt:       recurring.UpdatedAt,
+	}
+}
+
+func toOccurrenceResponse(recurring entity.RecurringTransaction, occurrence entity.RecurringOccurrence) response.RecurringOccurrenceResponse {
+	occurrenceResponse := response.RecurringOccurrenceResponse{
+		Date:          occurrence.Date,
+		Status:        occurrence.Status,
+		Amount:        recurring.Amount,
+		Description:   recurring.Description,
+		TransactionID: occurrence.TransactionID,
+	}
+	if occurrence.Amount != nil {
+		occurrenceResponse.Amount = *occurrence.Amount
+	}
+	if occurrence.Description != nil {
+		occurrenceResponse.Description = *occurrence.Description
+	}
+	return occurrenceResponse
+}
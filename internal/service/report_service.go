@@ -0,0 +1,349 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/notifier"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/report"
+	"go-electroshop/internal/utility"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrReportScheduleNotFound = errors.New("report schedule not found")
+
+// jumlah transaksi expense terbesar yang ditampilkan di laporan
+const reportTopExpenseLimit = 10
+
+type ReportService struct {
+	DB            *gorm.DB
+	Notifier      notifier.Notifier
+	dashboardUtil *utility.DashboardUtil
+}
+
+func NewReportService(db *gorm.DB, n notifier.Notifier) *ReportService {
+	return &ReportService{
+		DB:            db,
+		Notifier:      n,
+		dashboardUtil: &utility.DashboardUtil{DB: db},
+	}
+}
+
+// GetMonthlyReport menyusun laporan bulan month (format 2006-01), default bulan berjalan
+// menurut timezone user
+func (s *ReportService) GetMonthlyReport(userID uint, month string) (*response.MonthlyReportResponse, error) {
+	monthlyReport, _, err := s.monthlyReport(userID, month)
+	return monthlyReport, err
+}
+
+// ExportMonthlyReport menghasilkan file laporan bulanan dalam format xlsx atau pdf
+func (s *ReportService) ExportMonthlyReport(userID uint, filter request.MonthlyReportFilter) (*bytes.Buffer, string, error) {
+	monthlyReport, preferences, err := s.monthlyReport(userID, filter.Month)
+	if err != nil {
+		return nil, "", err
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := report.Write(buffer, monthlyReport, filter.Format, preferences.Locale); err != nil {
+		logrus.Errorf("Error rendering monthly report: %v", err)
+		return nil, "", errors.New("failed to generate monthly report")
+	}
+
+	return buffer, report.Filename(monthlyReport.Month, filter.Format), nil
+}
+
+func (s *ReportService) monthlyReport(userID uint, month string) (*response.MonthlyReportResponse, *userPreferences, error) {
+	var start time.Time
+	if month != "" {
+		var err error
+		if start, err = time.Parse("2006-01", month); err != nil {
+			return nil, nil, errors.New("invalid month format, use YYYY-MM")
+		}
+	}
+
+	preferences, err := loadUserPreferences(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Error getting user preferences: %v", err)
+		return nil, nil, errors.New("failed to generate monthly report")
+	}
+
+	if start.IsZero() {
+		start = utility.BucketStart(utility.LocalDate(time.Now(), preferences.Location), utility.GranularityMonth)
+	}
+
+	monthlyReport, err := s.buildMonthlyReport(userID, start, preferences)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return monthlyReport, preferences, nil
+}
+
+// buildMonthlyReport menghitung isi laporan untuk bulan yang dimulai pada start, semua nominal
+// dalam base currency user
+func (s *ReportService) buildMonthlyReport(userID uint, start time.Time, preferences *userPreferences) (*response.MonthlyReportResponse, error) {
+	end := start.AddDate(0, 1, 0)
+	previous := start.AddDate(0, -1, 0)
+
+	openingBalance, err := s.dashboardUtil.CalculateBalanceAt(userID, start)
+	if err != nil {
+		logrus.Errorf("Error calculating opening balance: %v", err)
+		return nil, errors.New("failed to generate monthly report")
+	}
+
+	closingBalance, err := s.dashboardUtil.CalculateBalanceAt(userID, end)
+	if err != nil {
+		logrus.Errorf("Error calculating closing balance: %v", err)
+		return nil, errors.New("failed to generate monthly report")
+	}
+
+	series, err := s.dashboardUtil.GetIncomeExpenseSeries(userID, []time.Time{previous, start}, end, utility.GranularityMonth)
+	if err != nil {
+		logrus.Errorf("Error getting income expense totals: %v", err)
+		return nil, errors.New("failed to generate monthly report")
+	}
+
+	categories, err := s.dashboardUtil.GetCategoryTotals(userID, start, end)
+	if err != nil {
+		logrus.Errorf("Error getting category totals: %v", err)
+		return nil, errors.New("failed to generate monthly report")
+	}
+
+	previousCategories, err := s.dashboardUtil.GetCategoryTotals(userID, previous, start)
+	if err != nil {
+		logrus.Errorf("Error getting previous category totals: %v", err)
+		return nil, errors.New("failed to generate monthly report")
+	}
+
+	topExpenses, err := s.dashboardUtil.GetTopExpenses(userID, start, end, reportTopExpenseLimit)
+	if err != nil {
+		logrus.Errorf("Error getting top expenses: %v", err)
+		return nil, errors.New("failed to generate monthly report")
+	}
+
+	spendings, err := s.dashboardUtil.GetBudgetSpending(userID, start, end)
+	if err != nil {
+		logrus.Errorf("Error getting budget spending: %v", err)
+		return nil, errors.New("failed to generate monthly report")
+	}
+
+	current, last := series[1], series[0]
+	monthlyReport := &response.MonthlyReportResponse{
+		Month:          start.Format("2006-01"),
+		Label:          utility.BucketLabel(start, utility.GranularityMonth, preferences.Locale),
+		PeriodStart:    start,
+		PeriodEnd:      end.AddDate(0, 0, -1),
+		Currency:       preferences.BaseCurrency,
+		OpeningBalance: openingBalance,
+		ClosingBalance: closingBalance,
+		Income:         current.Income,
+		Expense:        current.Expense,
+		Net:            current.Income - current.Expense,
+		Categories:     mergeCategoryTotals(categories, previousCategories),
+		TopExpenses:    make([]response.ReportExpense, len(topExpenses)),
+		Budgets:        make([]response.BudgetResponse, len(spendings)),
+		Comparison: response.ReportComparison{
+			PreviousMonth:   previous.Format("2006-01"),
+			PreviousLabel:   utility.BucketLabel(previous, utility.GranularityMonth, preferences.Locale),
+			PreviousIncome:  last.Income,
+			PreviousExpense: last.Expense,
+			PreviousNet:     last.Income - last.Expense,
+			IncomeChange:    percentChange(current.Income, last.Income),
+			ExpenseChange:   percentChange(current.Expense, last.Expense),
+		},
+	}
+
+	for i, expense := range topExpenses {
+		monthlyReport.TopExpenses[i] = response.ReportExpense{
+			TransactionID: expense.TransactionID,
+			Date:          expense.Date,
+			Category:      expense.Category,
+			Description:   expense.Description,
+			Amount:        expense.Amount,
+			Currency:      expense.Currency,
+			BaseAmount:    expense.BaseAmount,
+		}
+	}
+
+	for i, spending := range spendings {
+		monthlyReport.Budgets[i] = toBudgetResponse(spending)
+	}
+
+	return monthlyReport, nil
+}
+
+// mergeCategoryTotals menggabungkan total kategori bulan laporan dengan bulan sebelumnya.
+// Kategori yang hanya ada di bulan sebelumnya tetap ditampilkan dengan amount 0.
+func mergeCategoryTotals(current, previous []utility.CategoryTypeTotal) []response.ReportCategoryTotal {
+	totals := make([]response.ReportCategoryTotal, 0, len(current))
+	index := make(map[string]int, len(current))
+
+	for _, total := range current {
+		index[total.Type+"|"+total.Category] = len(totals)
+		totals = append(totals, response.ReportCategoryTotal{Category: total.Category, Type: total.Type, Amount: total.Total})
+	}
+
+	for _, total := range previous {
+		if i, ok := index[total.Type+"|"+total.Category]; ok {
+			totals[i].PreviousAmount = total.Total
+			continue
+		}
+		totals = append(totals, response.ReportCategoryTotal{Category: total.Category, Type: total.Type, PreviousAmount: total.Total})
+	}
+
+	return totals
+}
+
+// percentChange menghitung perubahan dalam persen, nil jika previous 0
+func percentChange(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := math.Round((current-previous)/math.Abs(previous)*10000) / 100
+	return &change
+}
+
+func (s *ReportService) GetReportSchedule(userID uint) (*response.ReportScheduleResponse, error) {
+	var schedule entity.ReportSchedule
+	if err := s.DB.Where("user_id = ?", userID).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportScheduleNotFound
+		}
+		logrus.Errorf("Error getting report schedule: %v", err)
+		return nil, errors.New("failed to get report schedule")
+	}
+
+	return toReportScheduleResponse(schedule), nil
+}
+
+// SaveReportSchedule membuat atau mengganti jadwal laporan bulanan user
+func (s *ReportService) SaveReportSchedule(userID uint, req request.ReportScheduleRequest) (*response.ReportScheduleResponse, error) {
+	schedule := entity.ReportSchedule{
+		UserID:     userID,
+		Format:     req.Format,
+		DayOfMonth: req.DayOfMonth,
+		Enabled:    true,
+	}
+	if schedule.DayOfMonth == 0 {
+		schedule.DayOfMonth = 1
+	}
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+
+	if err := s.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"format":       schedule.Format,
+			"day_of_month": schedule.DayOfMonth,
+			"enabled":      schedule.Enabled,
+			"updated_at":   time.Now(),
+		}),
+	}).Create(&schedule).Error; err != nil {
+		logrus.Errorf("Error saving report schedule: %v", err)
+		return nil, errors.New("failed to save report schedule")
+	}
+
+	return s.GetReportSchedule(userID)
+}
+
+func (s *ReportService) DeleteReportSchedule(userID uint) error {
+	result := s.DB.Where("user_id = ?", userID).Delete(&entity.ReportSchedule{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting report schedule: %v", result.Error)
+		return errors.New("failed to delete report schedule")
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrReportScheduleNotFound
+	}
+
+	return nil
+}
+
+// DeliverScheduledReports mengirim laporan bulan sebelumnya ke user yang jadwalnya sudah jatuh
+// tempo menurut timezone masing-masing. Dipanggil oleh scheduler, laporan yang sudah terkirim
+// dicatat di LastPeriod sehingga aman dijalankan berulang.
+func (s *ReportService) DeliverScheduledReports(ctx context.Context) error {
+	var schedules []entity.ReportSchedule
+	if err := s.DB.Where("enabled = ?", true).Find(&schedules).Error; err != nil {
+		return fmt.Errorf("failed to get report schedules: %v", err)
+	}
+
+	now := time.Now()
+	for _, schedule := range schedules {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := s.deliverReport(ctx, schedule, now); err != nil {
+			logrus.Errorf("Failed to deliver monthly report to user %d: %v", schedule.UserID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *ReportService) deliverReport(ctx context.Context, schedule entity.ReportSchedule, now time.Time) error {
+	preferences, err := loadUserPreferences(s.DB, schedule.UserID)
+	if err != nil {
+		return err
+	}
+
+	today := utility.LocalDate(now, preferences.Location)
+	if today.Day() < schedule.DayOfMonth {
+		return nil
+	}
+
+	period := utility.BucketStart(today, utility.GranularityMonth).AddDate(0, -1, 0)
+	if schedule.LastPeriod != nil && !schedule.LastPeriod.Before(period) {
+		return nil
+	}
+
+	monthlyReport, err := s.buildMonthlyReport(schedule.UserID, period, preferences)
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	if err := report.Write(&buffer, monthlyReport, schedule.Format, preferences.Locale); err != nil {
+		return err
+	}
+
+	msg := notifier.Message{
+		Subject: "Monthly report " + monthlyReport.Label,
+		Body: fmt.Sprintf("Your financial report for %s is attached. Income %.2f %s, expense %.2f %s.",
+			monthlyReport.Label, monthlyReport.Income, monthlyReport.Currency, monthlyReport.Expense, monthlyReport.Currency),
+		Attachments: []notifier.Attachment{{
+			Filename:    report.Filename(monthlyReport.Month, schedule.Format),
+			ContentType: report.ContentType(schedule.Format),
+			Data:        buffer.Bytes(),
+		}},
+	}
+	if err := s.Notifier.Notify(ctx, schedule.UserID, msg); err != nil {
+		return err
+	}
+
+	// dicatat setelah terkirim supaya pengiriman yang gagal diulang pada run berikutnya
+	return s.DB.Model(&schedule).Update("last_period", period).Error
+}
+
+func toReportScheduleResponse(schedule entity.ReportSchedule) *response.ReportScheduleResponse {
+	return &response.ReportScheduleResponse{
+		ID:         schedule.ID,
+		Format:     schedule.Format,
+		DayOfMonth: schedule.DayOfMonth,
+		Enabled:    schedule.Enabled,
+		LastPeriod: schedule.LastPeriod,
+		UpdatedAt:  schedule.UpdatedAt,
+	}
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSavingsGoalNotFound         = errors.New("savings goal not found")
+	ErrSavingsContributionNotFound = errors.New("savings contribution not found")
+)
+
+type SavingsGoalService struct {
+	DB            *gorm.DB
+	dashboardUtil *utility.DashboardUtil
+}
+
+func NewSavingsGoalService(db *gorm.DB) *SavingsGoalService {
+	return &SavingsGoalService{
+		DB:            db,
+		dashboardUtil: &utility.DashboardUtil{DB: db},
+	}
+}
+
+// GetSavingsGoals mengembalikan semua goal user beserta progress dan proyeksi tanggal tercapainya
+func (s *SavingsGoalService) GetSavingsGoals(userID uint) ([]response.SavingsGoalResponse, error) {
+	var goals []entity.SavingsGoal
+	if err := s.DB.Where("user_id = ?", userID).Order("id ASC").Find(&goals).Error; err != nil {
+		logrus.Errorf("Error getting savings goals: %v", err)
+		return nil, errors.New("failed to get savings goals")
+	}
+
+	today, err := userToday(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Error getting user timezone: %v", err)
+		return nil, errors.New("failed to get savings goals")
+	}
+
+	return buildSavingsGoalResponses(s.dashboardUtil, userID, goals, today)
+}
+
+func (s *SavingsGoalService) GetSavingsGoal(userID, goalID uint) (*response.SavingsGoalResponse, error) {
+	goal, err := s.getUserGoal(userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	today, err := userToday(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Error getting user timezone: %v", err)
+		return nil, errors.New("failed to get savings goal")
+	}
+
+	goalResponses, err := buildSavingsGoalResponses(s.dashboardUtil, userID, []entity.SavingsGoal{*goal}, today)
+	if err != nil {
+		return nil, err
+	}
+
+	return &goalResponses[0], nil
+}
+
+func (s *SavingsGoalService) CreateSavingsGoal(userID uint, req request.SavingsGoalRequest) (*response.SavingsGoalResponse, error) {
+	goal := entity.SavingsGoal{UserID: userID}
+	if err := s.applyGoalRequest(userID, &goal, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Create(&goal).Error; err != nil {
+		logrus.Errorf("Error creating savings goal: %v", err)
+		return nil, errors.New("failed to create savings goal")
+	}
+
+	return s.GetSavingsGoal(userID, goal.ID)
+}
+
+func (s *SavingsGoalService) UpdateSavingsGoal(userID, goalID uint, req request.SavingsGoalRequest) (*response.SavingsGoalResponse, error) {
+	goal, err := s.getUserGoal(userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyGoalRequest(userID, goal, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Save(goal).Error; err != nil {
+		logrus.Errorf("Error updating savings goal: %v", err)
+		return nil, errors.New("failed to update savings goal")
+	}
+
+	return s.GetSavingsGoal(userID, goal.ID)
+}
+
+func (s *SavingsGoalService) DeleteSavingsGoal(userID, goalID uint) error {
+	result := s.DB.Where("id = ? AND user_id = ?", goalID, userID).Delete(&entity.SavingsGoal{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting savings goal: %v", result.Error)
+		return errors.New("failed to delete savings goal")
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSavingsGoalNotFound
+	}
+
+	return nil
+}
+
+func (s *SavingsGoalService) GetContributions(userID, goalID uint) ([]response.SavingsContributionResponse, error) {
+	if _, err := s.getUserGoal(userID, goalID); err != nil {
+		return nil, err
+	}
+
+	var contributions []entity.SavingsContribution
+	if err := s.DB.Where("goal_id = ?", goalID).Order("date DESC, id DESC").Find(&contributions).Error; err != nil {
+		logrus.Errorf("Error getting savings contributions: %v", err)
+		return nil, errors.New("failed to get savings contributions")
+	}
+
+	contributionResponses := make([]response.SavingsContributionResponse, len(contributions))
+	for i, contribution := range contributions {
+		contributionResponses[i] = toSavingsContributionResponse(contribution)
+	}
+
+	return contributionResponses, nil
+}
+
+// AddContribution mencatat setoran (atau penarikan jika amount negatif) dan mengembalikan progress goal terbaru
+func (s *SavingsGoalService) AddContribution(userID, goalID uint, req request.SavingsContributionRequest) (*response.SavingsGoalResponse, error) {
+	goal, err := s.getUserGoal(userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := loadUserPreferences(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Error getting user timezone: %v", err)
+		return nil, errors.New("failed to add contribution")
+	}
+
+	date := utility.LocalDate(time.Now(), preferences.Location)
+	if req.Date != "" {
+		date, err = utility.ParseLocalDate(req.Date, preferences.Location)
+		if err != nil {
+			return nil, errors.New("invalid date format")
+		}
+	}
+
+	contribution := entity.SavingsContribution{
+		GoalID: goal.ID,
+		Amount: req.Amount,
+		Date:   date,
+		Note:   strings.TrimSpace(req.Note),
+	}
+	if err := s.DB.Create(&contribution).Error; err != nil {
+		logrus.Errorf("Error creating savings contribution: %v", err)
+		return nil, errors.New("failed to add contribution")
+	}
+
+	return s.GetSavingsGoal(userID, goal.ID)
+}
+
+func (s *SavingsGoalService) DeleteContribution(userID, goalID, contributionID uint) error {
+	if _, err := s.getUserGoal(userID, goalID); err != nil {
+		return err
+	}
+
+	result := s.DB.Where("id = ? AND goal_id = ?", contributionID, goalID).Delete(&entity.SavingsContribution{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting savings contribution: %v", result.Error)
+		return errors.New("failed to delete contribution")
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSavingsContributionNotFound
+	}
+
+	return nil
+}
+
+func (s *SavingsGoalService) getUserGoal(userID, goalID uint) (*entity.SavingsGoal, error) {
+	var goal entity.SavingsGoal
+	if err := s.DB.Where("id = ? AND user_id = ?", goalID, userID).First(&goal).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSavingsGoalNotFound
+		}
+		logrus.Errorf("Error getting savings goal: %v", err)
+		return nil, errors.New("failed to get savings goal")
+	}
+
+	return &goal, nil
+}
+
+// applyGoalRequest memvalidasi tanggal serta kepemilikan account/kategori lalu mengisi field goal
+func (s *SavingsGoalService) applyGoalRequest(userID uint, goal *entity.SavingsGoal, req request.SavingsGoalRequest) error {
+	targetDate, err := parseOptionalDate(req.TargetDate)
+	if err != nil {
+		return errors.New("invalid target_date format")
+	}
+
+	startDate, err := parseOptionalDate(req.StartDate)
+	if err != nil {
+		return errors.New("invalid start_date format")
+	}
+	if startDate == nil {
+		if goal.StartDate.IsZero() {
+			today, err := userToday(s.DB, userID)
+			if err != nil {
+				logrus.Errorf("Error getting user timezone: %v", err)
+				return errors.New("failed to get user settings")
+			}
+			startDate = &today
+		} else {
+			startDate = &goal.StartDate
+		}
+	}
+
+	if req.AccountID != nil {
+		var count int64
+		if err := s.DB.Model(&entity.Account{}).Where("id = ? AND user_id = ?", *req.AccountID, userID).Count(&count).Error; err != nil {
+			logrus.Errorf("Error getting account for savings goal: %v", err)
+			return errors.New("failed to get account")
+		}
+		if count == 0 {
+			return errors.New("account not found")
+		}
+	}
+
+	if req.CategoryID != nil {
+		var count int64
+		if err := s.DB.Model(&entity.Category{}).Where("id = ? AND ledger_id IN (?)", *req.CategoryID, memberLedgers(s.DB, userID, true)).Count(&count).Error; err != nil {
+			logrus.Errorf("Error getting category for savings goal: %v", err)
+			return errors.New("failed to get category")
+		}
+		if count == 0 {
+			return errors.New("category not found")
+		}
+	}
+
+	goal.Name = strings.TrimSpace(req.Name)
+	goal.TargetAmount = req.TargetAmount
+	goal.TargetDate = targetDate
+	goal.StartDate = *startDate
+	goal.AccountID = req.AccountID
+	goal.CategoryID = req.CategoryID
+
+	return goal.Validate()
+}
+
+// buildSavingsGoalResponses menghitung dana terkumpul dan proyeksi setiap goal.
+// Dipakai juga oleh dashboard overview.
+func buildSavingsGoalResponses(dashboardUtil *utility.DashboardUtil, userID uint, goals []entity.SavingsGoal, now time.Time) ([]response.SavingsGoalResponse, error) {
+	goalResponses := make([]response.SavingsGoalResponse, len(goals))
+	if len(goals) == 0 {
+		return goalResponses, nil
+	}
+
+	flows, err := dashboardUtil.GetSavingsGoalFlows(userID, now.Add(-utility.SavingsRateWindow))
+	if err != nil {
+		logrus.Errorf("Error calculating savings goal progress: %v", err)
+		return nil, errors.New("failed to get savings goals")
+	}
+
+	for i, goal := range goals {
+		// arus dana account/kategori baru dihitung sejak start date, jadi window
+		// laju kontribusi ikut dipotong oleh start date goal
+		flow := flows[goal.ID]
+		projection := utility.ProjectSavingsGoal(goal.TargetAmount, flow.Total, flow.Recent, goal.StartDate, goal.TargetDate, now)
+
+		goalResponses[i] = response.SavingsGoalResponse{
+			ID:                          goal.ID,
+			Name:                        goal.Name,
+			TargetAmount:                goal.TargetAmount,
+			TargetDate:                  goal.TargetDate,
+			StartDate:                   goal.StartDate,
+			AccountID:                   goal.AccountID,
+			CategoryID:                  goal.CategoryID,
+			SavedAmount:                 flow.Total,
+			RemainingAmount:             projection.Remaining,
+			ProgressPercentage:          projection.ProgressPercentage,
+			Completed:                   projection.Completed,
+			MonthlyContributionRate:     projection.MonthlyRate,
+			RequiredMonthlyContribution: projection.RequiredMonthly,
+			ProjectedCompletionDate:     projection.ProjectedCompletionDate,
+			OnTrack:                     projection.OnTrack,
+		}
+	}
+
+	return goalResponses, nil
+}
+
+func toSavingsContributionResponse(contribution entity.SavingsContribution) response.SavingsContributionResponse {
+	return response.SavingsContributionResponse{
+		ID:        contribution.ID,
+		GoalID:    contribution.GoalID,
+		Amount:    contribution.Amount,
+		Date:      contribution.Date,
+		Note:      contribution.Note,
+		CreatedAt: contribution.CreatedAt,
+	}
+}
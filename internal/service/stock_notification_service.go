@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/notifier"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/response"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// jumlah subscriber yang dinotifikasi untuk setiap unit stok dalam satu batch
+	restockNotifyPerUnit = 3
+	// batas maksimal subscriber per produk dalam satu batch
+	restockMaxBatchSize = 100
+	// jeda minimal antar batch untuk produk yang sama
+	restockBatchInterval = 15 * time.Minute
+)
+
+type StockNotificationService struct {
+	DB       *gorm.DB
+	Notifier notifier.Notifier
+}
+
+func NewStockNotificationService(db *gorm.DB, n notifier.Notifier) *StockNotificationService {
+	return &StockNotificationService{DB: db, Notifier: n}
+}
+
+// Subscribe mendaftarkan user ke antrian notifikasi produk yang stoknya habis
+func (s *StockNotificationService) Subscribe(userID, productID uint) (*response.StockSubscriptionResponse, error) {
+	var product entity.Product
+	if err := s.DB.First(&product, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		logrus.Errorf("Error getting product for stock subscription: %v", err)
+		return nil, errors.New("failed to get product")
+	}
+
+	if product.InStock() {
+		return nil, errors.New("product is still in stock")
+	}
+
+	subscription := entity.StockSubscription{
+		UserID:    userID,
+		ProductID: productID,
+		CreatedAt: time.Now(),
+	}
+
+	// subscribe ulang setelah dinotifikasi akan masuk lagi di akhir antrian
+	if err := s.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"created_at":  gorm.Expr("CASE WHEN stock_subscriptions.notified_at IS NULL THEN stock_subscriptions.created_at ELSE ? END", subscription.CreatedAt),
+			"notified_at": nil,
+		}),
+	}).Create(&subscription).Error; err != nil {
+		logrus.Errorf("Error saving stock subscription: %v", err)
+		return nil, errors.New("failed to save stock subscription")
+	}
+
+	if err := s.DB.Where("user_id = ? AND product_id = ?", userID, productID).First(&subscription).Error; err != nil {
+		logrus.Errorf("Error getting stock subscription: %v", err)
+		return nil, errors.New("failed to save stock subscription")
+	}
+	subscription.Product = product
+
+	return toStockSubscriptionResponse(subscription), nil
+}
+
+func (s *StockNotificationService) Unsubscribe(userID, productID uint) error {
+	result := s.DB.Where("user_id = ? AND product_id = ?", userID, productID).Delete(&entity.StockSubscription{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting stock subscription: %v", result.Error)
+		return errors.New("failed to delete stock subscription")
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("stock subscription not found")
+	}
+
+	return nil
+}
+
+func (s *StockNotificationService) GetUserSubscriptions(userID uint) ([]response.StockSubscriptionResponse, error) {
+	var subscriptions []entity.StockSubscription
+	if err := s.DB.Preload("Product").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&subscriptions).Error; err != nil {
+		logrus.Errorf("Error getting stock subscriptions: %v", err)
+		return nil, errors.New("failed to get stock subscriptions")
+	}
+
+	subscriptionResponses := make([]response.StockSubscriptionResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		subscriptionResponses[i] = *toStockSubscriptionResponse(subscription)
+	}
+
+	return subscriptionResponses, nil
+}
+
+// NotifyRestock mengirim batch pertama notifikasi saat stok produk terisi kembali
+func (s *StockNotificationService) NotifyRestock(ctx context.Context, productID uint) error {
+	_, err := s.dispatchBatch(ctx, productID)
+	return err
+}
+
+// DispatchPending mengirim batch berikutnya untuk semua produk yang masih ada stok
+// dan masih punya subscriber yang belum dinotifikasi. Dipanggil oleh scheduler.
+func (s *StockNotificationService) DispatchPending(ctx context.Context) error {
+	var productIDs []uint
+	if err := s.DB.Table("stock_subscriptions").
+		Joins("JOIN products ON products.id = stock_subscriptions.product_id AND products.deleted_at IS NULL").
+		Where("stock_subscriptions.notified_at IS NULL AND products.stock > 0").
+		Distinct("stock_subscriptions.product_id").
+		Pluck("stock_subscriptions.product_id", &productIDs).Error; err != nil {
+		return fmt.Errorf("failed to get products with pending subscriptions: %v", err)
+	}
+
+	for _, productID := range productIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if _, err := s.dispatchBatch(ctx, productID); err != nil {
+			logrus.Errorf("Failed to dispatch stock notifications for product %d: %v", productID, err)
+		}
+	}
+
+	return nil
+}
+
+// dispatchBatch menotifikasi subscriber terlama sejumlah kelipatan stok yang tersedia,
+// dengan jeda restockBatchInterval antar batch untuk produk yang sama
+func (s *StockNotificationService) dispatchBatch(ctx context.Context, productID uint) (int, error) {
+	var product entity.Product
+	var batch []entity.StockSubscription
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// lock produk supaya dua dispatcher tidak mengirim batch yang sama
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error; err != nil {
+			return err
+		}
+
+		// produk yang stoknya tidak dilacak tidak punya antrian notifikasi
+		if product.Stock == nil || *product.Stock <= 0 {
+			return nil
+		}
+
+		var lastNotifiedAt sql.NullTime
+		if err := tx.Model(&entity.StockSubscription{}).
+			Select("MAX(notified_at)").
+			Where("product_id = ?", productID).
+			Row().
+			Scan(&lastNotifiedAt); err != nil {
+			return err
+		}
+
+		if lastNotifiedAt.Valid && time.Since(lastNotifiedAt.Time) < restockBatchInterval {
+			return nil
+		}
+
+		batchSize := *product.Stock * restockNotifyPerUnit
+		if batchSize > restockMaxBatchSize {
+			batchSize = restockMaxBatchSize
+		}
+
+		if err := tx.Where("product_id = ? AND notified_at IS NULL", productID).
+			Order("created_at ASC, id ASC").
+			Limit(batchSize).
+			Find(&batch).Error; err != nil {
+			return err
+		}
+
+		if len(batch) == 0 {
+			return nil
+		}
+
+		subscriptionIDs := make([]uint, len(batch))
+		for i, subscription := range batch {
+			subscriptionIDs[i] = subscription.ID
+		}
+
+		return tx.Model(&entity.StockSubscription{}).
+			Where("id IN ?", subscriptionIDs).
+			Update("notified_at", time.Now()).Error
+	})
+	if err != nil {
+		logrus.Errorf("Error dispatching stock notifications: %v", err)
+		return 0, errors.New("failed to dispatch stock notifications")
+	}
+
+	for _, subscription := range batch {
+		msg := notifier.Message{
+			Subject: fmt.Sprintf("Back in stock: %s", product.Name),
+			Body:    fmt.Sprintf("%s is available again. Stock is limited, get yours before it runs out.", product.Name),
+		}
+		if err := s.Notifier.Notify(ctx, subscription.UserID, msg); err != nil {
+			logrus.Errorf("Failed to send stock notification %d: %v", subscription.ID, err)
+		}
+	}
+
+	return len(batch), nil
+}
+
+func toStockSubscriptionResponse(subscription entity.StockSubscription) *response.StockSubscriptionResponse {
+	return &response.StockSubscriptionResponse{
+		ID:          subscription.ID,
+		ProductID:   subscription.ProductID,
+		ProductName: subscription.Product.Name,
+		Stock:       subscription.Product.Stock,
+		NotifiedAt:  subscription.NotifiedAt,
+		CreatedAt:   subscription.CreatedAt,
+	}
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrTagNotFound = errors.New("tag not found")
+
+var tagSeparator = regexp.MustCompile(`[\s_]+`)
+
+type TagService struct {
+	DB *gorm.DB
+}
+
+func NewTagService(db *gorm.DB) *TagService {
+	return &TagService{DB: db}
+}
+
+func (s *TagService) GetTags(userID uint) ([]response.TagResponse, error) {
+	var tags []response.TagResponse
+	if err := s.DB.Table("tags").
+		Select("tags.id, tags.name, COUNT(transactions.id) AS transaction_count").
+		Joins("LEFT JOIN transaction_tags ON transaction_tags.tag_id = tags.id").
+		Joins("LEFT JOIN transactions ON transactions.id = transaction_tags.transaction_id AND transactions.deleted_at IS NULL").
+		Where("tags.user_id = ?", userID).
+		Group("tags.id, tags.name").
+		Order("tags.name ASC").
+		Scan(&tags).Error; err != nil {
+		logrus.Errorf("Error getting tags: %v", err)
+		return nil, errors.New("failed to get tags")
+	}
+
+	return tags, nil
+}
+
+// RenameTag mengganti nama tag, semua transaksi yang memakai tag ikut berubah
+func (s *TagService) RenameTag(userID, tagID uint, req request.TagRequest) (*response.TagResponse, error) {
+	names := normalizeTags([]string{req.Name})
+	if len(names) == 0 {
+		return nil, errors.New("tag name cannot be empty")
+	}
+
+	var tag entity.Tag
+	if err := s.DB.Where("id = ? AND user_id = ?", tagID, userID).First(&tag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		logrus.Errorf("Error getting tag: %v", err)
+		return nil, errors.New("failed to get tag")
+	}
+
+	var count int64
+	if err := s.DB.Model(&entity.Tag{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, names[0], tagID).
+		Count(&count).Error; err != nil {
+		logrus.Errorf("Error checking tag name: %v", err)
+		return nil, errors.New("failed to update tag")
+	}
+	if count > 0 {
+		return nil, errors.New("tag name already exists")
+	}
+
+	tag.Name = names[0]
+	if err := s.DB.Save(&tag).Error; err != nil {
+		logrus.Errorf("Error updating tag: %v", err)
+		return nil, errors.New("failed to update tag")
+	}
+
+	return &response.TagResponse{ID: tag.ID, Name: tag.Name}, nil
+}
+
+// DeleteTag menghapus tag dari semua transaksi, transaksinya sendiri tidak dihapus
+func (s *TagService) DeleteTag(userID, tagID uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", tagID, userID).Delete(&entity.Tag{})
+		if result.Error != nil {
+			logrus.Errorf("Error deleting tag: %v", result.Error)
+			return errors.New("failed to delete tag")
+		}
+		if result.RowsAffected == 0 {
+			return ErrTagNotFound
+		}
+
+		if err := tx.Exec("DELETE FROM transaction_tags WHERE tag_id = ?", tagID).Error; err != nil {
+			logrus.Errorf("Error deleting tag relations: %v", err)
+			return errors.New("failed to delete tag")
+		}
+
+		return nil
+	})
+}
+
+// GetTagSummary menghitung income dan expense per tag. Transaksi dengan beberapa tag
+// dihitung di setiap tag-nya, jadi total antar tag tidak boleh dijumlahkan. Total dalam base currency.
+func (s *TagService) GetTagSummary(userID uint, filter request.TagSummaryFilter) ([]response.TagSummary, error) {
+	joinCondition := "transactions.id = transaction_tags.transaction_id AND transactions.deleted_at IS NULL"
+	var joinArgs []interface{}
+
+	if filter.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", filter.StartDate)
+		if err != nil {
+			return nil, errors.New("invalid start date format")
+		}
+		joinCondition += " AND transactions.date >= ?"
+		joinArgs = append(joinArgs, startDate)
+	}
+	if filter.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", filter.EndDate)
+		if err != nil {
+			return nil, errors.New("invalid end date format")
+		}
+		joinCondition += " AND transactions.date < ?"
+		joinArgs = append(joinArgs, endDate.AddDate(0, 0, 1))
+	}
+
+	type tagTotal struct {
+		TagID            uint    `gorm:"column:tag_id"`
+		Tag              string  `gorm:"column:tag"`
+		TransactionCount int64   `gorm:"column:transaction_count"`
+		TotalIncome      float64 `gorm:"column:total_income"`
+		TotalExpense     float64 `gorm:"column:total_expense"`
+	}
+
+	var results []tagTotal
+	if err := s.DB.Table("tags").
+		Select("tags.id AS tag_id, tags.name AS tag, COUNT(transactions.id) AS transaction_count, "+
+			"COALESCE(SUM(CASE WHEN transactions.type = 'income' THEN "+utility.TransactionBaseAmountSQL+" ELSE 0 END), 0) AS total_income, "+
+			"COALESCE(SUM(CASE WHEN transactions.type = 'expense' THEN "+utility.TransactionBaseAmountSQL+" ELSE 0 END), 0) AS total_expense").
+		Joins("LEFT JOIN transaction_tags ON transaction_tags.tag_id = tags.id").
+		Joins("LEFT JOIN transactions ON "+joinCondition, joinArgs...).
+		Where("tags.user_id = ?", userID).
+		Group("tags.id, tags.name").
+		Order("total_expense DESC, tags.name ASC").
+		Scan(&results).Error; err != nil {
+		logrus.Errorf("Error getting tag summary: %v", err)
+		return nil, errors.New("failed to get tag summary")
+	}
+
+	summaries := make([]response.TagSummary, len(results))
+	for i, result := range results {
+		summaries[i] = response.TagSummary{
+			TagID:            result.TagID,
+			Tag:              result.Tag,
+			TransactionCount: result.TransactionCount,
+			TotalIncome:      result.TotalIncome,
+			TotalExpense:     result.TotalExpense,
+			Balance:          result.TotalIncome - result.TotalExpense,
+		}
+	}
+
+	return summaries, nil
+}
+
+// resolveTags mengembalikan tag user dengan nama tersebut, tag yang belum ada dibuat
+func resolveTags(db *gorm.DB, userID uint, names []string) ([]entity.Tag, error) {
+	names = normalizeTags(names)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	newTags := make([]entity.Tag, len(names))
+	for i, name := range names {
+		newTags[i] = entity.Tag{UserID: userID, Name: name}
+	}
+	// tag yang sudah ada dilewati, aman untuk request paralel dengan tag yang sama
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags).Error; err != nil {
+		logrus.Errorf("Error creating tags: %v", err)
+		return nil, errors.New("failed to save tags")
+	}
+
+	var tags []entity.Tag
+	if err := db.Where("user_id = ? AND name IN ?", userID, names).Order("name ASC").Find(&tags).Error; err != nil {
+		logrus.Errorf("Error getting tags: %v", err)
+		return nil, errors.New("failed to save tags")
+	}
+
+	return tags, nil
+}
+
+// normalizeTags menjadikan tag lowercase dengan "-" sebagai pemisah kata dan membuang duplikat
+func normalizeTags(names []string) []string {
+	seen := map[string]bool{}
+	var normalized []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.Trim(tagSeparator.ReplaceAllString(name, "-"), "-")
+		name = strings.TrimPrefix(name, "#")
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
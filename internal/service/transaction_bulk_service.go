@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// batas jumlah transaksi yang bisa diubah dalam satu bulk action
+const maxBulkTransactions = 5000
+
+var (
+	ErrBulkTransactionsNotFound = errors.New("transactions not found")
+	ErrBulkSelectionTooLarge    = fmt.Errorf("selection matches more than %d transactions, narrow the filter", maxBulkTransactions)
+)
+
+// BulkUpdateTransactions menjalankan satu aksi pada banyak transaksi sekaligus dalam satu
+// transaksi DB. Seperti update satu per satu, aksi bisa dilakukan owner dan editor ledger
+// transaksi. Jika ada ID yang tidak ditemukan atau tidak bisa diubah user, tidak ada yang diubah.
+func (s *TransactionService) BulkUpdateTransactions(userID uint, req request.BulkTransactionRequest) (*response.BulkTransactionResponse, error) {
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		return nil, errors.New("either ids or filter is required")
+	}
+	if err := s.validateBulkAction(userID, req); err != nil {
+		return nil, err
+	}
+
+	result := &response.BulkTransactionResponse{Action: req.Action}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		transactionIDs, err := s.selectBulkTransactions(tx, userID, req)
+		if err != nil {
+			return err
+		}
+		result.Matched = int64(len(transactionIDs))
+		if len(transactionIDs) == 0 {
+			return nil
+		}
+
+		return applyBulkAction(tx, userID, req, transactionIDs, result)
+	})
+	if err != nil {
+		if errors.Is(err, ErrBulkTransactionsNotFound) || errors.Is(err, ErrBulkSelectionTooLarge) ||
+			errors.Is(err, ErrLedgerNotFound) || errors.Is(err, ErrLedgerReadOnly) {
+			return nil, err
+		}
+		logrus.Errorf("Error running bulk %s on transactions: %v", req.Action, err)
+		return nil, errors.New("failed to update transactions")
+	}
+
+	return result, nil
+}
+
+func (s *TransactionService) validateBulkAction(userID uint, req request.BulkTransactionRequest) error {
+	switch req.Action {
+	case request.BulkActionRecategorize:
+		if req.CategoryID == 0 {
+			return errors.New("category_id is required")
+		}
+		var count int64
+		if err := s.DB.Model(&entity.Category{}).
+			Where("id = ? AND ledger_id IN (?)", req.CategoryID, memberLedgers(s.DB, userID, true)).
+			Count(&count).Error; err != nil {
+			logrus.Errorf("Error getting category: %v", err)
+			return errors.New("failed to get category")
+		}
+		if count == 0 {
+			return errors.New("category not found")
+		}
+	case request.BulkActionChangeType:
+		if req.Type == "" {
+			return errors.New("type is required")
+		}
+	case request.BulkActionAddTags, request.BulkActionRemoveTags:
+		if len(normalizeTags(req.Tags)) == 0 {
+			return errors.New("tags are required")
+		}
+	case request.BulkActionShiftDate:
+		if req.Days == 0 {
+			return errors.New("days is required")
+		}
+	}
+	return nil
+}
+
+// selectBulkTransactions mengembalikan ID transaksi yang dipilih request di ledger yang bisa
+// diubah user. Filter tanpa ledger_id memilih transaksi yang dicatat user, sama seperti list.
+func (s *TransactionService) selectBulkTransactions(tx *gorm.DB, userID uint, req request.BulkTransactionRequest) ([]uint, error) {
+	query := tx.Model(&entity.Transaction{}).Where("ledger_id IN (?)", memberLedgers(tx, userID, true))
+	// tag milik masing-masing member, aksi tag hanya berlaku untuk transaksi yang dicatat user
+	ownOnly := req.Action == request.BulkActionAddTags || req.Action == request.BulkActionRemoveTags
+
+	if len(req.IDs) > 0 {
+		query = query.Where("id IN ?", req.IDs)
+	} else if req.Filter.LedgerID != 0 {
+		if _, err := resolveLedger(tx, userID, req.Filter.LedgerID, true); err != nil {
+			return nil, err
+		}
+		query = query.Where("ledger_id = ?", req.Filter.LedgerID)
+	} else {
+		ownOnly = true
+	}
+	if ownOnly {
+		query = query.Where("user_id = ?", userID)
+	}
+	if req.Filter != nil {
+		query = s.transactionUtil.BuildFilterQuery(query, *req.Filter)
+	}
+
+	var transactionIDs []uint
+	if err := query.Order("id").Limit(maxBulkTransactions+1).Pluck("id", &transactionIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(transactionIDs) > maxBulkTransactions {
+		return nil, ErrBulkSelectionTooLarge
+	}
+
+	if len(req.IDs) > 0 {
+		found := make(map[uint]bool, len(transactionIDs))
+		for _, id := range transactionIDs {
+			found[id] = true
+		}
+		var missing []string
+		for _, id := range req.IDs {
+			if !found[id] {
+				missing = append(missing, fmt.Sprint(id))
+				found[id] = true
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrBulkTransactionsNotFound, strings.Join(missing, ", "))
+		}
+	}
+
+	return transactionIDs, nil
+}
+
+func applyBulkAction(tx *gorm.DB, userID uint, req request.BulkTransactionRequest, transactionIDs []uint, result *response.BulkTransactionResponse) error {
+	now := time.Now()
+	transactions := tx.Model(&entity.Transaction{}).Where("id IN ?", transactionIDs)
+
+	switch req.Action {
+	case request.BulkActionRecategorize:
+		// kategori transaksi yang di-split ditentukan oleh split-nya
+		var splitTransactionIDs []uint
+		if err := tx.Model(&entity.TransactionSplit{}).
+			Where("transaction_id IN ?", transactionIDs).
+			Distinct().
+			Pluck("transaction_id", &splitTransactionIDs).Error; err != nil {
+			return err
+		}
+		result.Skipped = int64(len(splitTransactionIDs))
+		if len(splitTransactionIDs) > 0 {
+			transactions = transactions.Where("id NOT IN ?", splitTransactionIDs)
+		}
+		update := recategorizeTransactions(transactions, req.CategoryID)
+		result.Updated = update.RowsAffected
+		return update.Error
+
+	case request.BulkActionDelete:
+		// soft delete, transaksi masuk trash dan masih bisa di-restore
+		update := tx.Where("id IN ?", transactionIDs).Delete(&entity.Transaction{})
+		result.Updated = update.RowsAffected
+		return update.Error
+
+	case request.BulkActionChangeType:
+		update := transactions.UpdateColumns(map[string]interface{}{"type": req.Type, "updated_at": now})
+		result.Updated = update.RowsAffected
+		return update.Error
+
+	case request.BulkActionShiftDate:
+		update := transactions.UpdateColumns(map[string]interface{}{
+			"date":       gorm.Expr("date + make_interval(days => ?)", req.Days),
+			"updated_at": now,
+		})
+		result.Updated = update.RowsAffected
+		return update.Error
+
+	case request.BulkActionAddTags:
+		tags, err := resolveTags(tx, userID, req.Tags)
+		if err != nil {
+			return err
+		}
+		relations := make([]map[string]interface{}, 0, len(transactionIDs)*len(tags))
+		for _, transactionID := range transactionIDs {
+			for _, tag := range tags {
+				relations = append(relations, map[string]interface{}{"transaction_id": transactionID, "tag_id": tag.ID})
+			}
+		}
+		// relasi yang sudah ada dilewati
+		insert := tx.Table("transaction_tags").Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&relations, 1000)
+		result.Updated = insert.RowsAffected
+		return insert.Error
+
+	case request.BulkActionRemoveTags:
+		var tagIDs []uint
+		if err := tx.Model(&entity.Tag{}).
+			Where("user_id = ? AND name IN ?", userID, normalizeTags(req.Tags)).
+			Pluck("id", &tagIDs).Error; err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		remove := tx.Exec("DELETE FROM transaction_tags WHERE transaction_id IN ? AND tag_id IN ?", transactionIDs, tagIDs)
+		result.Updated = remove.RowsAffected
+		return remove.Error
+	}
+
+	return fmt.Errorf("unsupported bulk action: %s", req.Action)
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// jumlah baris contoh yang dikembalikan pada langkah mapping kolom CSV
+const importSampleRows = 5
+
+// PreviewImport memparsing file import dan menandai baris yang kemungkinan sudah pernah dicatat.
+// Belum ada data yang disimpan pada tahap ini.
+func (s *TransactionService) PreviewImport(userID uint, file io.Reader, req request.ImportPreviewRequest) (*response.ImportPreviewResponse, error) {
+	result := &response.ImportPreviewResponse{
+		Format: req.Format,
+		Rows:   []response.ImportPreviewRow{},
+	}
+
+	var parsed []utility.ParsedTransaction
+	var err error
+
+	switch req.Format {
+	case "csv":
+		// tanpa mapping, kembalikan header supaya user bisa memilih kolom
+		if req.DateColumn == "" || req.AmountColumn == "" {
+			result.Headers, result.SampleRows, err = utility.ReadCSVPreview(file, req.Delimiter, importSampleRows)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+
+		parsed, err = utility.ParseCSV(file, utility.CSVMapping{
+			DateColumn:        req.DateColumn,
+			AmountColumn:      req.AmountColumn,
+			DescriptionColumn: req.DescriptionColumn,
+			TypeColumn:        req.TypeColumn,
+			DateFormat:        req.DateFormat,
+			DecimalSeparator:  req.DecimalSeparator,
+			Delimiter:         req.Delimiter,
+		})
+	case "ofx":
+		parsed, err = utility.ParseOFX(file)
+	case "qif":
+		parsed, err = utility.ParseQIF(file)
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", req.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var dates []time.Time
+	for _, row := range parsed {
+		if row.Error == "" {
+			dates = append(dates, row.Date)
+		}
+	}
+
+	matcher, err := loadCategoryRuleMatcher(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findImportDuplicates(s.DB, userID, dates)
+	if err != nil {
+		logrus.Errorf("Error checking import duplicates: %v", err)
+		return nil, errors.New("failed to check duplicate transactions")
+	}
+
+	// baris pertama untuk setiap kombinasi, supaya baris kembar di file yang sama ikut ditandai
+	firstRows := map[string]int{}
+
+	for i, row := range parsed {
+		previewRow := response.ImportPreviewRow{
+			Index:       i,
+			Amount:      row.Amount,
+			Type:        row.Type,
+			Description: row.Description,
+			Error:       row.Error,
+		}
+
+		if row.Error != "" {
+			result.Invalid++
+		} else {
+			previewRow.Date = row.Date.Format("2006-01-02")
+			previewRow.CategoryID, _ = matcher.Match(row.Description, row.Amount, row.Type)
+			key := importDuplicateKey(row.Date, row.Amount, row.Description)
+			if transactionID, ok := existing[key]; ok {
+				previewRow.Duplicate = true
+				previewRow.DuplicateTransactionID = transactionID
+				result.Duplicates++
+			} else if first, ok := firstRows[key]; ok {
+				previewRow.Duplicate = true
+				previewRow.DuplicateOfIndex = &first
+				result.Duplicates++
+			} else {
+				firstRows[key] = i
+			}
+		}
+
+		result.Rows = append(result.Rows, previewRow)
+	}
+	result.TotalRows = len(result.Rows)
+
+	return result, nil
+}
+
+// CommitImport menyimpan baris yang dipilih user dalam satu transaksi DB. Baris yang tidak
+// valid atau duplikat dilewati dan dilaporkan per baris, error database membatalkan semuanya.
+func (s *TransactionService) CommitImport(userID uint, req request.ImportCommitRequest) (*response.ImportCommitResponse, error) {
+	account, err := s.getUserAccount(userID, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// baris import mengikuti mata uang account, tanpa account memakai base currency user
+	var currency string
+	if account != nil {
+		currency = account.Currency
+	} else if currency, err = transactionCurrency(s.DB, userID, nil); err != nil {
+		logrus.Errorf("Error getting user settings: %v", err)
+		return nil, errors.New("failed to import transactions")
+	}
+
+	matcher, err := loadCategoryRuleMatcher(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// kategori per baris: pilihan user, lalu category rule, lalu default
+	rowCategoryIDs := make([]uint, len(req.Rows))
+	categoryIDs := []uint{req.DefaultCategoryID}
+	for i, row := range req.Rows {
+		rowCategoryIDs[i] = row.CategoryID
+		if rowCategoryIDs[i] == 0 {
+			if categoryID, ok := matcher.Match(row.Description, row.Amount, row.Type); ok {
+				rowCategoryIDs[i] = categoryID
+			} else {
+				rowCategoryIDs[i] = req.DefaultCategoryID
+			}
+		}
+		categoryIDs = append(categoryIDs, rowCategoryIDs[i])
+	}
+
+	var ledgerCategories []entity.Category
+	if err := s.DB.Select("id", "ledger_id").
+		Where("id IN ? AND ledger_id IN (?)", categoryIDs, memberLedgers(s.DB, userID, true)).
+		Find(&ledgerCategories).Error; err != nil {
+		logrus.Errorf("Error getting import categories: %v", err)
+		return nil, errors.New("failed to get categories")
+	}
+
+	// transaksi hasil import masuk ke ledger kategorinya
+	categoryLedger := make(map[uint]uint, len(ledgerCategories))
+	validCategory := make(map[uint]bool, len(ledgerCategories))
+	for _, category := range ledgerCategories {
+		categoryLedger[category.ID] = category.LedgerID
+		validCategory[category.ID] = true
+	}
+	if req.DefaultCategoryID != 0 && !validCategory[req.DefaultCategoryID] {
+		return nil, errors.New("category not found")
+	}
+
+	result := &response.ImportCommitResponse{
+		Results: make([]response.ImportRowResult, len(req.Rows)),
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		dates := make([]time.Time, len(req.Rows))
+		for i, row := range req.Rows {
+			// tanggal tidak valid akan ditolak di loop berikutnya
+			dates[i], _ = time.Parse("2006-01-02", row.Date)
+		}
+
+		existing, err := s.findImportDuplicates(tx, userID, dates)
+		if err != nil {
+			return err
+		}
+
+		var transactions []entity.Transaction
+		var resultIndexes []int
+		// baris kembar di request yang sama menunjuk ke transaksi yang dibuat baris pertamanya
+		pending := map[string]int{}
+		duplicateOf := map[int]int{}
+
+		for i, row := range req.Rows {
+			result.Results[i] = response.ImportRowResult{Index: row.Index}
+
+			categoryID := rowCategoryIDs[i]
+
+			switch {
+			case dates[i].IsZero():
+				result.Results[i].Status = "failed"
+				result.Results[i].Error = "invalid date format"
+			case row.Amount <= 0:
+				result.Results[i].Status = "failed"
+				result.Results[i].Error = "amount must be greater than 0"
+			case row.Type != "income" && row.Type != "expense":
+				result.Results[i].Status = "failed"
+				result.Results[i].Error = "type must be income or expense"
+			case categoryID == 0:
+				result.Results[i].Status = "failed"
+				result.Results[i].Error = "category is required, no category rule matched"
+			case !validCategory[categoryID]:
+				result.Results[i].Status = "failed"
+				result.Results[i].Error = "category not found"
+			default:
+				key := importDuplicateKey(dates[i], row.Amount, row.Description)
+				if transactionID, ok := existing[key]; ok && !row.AllowDuplicate {
+					result.Results[i].Status = "duplicate"
+					result.Results[i].TransactionID = transactionID
+					continue
+				}
+				if position, ok := pending[key]; ok && !row.AllowDuplicate {
+					result.Results[i].Status = "duplicate"
+					duplicateOf[i] = position
+					continue
+				}
+				pending[key] = len(transactions)
+
+				transactions = append(transactions, entity.Transaction{
+					UserID:      userID,
+					LedgerID:    categoryLedger[categoryID],
+					CategoryID:  categoryID,
+					AccountID:   req.AccountID,
+					Amount:      row.Amount,
+					Currency:    currency,
+					Type:        row.Type,
+					Description: strings.TrimSpace(row.Description),
+					Date:        dates[i],
+				})
+				resultIndexes = append(resultIndexes, i)
+			}
+		}
+
+		if len(transactions) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(&transactions).Error; err != nil {
+			return err
+		}
+
+		for i, transaction := range transactions {
+			result.Results[resultIndexes[i]].Status = "created"
+			result.Results[resultIndexes[i]].TransactionID = transaction.ID
+		}
+		for i, position := range duplicateOf {
+			result.Results[i].TransactionID = transactions[position].ID
+		}
+
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("Error committing import: %v", err)
+		return nil, errors.New("failed to import transactions")
+	}
+
+	for _, rowResult := range result.Results {
+		switch rowResult.Status {
+		case "created":
+			result.Created++
+		case "duplicate":
+			result.Duplicates++
+		case "failed":
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
+
+// findImportDuplicates mengembalikan transaksi user di rentang tanggal import,
+// di-index dengan kombinasi tanggal, nominal dan deskripsi
+func (s *TransactionService) findImportDuplicates(db *gorm.DB, userID uint, dates []time.Time) (map[string]uint, error) {
+	duplicates := map[string]uint{}
+
+	var minDate, maxDate time.Time
+	for _, date := range dates {
+		if date.IsZero() {
+			continue
+		}
+		if minDate.IsZero() || date.Before(minDate) {
+			minDate = date
+		}
+		if date.After(maxDate) {
+			maxDate = date
+		}
+	}
+	if minDate.IsZero() {
+		return duplicates, nil
+	}
+
+	var existing []entity.Transaction
+	if err := db.Select("id", "date", "amount", "description").
+		Where("user_id = ? AND date >= ? AND date < ?", userID, minDate, maxDate.AddDate(0, 0, 1)).
+		Find(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	for _, transaction := range existing {
+		duplicates[importDuplicateKey(transaction.Date, transaction.Amount, transaction.Description)] = transaction.ID
+	}
+
+	return duplicates, nil
+}
+
+func importDuplicateKey(date time.Time, amount float64, description string) string {
+	return fmt.Sprintf("%s|%d|%s",
+		date.Format("2006-01-02"),
+		int64(math.Round(amount*100)),
+		strings.ToLower(strings.TrimSpace(description)))
+}
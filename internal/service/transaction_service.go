@@ -1,23 +1,25 @@
 package service
 
 import (
-	"bytes"
+	"context"
 	"errors"
-	"fmt"
+	"go-electroshop/internal/exchangerate"
 	"go-electroshop/internal/payload/entity"
 	"go-electroshop/internal/payload/request"
 	"go-electroshop/internal/payload/response"
 	"go-electroshop/internal/utility"
+	"io"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
-	"github.com/xuri/excelize/v2"
 	"gorm.io/gorm"
 )
 
 type TransactionService struct {
 	DB              *gorm.DB
+	RateProvider    exchangerate.Provider // nil berarti hanya kurs yang diinput user
 	transactionUtil *utility.TransactionUtil
 }
 
@@ -33,7 +35,10 @@ func (s *TransactionService) GetTransactionByUser(userID uint, filter request.Tr
 
 	var transactions []entity.Transaction
 
-	baseQuery := s.DB.Where("user_id = ?", userID)
+	baseQuery, err := transactionScope(s.DB, userID, filter.LedgerID)
+	if err != nil {
+		return nil, err
+	}
 
 	filteredQuery := s.transactionUtil.BuildFilterQuery(baseQuery, filter)
 
@@ -52,8 +57,12 @@ func (s *TransactionService) GetTransactionByUser(userID uint, filter request.Tr
 
 	// terapkan pagination
 	offset := (filter.Page - 1) * filter.Limit
-	if err := filteredQuery.Preload("Category").
-		Order("date DESC").
+	if err := preloadCreator(s.transactionUtil.ApplySort(filteredQuery, filter), filter.LedgerID).
+		Preload("Category").
+		Preload("Account").
+		Preload("Splits.Category").
+		Preload("Attachments").
+		Preload("Tags").
 		Offset(offset).
 		Limit(filter.Limit).
 		Find(&transactions).Error; err != nil {
@@ -64,17 +73,7 @@ func (s *TransactionService) GetTransactionByUser(userID uint, filter request.Tr
 	// transform ke response format
 	transactionResponses := make([]response.TransactionResponse, len(transactions))
 	for i, tx := range transactions {
-		transactionResponses[i] = response.TransactionResponse{
-			ID:          tx.ID,
-			CategoryID:  tx.CategoryID,
-			Category:    tx.Category.Name,
-			Amount:      tx.Amount,
-			Type:        tx.Type,
-			Description: tx.Description,
-			Date:        tx.Date,
-			CreatedAt:   tx.CreatedAt,
-			UpdatedAt:   tx.UpdatedAt,
-		}
+		transactionResponses[i] = *toTransactionResponse(tx, tx.Category, tx.Account)
 	}
 
 	return &response.TransactionListResponse{
@@ -90,49 +89,109 @@ func (s *TransactionService) GetTransactionByUser(userID uint, filter request.Tr
 }
 
 func (s *TransactionService) CreateTransaction(userID uint, req request.CreateTransactionRequest) (*response.TransactionResponse, error) {
-	// validasi category
+	if err := checkSplitAmounts(req.Amount, req.Splits); err != nil {
+		return nil, err
+	}
+
+	ledgerID, err := resolveLedger(s.DB, userID, req.LedgerID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	splits, splitCategories, err := s.buildTransactionSplits(ledgerID, req.Splits)
+	if err != nil {
+		return nil, err
+	}
+	if len(splits) > 0 {
+		req.CategoryID = splits[0].CategoryID
+	}
+
+	// tanpa category, pakai rule pertama yang cocok
+	if req.CategoryID == 0 {
+		matcher, err := loadCategoryRuleMatcher(s.DB, userID)
+		if err != nil {
+			return nil, err
+		}
+		categoryID, ok := matcher.Match(req.Description, req.Amount, req.Type)
+		if !ok {
+			return nil, errors.New("category is required, no category rule matched")
+		}
+		req.CategoryID = categoryID
+	}
+
+	// validasi category, harus kategori ledger yang sama
 	var category entity.Category
-	if err := s.DB.First(&category, req.CategoryID).Error; err != nil {
+	if err := s.DB.Where("id = ? AND ledger_id = ?", req.CategoryID, ledgerID).First(&category).Error; err != nil {
 		logrus.Errorf("category not found: %v", err)
 		return nil, errors.New("category not found")
 	}
 
-	date, err := time.Parse("2006-01-02", req.Date)
+	account, err := s.getUserAccount(userID, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := loadUserPreferences(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Error getting user settings: %v", err)
+		return nil, errors.New("failed to get user settings")
+	}
+
+	date, err := utility.ParseLocalDate(req.Date, preferences.Location)
 	if err != nil {
-		logrus.Errorf("invalid date format: %v", err)
 		return nil, errors.New("invalid date format")
 	}
 
+	currency, err := s.resolveTransactionCurrency(userID, req.Currency, account, preferences.BaseCurrency, date)
+	if err != nil {
+		return nil, err
+	}
+
 	transaction := entity.Transaction{
 		UserID:      userID,
+		LedgerID:    ledgerID,
 		CategoryID:  req.CategoryID,
+		AccountID:   req.AccountID,
 		Amount:      req.Amount,
+		Currency:    currency,
 		Type:        req.Type,
 		Description: req.Description,
 		Date:        date,
+		Splits:      splits,
 	}
 
-	if err := s.DB.Create(&transaction).Error; err != nil {
+	// split dan relasi tag ikut tersimpan dalam transaksi DB yang sama
+	if err := s.DB.Transaction(func(tx *gorm.DB) error {
+		tags, err := resolveTags(tx, userID, req.Tags)
+		if err != nil {
+			return err
+		}
+		transaction.Tags = tags
+
+		// tag sudah dibuat oleh resolveTags, cukup simpan relasinya
+		return tx.Omit("Tags.*").Create(&transaction).Error
+	}); err != nil {
 		logrus.Errorf("Error creating transaction: %v", err)
 		return nil, errors.New("failed to create transaction")
 	}
 
-	return &response.TransactionResponse{
-		ID:          transaction.ID,
-		CategoryID:  transaction.CategoryID,
-		Category:    category.Name,
-		Amount:      transaction.Amount,
-		Type:        transaction.Type,
-		Description: transaction.Description,
-		Date:        transaction.Date,
-		CreatedAt:   transaction.CreatedAt,
-		UpdatedAt:   transaction.UpdatedAt,
-	}, nil
+	// kategori split diisi setelah disimpan supaya GORM tidak ikut menyimpan category
+	for i := range transaction.Splits {
+		transaction.Splits[i].Category = splitCategories[transaction.Splits[i].CategoryID]
+	}
+
+	return toTransactionResponse(transaction, category, account), nil
 }
 
+// UpdateTransaction bisa dilakukan owner dan editor ledger transaksi. Account, mata uang dan tag
+// tetap mengikuti member yang mencatat transaksi.
 func (s *TransactionService) UpdateTransaction(userID uint, transactionID uint, req request.UpdateTransactionRequest) (*response.TransactionResponse, error) {
+	if err := checkSplitAmounts(req.Amount, req.Splits); err != nil {
+		return nil, err
+	}
+
 	var transaction entity.Transaction
-	if err := s.DB.Where("id = ? AND user_id = ?", transactionID, userID).
+	if err := s.DB.Where("id = ? AND ledger_id IN (?)", transactionID, memberLedgers(s.DB, userID, true)).
 		First(&transaction).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("transaction not found")
@@ -140,45 +199,267 @@ func (s *TransactionService) UpdateTransaction(userID uint, transactionID uint,
 		logrus.Errorf("Error getting transaction: %v", err)
 		return nil, errors.New("failed to get transaction")
 	}
+	creatorID := transaction.UserID
+
+	splits, splitCategories, err := s.buildTransactionSplits(transaction.LedgerID, req.Splits)
+	if err != nil {
+		return nil, err
+	}
+	if len(splits) > 0 {
+		req.CategoryID = splits[0].CategoryID
+	}
 
 	var category entity.Category
-	if err := s.DB.First(&category, req.CategoryID).Error; err != nil {
+	if err := s.DB.Where("id = ? AND ledger_id = ?", req.CategoryID, transaction.LedgerID).First(&category).Error; err != nil {
 		logrus.Errorf("Error category not found: %v", err)
 		return nil, errors.New("category not found")
 	}
 
-	date, err := time.Parse("2006-01-02", req.Date)
+	account, err := s.getUserAccount(creatorID, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := loadUserPreferences(s.DB, creatorID)
+	if err != nil {
+		logrus.Errorf("Error getting user settings: %v", err)
+		return nil, errors.New("failed to get user settings")
+	}
+
+	date, err := utility.ParseLocalDate(req.Date, preferences.Location)
 	if err != nil {
-		logrus.Errorf("Error invalid date format: %v", err)
 		return nil, errors.New("invalid date format")
 	}
 
+	currency, err := s.resolveTransactionCurrency(creatorID, req.Currency, account, preferences.BaseCurrency, date)
+	if err != nil {
+		return nil, err
+	}
+
 	transaction.CategoryID = req.CategoryID
+	transaction.AccountID = req.AccountID
 	transaction.Amount = req.Amount
+	transaction.Currency = currency
 	transaction.Type = req.Type
 	transaction.Description = req.Description
 	transaction.Date = date
+	transaction.Splits = splits
+
+	// split dan tag lama selalu diganti dengan isi request
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transaction_id = ?", transaction.ID).Delete(&entity.TransactionSplit{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(&transaction).Error; err != nil {
+			return err
+		}
 
-	if err := s.DB.Save(&transaction).Error; err != nil {
+		tags, err := resolveTags(tx, creatorID, req.Tags)
+		if err != nil {
+			return err
+		}
+		if err := replaceTransactionTags(tx, transaction.ID, tags); err != nil {
+			return err
+		}
+		transaction.Tags = tags
+
+		return nil
+	})
+	if err != nil {
 		logrus.Errorf("Error update transaction: %v", err)
 		return nil, errors.New("failed to update transaction")
 	}
 
-	return &response.TransactionResponse{
-		ID:          transaction.ID,
-		CategoryID:  transaction.CategoryID,
-		Category:    category.Name,
-		Amount:      transaction.Amount,
-		Type:        transaction.Type,
-		Description: transaction.Description,
-		Date:        transaction.Date,
-		CreatedAt:   transaction.CreatedAt,
-		UpdatedAt:   transaction.UpdatedAt,
-	}, nil
+	// kategori split diisi setelah disimpan supaya GORM tidak ikut menyimpan category
+	for i := range transaction.Splits {
+		transaction.Splits[i].Category = splitCategories[transaction.Splits[i].CategoryID]
+	}
+
+	return toTransactionResponse(transaction, category, account), nil
 }
 
+// getUserAccount memvalidasi account milik user, nil jika transaksi tidak terkait account
+func (s *TransactionService) getUserAccount(userID uint, accountID *uint) (*entity.Account, error) {
+	if accountID == nil {
+		return nil, nil
+	}
+
+	var account entity.Account
+	if err := s.DB.Where("id = ? AND user_id = ?", *accountID, userID).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("account not found")
+		}
+		logrus.Errorf("Error getting account: %v", err)
+		return nil, errors.New("failed to get account")
+	}
+
+	return &account, nil
+}
+
+// transactionCurrency mengembalikan mata uang untuk transaksi yang dibuat sistem (import dan
+// recurring): mata uang account jika ada, selain itu base currency user
+func transactionCurrency(db *gorm.DB, userID uint, accountID *uint) (string, error) {
+	if accountID == nil {
+		preferences, err := loadUserPreferences(db, userID)
+		if err != nil {
+			return "", err
+		}
+		return preferences.BaseCurrency, nil
+	}
+
+	var accounts []entity.Account
+	if err := db.Select("currency").Where("id = ? AND user_id = ?", *accountID, userID).Limit(1).Find(&accounts).Error; err != nil {
+		return "", err
+	}
+	if len(accounts) == 0 || accounts[0].Currency == "" {
+		return utility.DefaultCurrency, nil
+	}
+	return accounts[0].Currency, nil
+}
+
+// resolveTransactionCurrency menentukan mata uang transaksi: mata uang account jika transaksi
+// terkait account, selain itu currency dari request atau base currency user. Kurs ke base
+// currency harus tersedia supaya transaksi ikut terhitung di ringkasan dan dashboard.
+func (s *TransactionService) resolveTransactionCurrency(userID uint, requested string, account *entity.Account, baseCurrency string, date time.Time) (string, error) {
+	currency := baseCurrency
+	if requested != "" {
+		normalized, err := utility.NormalizeCurrency(requested)
+		if err != nil {
+			return "", err
+		}
+		currency = normalized
+	}
+
+	if account != nil {
+		if requested != "" && currency != account.Currency {
+			return "", errors.New("currency must match the account currency")
+		}
+		currency = account.Currency
+	}
+
+	if currency != baseCurrency {
+		if err := ensureExchangeRate(s.DB, s.RateProvider, userID, currency, baseCurrency, date); err != nil {
+			return "", err
+		}
+	}
+
+	return currency, nil
+}
+
+func replaceTransactionTags(tx *gorm.DB, transactionID uint, tags []entity.Tag) error {
+	if err := tx.Exec("DELETE FROM transaction_tags WHERE transaction_id = ?", transactionID).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	relations := make([]map[string]interface{}, len(tags))
+	for i, tag := range tags {
+		relations[i] = map[string]interface{}{"transaction_id": transactionID, "tag_id": tag.ID}
+	}
+	return tx.Table("transaction_tags").Create(&relations).Error
+}
+
+// checkSplitAmounts memvalidasi split dari request: minimal 2 baris dan total sama dengan
+// amount transaksi. Split kosong berarti transaksi tidak di-split.
+func checkSplitAmounts(amount float64, reqSplits []request.TransactionSplitRequest) error {
+	if len(reqSplits) == 0 {
+		return nil
+	}
+	if len(reqSplits) < 2 {
+		return errors.New("split transaction must have at least 2 lines")
+	}
+
+	var total float64
+	for _, split := range reqSplits {
+		total += split.Amount
+	}
+	// bandingkan dalam sen supaya tidak terpengaruh pembulatan float
+	if math.Round(total*100) != math.Round(amount*100) {
+		return errors.New("split amounts must add up to the transaction amount")
+	}
+	return nil
+}
+
+// buildTransactionSplits memastikan kategori split ada di ledger transaksi, amount-nya sudah
+// dicek checkSplitAmounts. Nil jika transaksi tidak di-split.
+func (s *TransactionService) buildTransactionSplits(ledgerID uint, reqSplits []request.TransactionSplitRequest) ([]entity.TransactionSplit, map[uint]entity.Category, error) {
+	if len(reqSplits) == 0 {
+		return nil, nil, nil
+	}
+
+	categoryIDs := make([]uint, len(reqSplits))
+	for i, split := range reqSplits {
+		categoryIDs[i] = split.CategoryID
+	}
+
+	var categories []entity.Category
+	if err := s.DB.Where("id IN ? AND ledger_id = ?", categoryIDs, ledgerID).Find(&categories).Error; err != nil {
+		logrus.Errorf("Error getting split categories: %v", err)
+		return nil, nil, errors.New("failed to get categories")
+	}
+	categoryByID := make(map[uint]entity.Category, len(categories))
+	for _, category := range categories {
+		categoryByID[category.ID] = category
+	}
+
+	splits := make([]entity.TransactionSplit, len(reqSplits))
+	for i, split := range reqSplits {
+		if _, ok := categoryByID[split.CategoryID]; !ok {
+			return nil, nil, errors.New("category not found")
+		}
+		splits[i] = entity.TransactionSplit{
+			CategoryID:  split.CategoryID,
+			Amount:      split.Amount,
+			Description: strings.TrimSpace(split.Description),
+		}
+	}
+
+	return splits, categoryByID, nil
+}
+
+func toTransactionResponse(transaction entity.Transaction, category entity.Category, account *entity.Account) *response.TransactionResponse {
+	transactionResponse := &response.TransactionResponse{
+		ID:            transaction.ID,
+		LedgerID:      transaction.LedgerID,
+		CreatedBy:     transaction.UserID,
+		CreatedByName: transaction.User.Name,
+		CategoryID:    transaction.CategoryID,
+		Category:      category.Name,
+		AccountID:     transaction.AccountID,
+		Amount:        transaction.Amount,
+		Currency:      transaction.Currency,
+		Type:          transaction.Type,
+		Description:   transaction.Description,
+		Date:          transaction.Date,
+		CreatedAt:     transaction.CreatedAt,
+		UpdatedAt:     transaction.UpdatedAt,
+	}
+	if account != nil {
+		transactionResponse.Account = account.Name
+	}
+	for _, split := range transaction.Splits {
+		transactionResponse.Splits = append(transactionResponse.Splits, response.TransactionSplitResponse{
+			ID:          split.ID,
+			CategoryID:  split.CategoryID,
+			Category:    split.Category.Name,
+			Amount:      split.Amount,
+			Description: split.Description,
+		})
+	}
+	for _, tag := range transaction.Tags {
+		transactionResponse.Tags = append(transactionResponse.Tags, tag.Name)
+	}
+	for _, attachment := range transaction.Attachments {
+		transactionResponse.Attachments = append(transactionResponse.Attachments, toAttachmentResponse(attachment))
+	}
+	return transactionResponse
+}
+
+// DeleteTransaction bisa dilakukan owner dan editor ledger transaksi
 func (s *TransactionService) DeleteTransaction(userID uint, transactionID uint) error {
-	result := s.DB.Where("id = ? AND user_id = ?", transactionID, userID).Delete(&entity.Transaction{})
+	result := s.DB.Where("id = ? AND ledger_id IN (?)", transactionID, memberLedgers(s.DB, userID, true)).Delete(&entity.Transaction{})
 	if result.Error != nil {
 		logrus.Errorf("Error to delete transaction: %v", result.Error)
 		return errors.New("failed to delete transaction")
@@ -191,82 +472,99 @@ func (s *TransactionService) DeleteTransaction(userID uint, transactionID uint)
 	return nil
 }
 
-func (s *TransactionService) ExportTransactionsExcel(userID uint, filter request.TransactionFilter) (*bytes.Buffer, error) {
-	transactions, err := s.GetTransactionByUser(userID, filter)
+// jumlah transaksi yang dimuat per query saat export
+const transactionExportBatchSize = 500
+
+// ExportTransactions menulis semua transaksi hasil filter ke w dalam format csv, xlsx atau json.
+// Transaksi dimuat per batch dengan keyset pagination (date, id) sehingga memori tetap kecil
+// berapapun jumlah transaksinya. Error sebelum ada data yang ditulis ke w masih bisa dikirim
+// ke client sebagai response biasa.
+func (s *TransactionService) ExportTransactions(ctx context.Context, userID uint, filter request.TransactionExportFilter, w io.Writer) error {
+	db := s.DB.WithContext(ctx)
+	baseQuery, err := transactionScope(db, userID, filter.LedgerID)
 	if err != nil {
-		logrus.Errorf("Error getting transactions: %v", err)
-		return nil, err
+		return err
+	}
+	filteredQuery := func() *gorm.DB {
+		return s.transactionUtil.BuildFilterQuery(baseQuery.Session(&gorm.Session{}), filter.TransactionFilter)
 	}
 
-	f := excelize.NewFile()
-
-	// Buat sheet baru
-	sheet := "Transactions"
-	index, err := f.NewSheet(sheet)
+	summary, err := s.transactionUtil.CalculateTransactionSummary(filteredQuery(), filter.TransactionFilter)
 	if err != nil {
-		logrus.Errorf("Error creating sheet: %v", err)
-		return nil, err
+		logrus.Errorf("Failed to calculate transaction summary: %v", err)
+		return errors.New("failed to export transactions")
 	}
-	f.SetActiveSheet(index)
 
-	// Set header
-	headers := []string{"Date", "Type", "Category", "Amount", "Description"}
-	for i, header := range headers {
-		cell := fmt.Sprintf("%s1", string(rune('A'+i)))
-		if err := f.SetCellValue(sheet, cell, header); err != nil {
-			logrus.Errorf("Error setting header: %v", err)
-			return nil, err
-		}
+	writer, err := utility.NewTransactionExportWriter(filter.Format, w)
+	if err != nil {
+		logrus.Errorf("Error creating export writer: %v", err)
+		return errors.New("failed to export transactions")
 	}
-
-	// Isi data
-	for i, tx := range transactions.Transactions {
-		row := i + 2
-		if err := f.SetCellValue(sheet, fmt.Sprintf("A%d", row), tx.Date.Format("2006-01-02")); err != nil {
-			return nil, err
-		}
-		if err := f.SetCellValue(sheet, fmt.Sprintf("B%d", row), tx.Type); err != nil {
-			return nil, err
+	// file sementara xlsx tetap dibersihkan jika export gagal sebelum Close
+	defer writer.Abort()
+
+	var last *entity.Transaction
+	for {
+		query := filteredQuery()
+		if last != nil {
+			query = query.Where("date < ? OR (date = ? AND id < ?)", last.Date, last.Date, last.ID)
 		}
-		if err := f.SetCellValue(sheet, fmt.Sprintf("C%d", row), tx.Category); err != nil {
-			return nil, err
+
+		var transactions []entity.Transaction
+		if err := preloadCreator(query, filter.LedgerID).
+			Preload("Category").
+			Preload("Account").
+			Preload("Splits.Category").
+			Preload("Attachments").
+			Preload("Tags").
+			Order("date DESC, id DESC").
+			Limit(transactionExportBatchSize).
+			Find(&transactions).Error; err != nil {
+			logrus.Errorf("Failed to get transactions for export: %v", err)
+			return errors.New("failed to export transactions")
 		}
-		if err := f.SetCellValue(sheet, fmt.Sprintf("D%d", row), tx.Amount); err != nil {
-			return nil, err
+
+		for _, tx := range transactions {
+			if err := writer.Write(*toTransactionResponse(tx, tx.Category, tx.Account)); err != nil {
+				logrus.Errorf("Error writing transaction export: %v", err)
+				return errors.New("failed to export transactions")
+			}
 		}
-		if err := f.SetCellValue(sheet, fmt.Sprintf("E%d", row), tx.Description); err != nil {
-			return nil, err
+
+		if len(transactions) < transactionExportBatchSize {
+			break
 		}
+		last = &transactions[len(transactions)-1]
 	}
 
-	// Tambah summary
-	summaryRow := len(transactions.Transactions) + 4
-	f.SetCellValue(sheet, fmt.Sprintf("A%d", summaryRow), "Summary")
-	f.SetCellValue(sheet, fmt.Sprintf("B%d", summaryRow), "Total Pemasukan")
-	f.SetCellValue(sheet, fmt.Sprintf("C%d", summaryRow), transactions.Summary.TotalIncome)
-	f.SetCellValue(sheet, fmt.Sprintf("B%d", summaryRow+1), "Total Pengeluaran")
-	f.SetCellValue(sheet, fmt.Sprintf("C%d", summaryRow+1), transactions.Summary.TotalExpense)
-	f.SetCellValue(sheet, fmt.Sprintf("B%d", summaryRow+2), "Saldo")
-	f.SetCellValue(sheet, fmt.Sprintf("C%d", summaryRow+2), transactions.Summary.Balance)
-
-	// Styling
-	if style, err := f.NewStyle(&excelize.Style{
-		NumFmt: 44, // Format currency
-	}); err == nil {
-		// Set style untuk kolom amount dan summary
-		for i := 2; i <= len(transactions.Transactions)+1; i++ {
-			f.SetCellStyle(sheet, fmt.Sprintf("D%d", i), fmt.Sprintf("D%d", i), style)
-		}
-		f.SetCellStyle(sheet, fmt.Sprintf("C%d", summaryRow), fmt.Sprintf("C%d", summaryRow+2), style)
+	if err := writer.Close(*summary); err != nil {
+		logrus.Errorf("Error finishing transaction export: %v", err)
+		return errors.New("failed to export transactions")
 	}
 
-	// Save ke buffer
-	buffer := new(bytes.Buffer)
-	_, err = f.WriteTo(buffer)
+	return nil
+}
+
+// transactionScope membatasi query ke transaksi semua member satu ledger jika ledgerID diisi,
+// selain itu ke transaksi yang dicatat user di semua ledger
+func transactionScope(db *gorm.DB, userID, ledgerID uint) (*gorm.DB, error) {
+	if ledgerID == 0 {
+		return db.Where("user_id = ?", userID), nil
+	}
+
+	ledgerID, err := resolveLedger(db, userID, ledgerID, false)
 	if err != nil {
-		logrus.Errorf("Error writing to buffer: %v", err)
 		return nil, err
 	}
+	return db.Where("ledger_id = ?", ledgerID), nil
+}
 
-	return buffer, nil
+// preloadCreator memuat nama member yang mencatat transaksi pada list transaksi ledger
+func preloadCreator(query *gorm.DB, ledgerID uint) *gorm.DB {
+	if ledgerID == 0 {
+		return query
+	}
+	return query.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name")
+	})
 }
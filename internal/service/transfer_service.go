@@ -0,0 +1,151 @@
+package service
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"math"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TransferService struct {
+	DB *gorm.DB
+}
+
+func NewTransferService(db *gorm.DB) *TransferService {
+	return &TransferService{DB: db}
+}
+
+func (s *TransferService) GetTransfers(userID uint, filter request.TransferFilter) (*response.TransferListResponse, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+
+	query := s.DB.Model(&entity.Transfer{}).Where("user_id = ?", userID)
+	if filter.AccountID != 0 {
+		query = query.Where("from_account_id = ? OR to_account_id = ?", filter.AccountID, filter.AccountID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logrus.Errorf("Error counting transfers: %v", err)
+		return nil, errors.New("failed to get transfers")
+	}
+
+	var transfers []entity.Transfer
+	if err := query.Preload("FromAccount", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("ToAccount", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("date DESC, id DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&transfers).Error; err != nil {
+		logrus.Errorf("Error getting transfers: %v", err)
+		return nil, errors.New("failed to get transfers")
+	}
+
+	transferResponses := make([]response.TransferResponse, len(transfers))
+	for i, transfer := range transfers {
+		transferResponses[i] = toTransferResponse(transfer)
+	}
+
+	return &response.TransferListResponse{
+		Transfers: transferResponses,
+		Pagination: response.Pagination{
+			CurrentPage: filter.Page,
+			TotalPage:   int(math.Ceil(float64(total) / float64(filter.Limit))),
+			TotalItems:  total,
+			ItemPerPage: filter.Limit,
+		},
+	}, nil
+}
+
+func (s *TransferService) CreateTransfer(userID uint, req request.TransferRequest) (*response.TransferResponse, error) {
+	if req.FromAccountID == req.ToAccountID {
+		return nil, errors.New("cannot transfer to the same account")
+	}
+
+	preferences, err := loadUserPreferences(s.DB, userID)
+	if err != nil {
+		logrus.Errorf("Error getting user settings: %v", err)
+		return nil, errors.New("failed to get user settings")
+	}
+
+	date, err := utility.ParseLocalDate(req.Date, preferences.Location)
+	if err != nil {
+		return nil, errors.New("invalid date format")
+	}
+
+	var accounts []entity.Account
+	if err := s.DB.Where("id IN ? AND user_id = ?", []uint{req.FromAccountID, req.ToAccountID}, userID).
+		Find(&accounts).Error; err != nil {
+		logrus.Errorf("Error getting transfer accounts: %v", err)
+		return nil, errors.New("failed to get account")
+	}
+
+	transfer := entity.Transfer{
+		UserID:        userID,
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		Description:   req.Description,
+		Date:          date,
+	}
+	for _, account := range accounts {
+		if account.ID == req.FromAccountID {
+			transfer.FromAccount = account
+		}
+		if account.ID == req.ToAccountID {
+			transfer.ToAccount = account
+		}
+	}
+	if transfer.FromAccount.ID == 0 || transfer.ToAccount.ID == 0 {
+		return nil, ErrAccountNotFound
+	}
+	// transfer hanya punya satu amount, jadi kedua account harus bermata uang sama
+	if transfer.FromAccount.Currency != transfer.ToAccount.Currency {
+		return nil, errors.New("cannot transfer between accounts with different currencies")
+	}
+
+	if err := s.DB.Omit("FromAccount", "ToAccount").Create(&transfer).Error; err != nil {
+		logrus.Errorf("Error creating transfer: %v", err)
+		return nil, errors.New("failed to create transfer")
+	}
+
+	transferResponse := toTransferResponse(transfer)
+	return &transferResponse, nil
+}
+
+func (s *TransferService) DeleteTransfer(userID, transferID uint) error {
+	result := s.DB.Where("id = ? AND user_id = ?", transferID, userID).Delete(&entity.Transfer{})
+	if result.Error != nil {
+		logrus.Errorf("Error deleting transfer: %v", result.Error)
+		return errors.New("failed to delete transfer")
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("transfer not found")
+	}
+
+	return nil
+}
+
+func toTransferResponse(transfer entity.Transfer) response.TransferResponse {
+	return response.TransferResponse{
+		ID:            transfer.ID,
+		FromAccountID: transfer.FromAccountID,
+		FromAccount:   transfer.FromAccount.Name,
+		ToAccountID:   transfer.ToAccountID,
+		ToAccount:     transfer.ToAccount.Name,
+		Amount:        transfer.Amount,
+		Description:   transfer.Description,
+		Date:          transfer.Date,
+		CreatedAt:     transfer.CreatedAt,
+	}
+}
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/storage"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTrashedTransactionNotFound = errors.New("transaction not found in trash")
+	ErrTrashedCategoryNotFound    = errors.New("category not found in trash")
+	ErrCategoryInTrash            = errors.New("transaction category is in trash, restore the category first")
+	ErrTransactionAccountDeleted  = errors.New("transaction account has been deleted")
+	ErrCategoryInUse              = errors.New("category is still in use")
+)
+
+// jumlah transaksi yang dihapus permanen per batch oleh retention job
+const trashPurgeBatchSize = 500
+
+// TrashService mengelola transaksi dan kategori yang sudah di-soft delete: melihat isi trash,
+// mengembalikan, dan menghapus permanen beserta lampirannya di storage
+type TrashService struct {
+	DB      *gorm.DB
+	Storage storage.Storage
+}
+
+func NewTrashService(db *gorm.DB, store storage.Storage) *TrashService {
+	return &TrashService{DB: db, Storage: store}
+}
+
+// trashRetentionDays adalah lama item disimpan di trash sebelum dihapus permanen
+func trashRetentionDays() int {
+	if value, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && value > 0 {
+		return value
+	}
+	return 30
+}
+
+func trashPurgeAt(deletedAt time.Time) time.Time {
+	return deletedAt.AddDate(0, 0, trashRetentionDays())
+}
+
+// unscopedPreload memuat relasi walaupun relasinya juga ada di trash
+func unscopedPreload(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// GetTrashedTransactions menampilkan transaksi terhapus di semua ledger tempat user menjadi
+// owner atau editor, siapapun yang mencatat atau menghapusnya
+func (s *TrashService) GetTrashedTransactions(userID uint, filter request.TrashFilter) (*response.TrashedTransactionListResponse, error) {
+	baseQuery := s.DB.Unscoped().Where("ledger_id IN (?) AND deleted_at IS NOT NULL", memberLedgers(s.DB, userID, true))
+
+	var total int64
+	if err := baseQuery.Model(&entity.Transaction{}).Count(&total).Error; err != nil {
+		logrus.Errorf("Failed to count trashed transactions: %v", err)
+		return nil, errors.New("failed to count trashed transactions")
+	}
+
+	var transactions []entity.Transaction
+	offset := (filter.Page - 1) * filter.Limit
+	if err := baseQuery.Preload("Category", unscopedPreload).
+		Preload("Account", unscopedPreload).
+		Preload("Splits.Category", unscopedPreload).
+		Preload("Attachments").
+		Preload("Tags").
+		Order("deleted_at DESC").
+		Offset(offset).
+		Limit(filter.Limit).
+		Find(&transactions).Error; err != nil {
+		logrus.Errorf("Failed to get trashed transactions: %v", err)
+		return nil, errors.New("failed to get trashed transactions")
+	}
+
+	trashed := make([]response.TrashedTransactionResponse, len(transactions))
+	for i, tx := range transactions {
+		trashed[i] = response.TrashedTransactionResponse{
+			TransactionResponse: *toTransactionResponse(tx, tx.Category, tx.Account),
+			DeletedAt:           tx.DeletedAt.Time,
+			PurgeAt:             trashPurgeAt(tx.DeletedAt.Time),
+		}
+	}
+
+	return &response.TrashedTransactionListResponse{
+		Transactions: trashed,
+		Pagination: response.Pagination{
+			CurrentPage: filter.Page,
+			TotalPage:   int(math.Ceil(float64(total) / float64(filter.Limit))),
+			TotalItems:  total,
+			ItemPerPage: filter.Limit,
+		},
+	}, nil
+}
+
+// RestoreTransaction mengembalikan transaksi dari trash. Kategori (termasuk kategori split)
+// dan account transaksi harus masih aktif supaya transaksi tidak menunjuk data yang terhapus.
+// Account milik member yang mencatat transaksi, bukan milik user yang me-restore.
+func (s *TrashService) RestoreTransaction(userID, transactionID uint) error {
+	transaction, err := s.getTrashedTransaction(userID, transactionID)
+	if err != nil {
+		return err
+	}
+
+	categoryIDs := []uint{transaction.CategoryID}
+	for _, split := range transaction.Splits {
+		categoryIDs = append(categoryIDs, split.CategoryID)
+	}
+
+	var trashedCategories int64
+	if err := s.DB.Unscoped().Model(&entity.Category{}).
+		Where("id IN ? AND deleted_at IS NOT NULL", categoryIDs).
+		Count(&trashedCategories).Error; err != nil {
+		logrus.Errorf("Error checking transaction categories: %v", err)
+		return errors.New("failed to restore transaction")
+	}
+	if trashedCategories > 0 {
+		return ErrCategoryInTrash
+	}
+
+	if transaction.AccountID != nil {
+		var accounts int64
+		if err := s.DB.Model(&entity.Account{}).
+			Where("id = ? AND user_id = ?", *transaction.AccountID, transaction.UserID).
+			Count(&accounts).Error; err != nil {
+			logrus.Errorf("Error checking transaction account: %v", err)
+			return errors.New("failed to restore transaction")
+		}
+		if accounts == 0 {
+			return ErrTransactionAccountDeleted
+		}
+	}
+
+	if err := s.DB.Unscoped().Model(&entity.Transaction{}).
+		Where("id = ?", transaction.ID).
+		UpdateColumn("deleted_at", nil).Error; err != nil {
+		logrus.Errorf("Error restoring transaction: %v", err)
+		return errors.New("failed to restore transaction")
+	}
+
+	return nil
+}
+
+// PurgeTransaction menghapus permanen transaksi yang ada di trash
+func (s *TrashService) PurgeTransaction(ctx context.Context, userID, transactionID uint) error {
+	transaction, err := s.getTrashedTransaction(userID, transactionID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.purgeTransactions(ctx, []uint{transaction.ID}); err != nil {
+		logrus.Errorf("Error purging transaction: %v", err)
+		return errors.New("failed to purge transaction")
+	}
+
+	return nil
+}
+
+func (s *TrashService) GetTrashedCategories(userID uint) ([]response.TrashedCategoryResponse, error) {
+	var categories []entity.Category
+	if err := s.DB.Unscoped().
+		Where("ledger_id IN (?) AND deleted_at IS NOT NULL", memberLedgers(s.DB, userID, true)).
+		Order("deleted_at DESC").
+		Find(&categories).Error; err != nil {
+		logrus.Errorf("Failed to get trashed categories: %v", err)
+		return nil, errors.New("failed to get trashed categories")
+	}
+
+	trashed := make([]response.TrashedCategoryResponse, len(categories))
+	for i, category := range categories {
+		trashed[i] = response.TrashedCategoryResponse{
+			ID:        category.ID,
+			Name:      category.Name,
+			Color:     category.Color,
+			IconColor: category.IconColor,
+			DeletedAt: category.DeletedAt.Time,
+			PurgeAt:   trashPurgeAt(category.DeletedAt.Time),
+		}
+	}
+
+	return trashed, nil
+}
+
+func (s *TrashService) RestoreCategory(userID, categoryID uint) error {
+	category, err := s.getTrashedCategory(userID, categoryID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DB.Unscoped().Model(&entity.Category{}).
+		Where("id = ?", category.ID).
+		UpdateColumn("deleted_at", nil).Error; err != nil {
+		logrus.Errorf("Error restoring category: %v", err)
+		return errors.New("failed to restore category")
+	}
+
+	return nil
+}
+
+// PurgeCategory menghapus permanen kategori di trash. Kategori yang masih dipakai transaksi
+// (termasuk transaksi di trash), recurring transaction atau savings goal tidak bisa dihapus.
+func (s *TrashService) PurgeCategory(userID, categoryID uint) error {
+	category, err := s.getTrashedCategory(userID, categoryID)
+	if err != nil {
+		return err
+	}
+
+	usage, err := s.categoryUsage(category.ID)
+	if err != nil {
+		logrus.Errorf("Error checking category usage: %v", err)
+		return errors.New("failed to purge category")
+	}
+	if usage != "" {
+		return fmt.Errorf("%w by %s", ErrCategoryInUse, usage)
+	}
+
+	if err := s.purgeCategory(category); err != nil {
+		logrus.Errorf("Error purging category: %v", err)
+		return errors.New("failed to purge category")
+	}
+
+	return nil
+}
+
+// PurgeExpired dijalankan scheduler untuk menghapus permanen item yang sudah melewati masa
+// retention. Kategori yang masih dipakai dibiarkan di trash sampai tidak dipakai lagi.
+func (s *TrashService) PurgeExpired(ctx context.Context) error {
+	cutoff := time.Now().AddDate(0, 0, -trashRetentionDays())
+	db := s.DB.WithContext(ctx)
+
+	for {
+		var transactionIDs []uint
+		if err := db.Unscoped().Model(&entity.Transaction{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Order("id").
+			Limit(trashPurgeBatchSize).
+			Pluck("id", &transactionIDs).Error; err != nil {
+			return fmt.Errorf("failed to get expired transactions: %v", err)
+		}
+		if len(transactionIDs) == 0 {
+			break
+		}
+
+		if err := s.purgeTransactions(ctx, transactionIDs); err != nil {
+			return fmt.Errorf("failed to purge expired transactions: %v", err)
+		}
+		if len(transactionIDs) < trashPurgeBatchSize {
+			break
+		}
+	}
+
+	var categories []entity.Category
+	if err := db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Find(&categories).Error; err != nil {
+		return fmt.Errorf("failed to get expired categories: %v", err)
+	}
+
+	for i := range categories {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		usage, err := s.categoryUsage(categories[i].ID)
+		if err != nil {
+			return fmt.Errorf("failed to check category usage: %v", err)
+		}
+		if usage != "" {
+			continue
+		}
+
+		if err := s.purgeCategory(&categories[i]); err != nil {
+			logrus.Errorf("Failed to purge category %d: %v", categories[i].ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *TrashService) getTrashedTransaction(userID, transactionID uint) (*entity.Transaction, error) {
+	var transaction entity.Transaction
+	if err := s.DB.Unscoped().Preload("Splits").
+		Where("id = ? AND ledger_id IN (?) AND deleted_at IS NOT NULL", transactionID, memberLedgers(s.DB, userID, true)).
+		First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTrashedTransactionNotFound
+		}
+		logrus.Errorf("Error getting trashed transaction: %v", err)
+		return nil, errors.New("failed to get transaction")
+	}
+	return &transaction, nil
+}
+
+func (s *TrashService) getTrashedCategory(userID, categoryID uint) (*entity.Category, error) {
+	var category entity.Category
+	if err := s.DB.Unscoped().
+		Where("id = ? AND ledger_id IN (?) AND deleted_at IS NOT NULL", categoryID, memberLedgers(s.DB, userID, true)).
+		First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTrashedCategoryNotFound
+		}
+		logrus.Errorf("Error getting trashed category: %v", err)
+		return nil, errors.New("failed to get category")
+	}
+	return &category, nil
+}
+
+// purgeTransactions menghapus permanen transaksi beserta split, tag, lampiran dan insight-nya.
+// File lampiran dihapus dari storage setelah commit, kegagalannya hanya dicatat di log.
+func (s *TrashService) purgeTransactions(ctx context.Context, transactionIDs []uint) error {
+	db := s.DB.WithContext(ctx)
+
+	var attachments []entity.TransactionAttachment
+	if err := db.Unscoped().Where("transaction_id IN ?", transactionIDs).Find(&attachments).Error; err != nil {
+		return err
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transaction_id IN ?", transactionIDs).Delete(&entity.TransactionSplit{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM transaction_tags WHERE transaction_id IN ?", transactionIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("transaction_id IN ?", transactionIDs).Delete(&entity.TransactionAttachment{}).Error; err != nil {
+			return err
+		}
+		// occurrence tetap tercatat sebagai sudah diposting supaya tidak dibuat ulang
+		if err := tx.Model(&entity.RecurringOccurrence{}).
+			Where("transaction_id IN ?", transactionIDs).
+			Update("transaction_id", nil).Error; err != nil {
+			return err
+		}
+		// insight transaksi tidak biasa tidak berarti lagi tanpa transaksinya
+		if err := tx.Where("transaction_id IN ?", transactionIDs).Delete(&entity.Insight{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", transactionIDs).Delete(&entity.Transaction{}).Error
+	}); err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		if err := s.Storage.Delete(ctx, attachment.StorageKey); err != nil {
+			logrus.Errorf("Error removing attachment %s: %v", attachment.StorageKey, err)
+		}
+	}
+
+	return nil
+}
+
+// categoryUsage mengembalikan nama data yang masih memakai kategori, kosong jika tidak dipakai.
+// Transaksi di trash ikut dihitung karena masih bisa di-restore.
+func (s *TrashService) categoryUsage(categoryID uint) (string, error) {
+	usages := []struct {
+		name  string
+		query *gorm.DB
+	}{
+		{"transactions", s.DB.Unscoped().Model(&entity.Transaction{}).Where("category_id = ?", categoryID)},
+		{"transaction splits", s.DB.Model(&entity.TransactionSplit{}).Where("category_id = ?", categoryID)},
+		{"recurring transactions", s.DB.Unscoped().Model(&entity.RecurringTransaction{}).Where("category_id = ?", categoryID)},
+		{"savings goals", s.DB.Unscoped().Model(&entity.SavingsGoal{}).Where("category_id = ?", categoryID)},
+	}
+
+	for _, usage := range usages {
+		var count int64
+		if err := usage.query.Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return usage.name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// purgeCategory menghapus permanen kategori beserta budget dan category rule miliknya
+func (s *TrashService) purgeCategory(category *entity.Category) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", category.ID).Delete(&entity.Budget{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("category_id = ?", category.ID).Delete(&entity.CategoryRule{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(category).Error
+	})
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/utility"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+func (s *UserService) GetUserSettings(userID uint) (*response.UserSettingsResponse, error) {
+	var user entity.User
+	if err := s.DB.Select("id", "timezone", "locale", "base_currency").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		logrus.Errorf("Error getting user settings: %v", err)
+		return nil, errors.New("failed to get user settings")
+	}
+
+	return toUserSettingsResponse(user), nil
+}
+
+func (s *UserService) UpdateUserSettings(userID uint, req request.UserSettingsRequest) (*response.UserSettingsResponse, error) {
+	if err := utility.ValidateTimezone(req.Timezone); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"timezone": req.Timezone, "locale": req.Locale}
+	// base currency hanya mengubah mata uang laporan, transaksi lama tetap di mata uang aslinya
+	if req.BaseCurrency != "" {
+		baseCurrency, err := utility.NormalizeCurrency(req.BaseCurrency)
+		if err != nil {
+			return nil, err
+		}
+		updates["base_currency"] = baseCurrency
+	}
+
+	result := s.DB.Model(&entity.User{}).
+		Where("id = ?", userID).
+		Updates(updates)
+	if result.Error != nil {
+		logrus.Errorf("Error updating user settings: %v", result.Error)
+		return nil, errors.New("failed to update user settings")
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	return s.GetUserSettings(userID)
+}
+
+type userPreferences struct {
+	Location     *time.Location
+	Locale       string
+	BaseCurrency string
+}
+
+// loadUserPreferences mengembalikan timezone, locale dan base currency user, default UTC, "en" dan IDR
+func loadUserPreferences(db *gorm.DB, userID uint) (*userPreferences, error) {
+	var users []entity.User
+	if err := db.Select("timezone", "locale", "base_currency").Where("id = ?", userID).Limit(1).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	preferences := &userPreferences{Location: time.UTC, Locale: utility.DefaultLocale, BaseCurrency: utility.DefaultCurrency}
+	if len(users) > 0 {
+		preferences.Location = utility.LoadLocation(users[0].Timezone)
+		if users[0].Locale != "" {
+			preferences.Locale = users[0].Locale
+		}
+		if users[0].BaseCurrency != "" {
+			preferences.BaseCurrency = users[0].BaseCurrency
+		}
+	}
+
+	return preferences, nil
+}
+
+// userToday mengembalikan tanggal hari ini menurut timezone user, dipakai untuk batas bulan
+// dan tanggal default supaya transaksi jam 23:30 WIB tidak masuk ke hari/bulan berikutnya
+func userToday(db *gorm.DB, userID uint) (time.Time, error) {
+	preferences, err := loadUserPreferences(db, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return utility.LocalDate(time.Now(), preferences.Location), nil
+}
+
+func toUserSettingsResponse(user entity.User) *response.UserSettingsResponse {
+	settings := &response.UserSettingsResponse{Timezone: user.Timezone, Locale: user.Locale, BaseCurrency: user.BaseCurrency}
+	if settings.Timezone == "" {
+		settings.Timezone = utility.DefaultTimezone
+	}
+	if settings.Locale == "" {
+		settings.Locale = utility.DefaultLocale
+	}
+	if settings.BaseCurrency == "" {
+		settings.BaseCurrency = utility.DefaultCurrency
+	}
+	return settings
+}
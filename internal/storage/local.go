@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage menyimpan file di filesystem lokal di bawah BaseDir
+type LocalStorage struct {
+	BaseDir string
+}
+
+func NewLocalStorage(baseDir string) *LocalStorage {
+	return &LocalStorage{BaseDir: baseDir}
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// tulis ke file sementara dulu supaya pembaca tidak pernah melihat upload yang belum selesai
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path menerjemahkan key ke path di bawah BaseDir dan menolak key yang keluar dari BaseDir
+func (s *LocalStorage) path(key string) (string, error) {
+	base, err := filepath.Abs(s.BaseDir)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(base, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, base+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid storage key: %s", key)
+	}
+
+	return path, nil
+}
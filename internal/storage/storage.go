@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrNotFound dikembalikan Get jika tidak ada object untuk key tersebut
+var ErrNotFound = errors.New("object not found")
+
+// Storage menyimpan file upload dengan key yang dipisah garis miring. Implementasinya
+// harus aman dipakai secara concurrent.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewFromEnv membuat storage sesuai STORAGE_DRIVER ("local" atau "s3"). Local storage
+// menulis ke STORAGE_LOCAL_DIR (default ./uploads), storage S3 dikonfigurasi lewat
+// variabel S3_*.
+func NewFromEnv() (Storage, error) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = "./uploads"
+		}
+		return NewLocalStorage(dir), nil
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", driver)
+	}
+}
@@ -0,0 +1,72 @@
+package unit
+
+import (
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func accountRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name", "type", "opening_balance"})
+}
+
+func TestCreateTransfer(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transferService := service.NewTransferService(db)
+	userID := uint(1)
+	now := time.Now()
+
+	expectUserPreferences(mock, userID, "Asia/Jakarta")
+	mock.ExpectQuery("SELECT (.+) FROM `accounts` WHERE \\(id IN \\(\\?,\\?\\) AND user_id = \\?\\)").
+		WithArgs(uint(1), uint(2), userID).
+		WillReturnRows(accountRows().
+			AddRow(1, now, now, nil, userID, "BCA", "bank", 1000000.0).
+			AddRow(2, now, now, nil, userID, "GoPay", "ewallet", 0.0))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `transfers`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(1), uint(2), 250000.0, "Top up", time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := transferService.CreateTransfer(userID, request.TransferRequest{
+		FromAccountID: 1,
+		ToAccountID:   2,
+		Amount:        250000,
+		Description:   "Top up",
+		// 20:00 UTC sudah tanggal 29 di Jakarta
+		Date: "2025-01-28T20:00:00Z",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "BCA", result.FromAccount)
+	assert.Equal(t, "GoPay", result.ToAccount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTransfer_AccountOfAnotherUser(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transferService := service.NewTransferService(db)
+	userID := uint(1)
+	now := time.Now()
+
+	expectUserPreferences(mock, userID, "UTC")
+	mock.ExpectQuery("SELECT (.+) FROM `accounts` WHERE \\(id IN \\(\\?,\\?\\) AND user_id = \\?\\)").
+		WithArgs(uint(1), uint(9), userID).
+		WillReturnRows(accountRows().
+			AddRow(1, now, now, nil, userID, "BCA", "bank", 1000000.0))
+
+	result, err := transferService.CreateTransfer(userID, request.TransferRequest{
+		FromAccountID: 1,
+		ToAccountID:   9,
+		Amount:        250000,
+		Date:          "2025-01-29",
+	})
+
+	assert.ErrorIs(t, err, service.ErrAccountNotFound)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,84 @@
+package unit
+
+import (
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBudgets_SpentVsLimit(t *testing.T) {
+	db, mock := setupTestDB(t)
+	budgetService := service.NewBudgetService(db)
+	userID := uint(1)
+
+	expectUserPreferences(mock, userID, "Asia/Jakarta")
+	mock.ExpectQuery("SELECT budgets.id as budget_id, (.+) FROM `budgets` JOIN categories (.+) LEFT JOIN \\(SELECT (.+) FROM `transactions` LEFT JOIN transaction_splits (.+)\\) AS category_lines (.+) WHERE budgets.user_id = \\? GROUP BY (.+)").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), userID).
+		WillReturnRows(sqlmock.NewRows([]string{"budget_id", "category_id", "category_name", "budget_limit", "spent"}).
+			AddRow(1, 3, "food", 1000000.0, 1250000.0).
+			AddRow(2, 4, "transport", 500000.0, 125000.0))
+
+	result, err := budgetService.GetBudgets(userID)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Budgets, 2)
+
+	assert.Equal(t, float64(-250000), result.Budgets[0].Remaining)
+	assert.Equal(t, float64(125), result.Budgets[0].UtilizationPercentage)
+	assert.True(t, result.Budgets[0].Overspent)
+
+	assert.Equal(t, float64(375000), result.Budgets[1].Remaining)
+	assert.Equal(t, float64(25), result.Budgets[1].UtilizationPercentage)
+	assert.False(t, result.Budgets[1].Overspent)
+
+	assert.Equal(t, float64(1500000), result.TotalLimit)
+	assert.Equal(t, float64(1375000), result.TotalSpent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBudget(t *testing.T) {
+	db, mock := setupTestDB(t)
+	budgetService := service.NewBudgetService(db)
+	userID := uint(1)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT \\* FROM `budgets` WHERE id = \\? AND user_id = \\? ORDER BY `budgets`.`id` LIMIT \\?").
+		WithArgs(2, userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "category_id", "amount", "created_at", "updated_at"}).
+			AddRow(2, userID, 4, 500000.0, now, now))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `budgets` SET `user_id`=\\?,`category_id`=\\?,`amount`=\\?,`created_at`=\\?,`updated_at`=\\? WHERE `id` = \\?").
+		WithArgs(userID, 4, 750000.0, sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUserPreferences(mock, userID, "Asia/Jakarta")
+	mock.ExpectQuery("SELECT budgets.id as budget_id, (.+) FROM `budgets`").
+		WillReturnRows(sqlmock.NewRows([]string{"budget_id", "category_id", "category_name", "budget_limit", "spent"}).
+			AddRow(2, 4, "transport", 750000.0, 125000.0))
+
+	result, err := budgetService.UpdateBudget(2, userID, &request.UpdateBudgetRequest{Amount: 750000})
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(750000), result.Limit)
+	assert.Equal(t, float64(625000), result.Remaining)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBudget_NotFound(t *testing.T) {
+	db, mock := setupTestDB(t)
+	budgetService := service.NewBudgetService(db)
+
+	mock.ExpectQuery("SELECT \\* FROM `budgets` WHERE id = \\? AND user_id = \\?").
+		WithArgs(9, 1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	result, err := budgetService.UpdateBudget(9, 1, &request.UpdateBudgetRequest{Amount: 750000})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrBudgetNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,138 @@
+package unit
+
+import (
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func categoryRuleRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "category_id", "name",
+		"priority", "description_contains", "description_pattern", "min_amount", "max_amount", "type"})
+}
+
+func TestCategoryRuleMatcher(t *testing.T) {
+	minAmount := 1000000.0
+	matcher := utility.NewCategoryRuleMatcher([]entity.CategoryRule{
+		{Model: gorm.Model{ID: 1}, CategoryID: 10, Priority: 5, DescriptionContains: "gojek"},
+		{Model: gorm.Model{ID: 2}, CategoryID: 20, Priority: 1, DescriptionPattern: `(?i)^gojek\s+food`},
+		{Model: gorm.Model{ID: 3}, CategoryID: 30, Priority: 5, MinAmount: &minAmount, Type: "income"},
+		{Model: gorm.Model{ID: 4}, CategoryID: 40, Priority: 9, DescriptionPattern: "("},
+	})
+
+	categoryID, ok := matcher.Match("GOJEK FOOD Warteg", 35000, "expense")
+	assert.True(t, ok)
+	assert.Equal(t, uint(20), categoryID, "lower priority wins")
+
+	categoryID, ok = matcher.Match("Gojek ride", 20000, "expense")
+	assert.True(t, ok)
+	assert.Equal(t, uint(10), categoryID)
+
+	categoryID, ok = matcher.Match("Gaji", 10000000, "income")
+	assert.True(t, ok)
+	assert.Equal(t, uint(30), categoryID)
+
+	_, ok = matcher.Match("Gaji", 500000, "income")
+	assert.False(t, ok, "below min amount")
+
+	_, ok = matcher.Match("Bonus", 10000000, "expense")
+	assert.False(t, ok, "type does not match")
+}
+
+func TestCategoryRuleValidate(t *testing.T) {
+	minAmount, maxAmount := 500.0, 100.0
+
+	assert.Error(t, (&entity.CategoryRule{CategoryID: 1}).Validate())
+	assert.Error(t, (&entity.CategoryRule{CategoryID: 1, DescriptionPattern: "[a-"}).Validate())
+	assert.Error(t, (&entity.CategoryRule{CategoryID: 1, MinAmount: &minAmount, MaxAmount: &maxAmount}).Validate())
+	assert.NoError(t, (&entity.CategoryRule{CategoryID: 1, DescriptionContains: "indomaret"}).Validate())
+}
+
+func TestCreateTransaction_CategoryFromRule(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+	now := time.Now()
+
+	expectPersonalLedger(mock, userID, 4)
+	mock.ExpectQuery("SELECT (.+) FROM `category_rules` WHERE \\(user_id = \\? AND category_id IN \\(SELECT `id` FROM `categories` WHERE ledger_id IN \\(SELECT `ledger_id` FROM `ledger_members`").
+		WithArgs(userID, userID, "owner", "editor").
+		WillReturnRows(categoryRuleRows().
+			AddRow(1, now, now, nil, userID, 7, "Transport", 0, "pertamina", "", nil, nil, "expense"))
+	mock.ExpectQuery("SELECT (.+) FROM `categories` WHERE \\(id = \\? AND ledger_id = \\?\\)").
+		WithArgs(7, 4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(7, userID, "Transport"))
+	expectUserPreferences(mock, userID, "UTC")
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `transactions`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(4), uint(7), nil, 150000.0, "IDR", "expense", "SPBU Pertamina", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := transactionService.CreateTransaction(userID, request.CreateTransactionRequest{
+		Amount:      150000,
+		Type:        "expense",
+		Description: "SPBU Pertamina",
+		Date:        "2025-01-29",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), result.CategoryID)
+	assert.Equal(t, "Transport", result.Category)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTransaction_NoCategoryAndNoRule(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+
+	expectPersonalLedger(mock, 1, 4)
+	mock.ExpectQuery("SELECT (.+) FROM `category_rules`").
+		WillReturnRows(categoryRuleRows())
+
+	result, err := transactionService.CreateTransaction(1, request.CreateTransactionRequest{
+		Amount: 150000,
+		Type:   "expense",
+		Date:   "2025-01-29",
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApplyCategoryRules(t *testing.T) {
+	db, mock := setupTestDB(t)
+	categoryRuleService := service.NewCategoryRuleService(db)
+	userID := uint(1)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM `category_rules`").
+		WillReturnRows(categoryRuleRows().
+			AddRow(1, now, now, nil, userID, 7, "", 0, "grab", "", nil, nil, ""))
+	mock.ExpectQuery("SELECT `id`,`category_id`,`amount`,`type`,`description` FROM `transactions` WHERE \\(user_id = \\? AND ledger_id IN \\(SELECT `ledger_id` FROM `ledger_members` (.+)\\)\\) AND date >= \\?").
+		WithArgs(userID, userID, "owner", "editor", sqlmock.AnyArg(), 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id", "amount", "type", "description"}).
+			AddRow(1, 3, 25000.0, "expense", "Grab bike").
+			AddRow(2, 7, 30000.0, "expense", "GrabFood").
+			AddRow(3, 3, 50000.0, "expense", "Indomaret"))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `transactions` SET `category_id`=\\?,`ledger_id`=\\(SELECT `ledger_id` FROM `categories` WHERE id = \\? (.+)\\),`updated_at`=\\? WHERE \\(id IN \\(\\?\\) AND user_id = \\?\\)").
+		WithArgs(uint(7), uint(7), sqlmock.AnyArg(), uint(1), userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := categoryRuleService.ApplyCategoryRules(userID, request.ApplyCategoryRulesRequest{StartDate: "2025-01-01"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), result.Checked)
+	assert.Equal(t, int64(1), result.Updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
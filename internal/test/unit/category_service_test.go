@@ -2,6 +2,7 @@ package unit
 
 import (
 	"database/sql"
+	"go-electroshop/internal/payload/request"
 	"go-electroshop/internal/service"
 	"io"
 	"log"
@@ -58,53 +59,123 @@ func (suite *CategoryServiceTestSuite) TearDownTest() {
 	suite.sqlDB.Close()
 }
 
+const writableLedgersQuery = "SELECT `ledger_id` FROM `ledger_members` WHERE user_id = ? AND role IN (?,?)"
+
 func (suite *CategoryServiceTestSuite) TestGetCategories() {
 	userID := uint(1)
 	now := time.Now()
 
-	query := "SELECT * FROM `categories` WHERE user_id = ? AND `categories`.`deleted_at` IS NULL"
-	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "name", "user_id"}).
-		AddRow(1, now, now, nil, "food", userID).
-		AddRow(2, now, now, nil, "transport", userID)
+	// tanpa ledger_id, kategori personal ledger user
+	suite.mock.ExpectQuery("SELECT `id` FROM `ledgers` WHERE (owner_id = ? AND personal = ?) AND `ledgers`.`deleted_at` IS NULL LIMIT ?").
+		WithArgs(userID, true, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	suite.mock.ExpectQuery("SELECT count(*) FROM `transactions` WHERE ledger_id = ? AND `transactions`.`deleted_at` IS NULL").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	query := "SELECT * FROM `categories` WHERE ledger_id = ? AND `categories`.`deleted_at` IS NULL"
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "name", "user_id", "ledger_id"}).
+		AddRow(1, now, now, nil, "food", userID, 4).
+		AddRow(2, now, now, nil, "transport", userID, 4)
 
 	suite.mock.ExpectQuery(query).
-		WithArgs(userID).
+		WithArgs(4).
 		WillReturnRows(rows)
+	for categoryID, usage := range []int{3, 1} {
+		suite.mock.ExpectQuery("SELECT count(*) FROM `transactions` WHERE category_id = ? AND `transactions`.`deleted_at` IS NULL").
+			WithArgs(categoryID + 1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(usage))
+	}
 
-	categories, err := suite.service.GetCategories(userID)
+	categories, err := suite.service.GetCategories(userID, request.CategoryFilter{})
 
 	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
 	assert.Len(suite.T(), categories, 2)
 	if len(categories) > 0 {
 		assert.Equal(suite.T(), "food", categories[0].Name)
+		assert.Equal(suite.T(), float64(75), categories[0].UsagePercentage)
 		assert.Equal(suite.T(), "transport", categories[1].Name)
+		assert.Equal(suite.T(), uint(4), categories[1].LedgerID)
 	}
 }
 
+func (suite *CategoryServiceTestSuite) TestGetCategories_SharedLedger() {
+	userID := uint(2)
+	now := time.Now()
+
+	// kategori shared ledger bisa dilihat semua member, termasuk yang dibuat member lain
+	suite.mock.ExpectQuery("SELECT `ledger_members`.`role` FROM `ledger_members` JOIN ledgers ON ledgers.id = ledger_members.ledger_id AND ledgers.deleted_at IS NULL "+
+		"WHERE ledger_members.ledger_id = ? AND ledger_members.user_id = ? LIMIT ?").
+		WithArgs(7, userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("viewer"))
+	suite.mock.ExpectQuery("SELECT count(*) FROM `transactions` WHERE ledger_id = ? AND `transactions`.`deleted_at` IS NULL").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery("SELECT * FROM `categories` WHERE ledger_id = ? AND `categories`.`deleted_at` IS NULL").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "name", "user_id", "ledger_id"}).
+			AddRow(9, now, now, nil, "household", 1, 7))
+	suite.mock.ExpectQuery("SELECT count(*) FROM `transactions` WHERE category_id = ? AND `transactions`.`deleted_at` IS NULL").
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	categories, err := suite.service.GetCategories(userID, request.CategoryFilter{LedgerID: 7})
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+	assert.Len(suite.T(), categories, 1)
+	assert.Equal(suite.T(), uint(1), categories[0].UserID)
+	assert.Equal(suite.T(), uint(7), categories[0].LedgerID)
+}
+
+func (suite *CategoryServiceTestSuite) TestGetCategories_NotLedgerMember() {
+	suite.mock.ExpectQuery("SELECT `ledger_members`.`role` FROM `ledger_members` JOIN ledgers ON ledgers.id = ledger_members.ledger_id AND ledgers.deleted_at IS NULL "+
+		"WHERE ledger_members.ledger_id = ? AND ledger_members.user_id = ? LIMIT ?").
+		WithArgs(7, 3, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}))
+
+	categories, err := suite.service.GetCategories(3, request.CategoryFilter{LedgerID: 7})
+
+	assert.ErrorIs(suite.T(), err, service.ErrLedgerNotFound)
+	assert.Nil(suite.T(), categories)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
 func (suite *CategoryServiceTestSuite) TestCreateCategory() {
 	userID := uint(1)
 	name := "groceries"
 
-	// Check existing
-	checkQuery := "SELECT * FROM `categories` WHERE (LOWER(name) = ? AND user_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
+	suite.mock.ExpectQuery("SELECT `id` FROM `ledgers` WHERE (owner_id = ? AND personal = ?) AND `ledgers`.`deleted_at` IS NULL LIMIT ?").
+		WithArgs(userID, true, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+	// Check existing, nama unik per ledger
+	checkQuery := "SELECT * FROM `categories` WHERE (LOWER(name) = ? AND ledger_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
 	suite.mock.ExpectQuery(checkQuery).
-		WithArgs("groceries", userID, 1).
+		WithArgs("groceries", 4, 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	// Create
 	suite.mock.ExpectBegin()
-	createQuery := "INSERT INTO `categories` (`created_at`,`updated_at`,`deleted_at`,`user_id`,`name`) VALUES (?,?,?,?,?)"
+	createQuery := "INSERT INTO `categories` (`created_at`,`updated_at`,`deleted_at`,`user_id`,`ledger_id`,`name`,`color`,`icon_color`) VALUES (?,?,?,?,?,?,?,?)"
 	suite.mock.ExpectExec(createQuery).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, name).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(4), name, "bg-green-100", "text-green-500").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mock.ExpectCommit()
 
-	category, err := suite.service.CreateCategory(name, userID)
+	category, err := suite.service.CreateCategory(&request.CategoryRequest{
+		Name:      " Groceries ",
+		Color:     "bg-green-100",
+		IconColor: "text-green-500",
+	}, userID)
 
 	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
 	assert.NotNil(suite.T(), category)
 	if category != nil {
 		assert.Equal(suite.T(), name, category.Name)
+		assert.Equal(suite.T(), uint(4), category.LedgerID)
 	}
 }
 
@@ -115,31 +186,39 @@ func (suite *CategoryServiceTestSuite) TestUpdateCategory() {
 	newName := "updated food"
 	now := time.Now()
 
-	// Get existing category
-	getQuery := "SELECT * FROM `categories` WHERE (id = ? AND user_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
-	getRows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "name", "user_id"}).
-		AddRow(categoryID, now, now, nil, oldName, userID)
+	// Get existing category, hanya owner dan editor ledger yang bisa mengubah
+	getQuery := "SELECT * FROM `categories` WHERE (id = ? AND ledger_id IN (" + writableLedgersQuery + ")) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
+	getRows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "ledger_id", "name", "color", "icon_color"}).
+		AddRow(categoryID, now, now, nil, userID, 4, oldName, "bg-blue-100", "text-blue-500")
 	suite.mock.ExpectQuery(getQuery).
-		WithArgs(categoryID, userID, 1).
+		WithArgs(categoryID, userID, "owner", "editor", 1).
 		WillReturnRows(getRows)
 
 	// Check duplicate name
-	checkQuery := "SELECT * FROM `categories` WHERE (LOWER(name) = ? AND user_id = ? AND id != ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
+	checkQuery := "SELECT * FROM `categories` WHERE (LOWER(name) = ? AND ledger_id = ? AND id != ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
 	suite.mock.ExpectQuery(checkQuery).
-		WithArgs(newName, userID, categoryID, 1).
+		WithArgs(newName, 4, categoryID, 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	// Update
 	suite.mock.ExpectBegin()
-	updateQuery := "UPDATE `categories` SET `created_at`=?,`updated_at`=?,`deleted_at`=?,`user_id`=?,`name`=? WHERE `categories`.`deleted_at` IS NULL AND `id` = ?"
+	updateQuery := "UPDATE `categories` SET `created_at`=?,`updated_at`=?,`deleted_at`=?,`user_id`=?,`ledger_id`=?,`name`=?,`color`=?,`icon_color`=? WHERE `categories`.`deleted_at` IS NULL AND `id` = ?"
 	suite.mock.ExpectExec(updateQuery).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, newName, categoryID).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(4), newName, "bg-blue-100", "text-blue-500", categoryID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mock.ExpectCommit()
 
-	result, err := suite.service.UpdateCategory(categoryID, userID, newName)
+	suite.mock.ExpectQuery("SELECT count(*) FROM `transactions` WHERE ledger_id = ? AND `transactions`.`deleted_at` IS NULL").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery("SELECT count(*) FROM `transactions` WHERE category_id = ? AND `transactions`.`deleted_at` IS NULL").
+		WithArgs(categoryID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	result, err := suite.service.UpdateCategory(categoryID, userID, &request.UpdateCategoryRequest{Name: newName})
 
 	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
 	assert.NotNil(suite.T(), result)
 	if result != nil {
 		assert.Equal(suite.T(), newName, result.Name)
@@ -152,14 +231,15 @@ func (suite *CategoryServiceTestSuite) TestDeleteCategory() {
 
 	// Mock soft delete
 	suite.mock.ExpectBegin()
-	deleteQuery := "UPDATE `categories` SET `deleted_at`=? WHERE (id = ? AND user_id = ?) AND `categories`.`deleted_at` IS NULL"
+	deleteQuery := "UPDATE `categories` SET `deleted_at`=? WHERE (id = ? AND ledger_id IN (" + writableLedgersQuery + ")) AND `categories`.`deleted_at` IS NULL"
 	suite.mock.ExpectExec(deleteQuery).
-		WithArgs(sqlmock.AnyArg(), categoryID, userID).
+		WithArgs(sqlmock.AnyArg(), categoryID, userID, "owner", "editor").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mock.ExpectCommit()
 
 	err := suite.service.DeleteCategory(categoryID, userID)
 	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
 }
 
 func TestCategoryServiceSuite(t *testing.T) {
@@ -0,0 +1,100 @@
+package unit
+
+import (
+	"fmt"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecastBalances(t *testing.T) {
+	// rata-rata 1 juta, stddev sampel 1 juta
+	points := utility.ForecastBalances(10000000, []float64{0, 1000000, 2000000}, []float64{0, -500000, 0, 0})
+
+	assert.Len(t, points, 4)
+	assert.Equal(t, float64(11000000), points[0].Balance)
+	assert.Equal(t, float64(11500000), points[1].Balance)
+	assert.Equal(t, float64(13500000), points[3].Balance)
+	assert.Equal(t, float64(9720000), points[0].Lower)
+	assert.Equal(t, float64(12280000), points[0].Upper)
+	// band melebar dengan akar jumlah bulan
+	assert.Equal(t, float64(13500000+2560000), points[3].Upper)
+}
+
+func TestForecastBalances_NoHistory(t *testing.T) {
+	points := utility.ForecastBalances(500000, nil, []float64{100000, 100000, 100000})
+
+	assert.Equal(t, float64(800000), points[2].Balance)
+	assert.Equal(t, points[2].Balance, points[2].Lower)
+	assert.Equal(t, points[2].Balance, points[2].Upper)
+}
+
+func TestGetCashFlowForecast(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewDashboardService(db)
+	userID := uint(1)
+	now := time.Now().UTC()
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(CASE WHEN type = 'income' THEN transactions.amount ELSE -transactions.amount END \\* (.+)\\), 0\\) \\+ \\(SELECT COALESCE\\(SUM\\(accounts.opening_balance \\* (.+)\\), 0\\) FROM accounts").
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(20000000))
+
+	// bulan pertama kosong (user baru), lalu net 3 juta, 1 juta, 2 juta, 2 juta dan bulan berjalan
+	history := [][2]float64{{8000000, 5000000}, {8000000, 7000000}, {8000000, 6000000}, {8000000, 6000000}, {8000000, 1000000}}
+	historyRows := sqlmock.NewRows([]string{"bucket", "income", "expense"})
+	for i, month := range history {
+		historyRows.AddRow(currentMonth.AddDate(0, i-4, 0), month[0], month[1])
+	}
+	mock.ExpectQuery("SELECT date_trunc\\(\\?, transactions.date AT TIME ZONE 'UTC'\\) AS bucket, (.+) FROM `transactions` WHERE (.+) GROUP BY `bucket` ORDER BY bucket").
+		WithArgs("month", userID, currentMonth.AddDate(0, -5, 0), currentMonth.AddDate(0, 1, 0)).
+		WillReturnRows(historyRows)
+
+	// gaji recurring 8 juta tiap bulan sudah termasuk di history
+	recurringLines := sqlmock.NewRows([]string{"date", "net"})
+	for i := 1; i < 5; i++ {
+		recurringLines.AddRow(currentMonth.AddDate(0, i-5, 0), 8000000)
+	}
+	mock.ExpectQuery("SELECT transactions.date, (.+) FROM `transactions` JOIN recurring_occurrences ON recurring_occurrences.transaction_id = transactions.id").
+		WithArgs(userID, currentMonth.AddDate(0, -5, 0).Format("2006-01-02")).
+		WillReturnRows(recurringLines)
+
+	startDate := currentMonth.AddDate(0, -4, 0)
+	nextRun := currentMonth.AddDate(0, 1, 0)
+	mock.ExpectQuery("SELECT \\* FROM `recurring_transactions` WHERE \\(user_id = \\? AND active = \\?\\)").
+		WithArgs(userID, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "category_id", "amount", "type", "frequency", "interval", "start_date", "occurrence_count", "next_run_date", "active"}).
+			AddRow(7, userID, 1, 8000000, "income", "monthly", 1, startDate, 5, nextRun, true))
+	// gaji bulan depan di-skip
+	mock.ExpectQuery("SELECT \\* FROM `recurring_occurrences` WHERE recurring_transaction_id IN \\(\\?\\) AND date < \\? AND status <> \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recurring_transaction_id", "date", "status"}).
+			AddRow(1, 7, nextRun, "skipped"))
+
+	result, err := dashboardService.GetCashFlowForecast(userID, 3, utility.LocalDate(now, time.UTC), "id")
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Labels, 9)
+	lastMonth := currentMonth.AddDate(0, 3, 0)
+	assert.Equal(t, fmt.Sprintf("%s %d", utility.ShortMonthName(lastMonth.Month(), "id"), lastMonth.Year()), result.Labels[8])
+
+	// non-recurring net rata-rata (-5 -7 -6 -6) / 4 = -6 juta
+	assert.Equal(t, float64(-6000000), result.AverageMonthlyNet)
+
+	actual := result.Datasets[0].Data
+	assert.Equal(t, float64(20000000), *actual[5])
+	assert.Equal(t, float64(13000000), *actual[4])
+	assert.Nil(t, actual[6])
+
+	forecast := result.Datasets[1].Data
+	assert.Nil(t, forecast[4])
+	assert.Equal(t, float64(20000000), *forecast[5])
+	assert.Equal(t, float64(14000000), *forecast[6]) // gaji di-skip
+	assert.Equal(t, float64(16000000), *forecast[7])
+	assert.Equal(t, float64(18000000), *forecast[8])
+	assert.Greater(t, *result.Datasets[2].Data[8], *forecast[8])
+	assert.Less(t, *result.Datasets[3].Data[8], *forecast[8])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketStart(t *testing.T) {
+	// 2025-03-13 adalah hari Kamis
+	date := time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC), utility.BucketStart(date, utility.GranularityDay))
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), utility.BucketStart(date, utility.GranularityWeek))
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), utility.BucketStart(date, utility.GranularityMonth))
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), utility.BucketStart(date, utility.GranularityQuarter))
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), utility.BucketStart(date, utility.GranularityYear))
+
+	// hari Minggu masuk minggu yang dimulai Senin sebelumnya
+	sunday := time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), utility.BucketStart(sunday, utility.GranularityWeek))
+}
+
+func TestBuckets(t *testing.T) {
+	from := time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	buckets, ok := utility.Buckets(from, to, utility.GranularityQuarter, 10)
+	assert.True(t, ok)
+	assert.Len(t, buckets, 3)
+	assert.Equal(t, "Q4 2024", utility.BucketLabel(buckets[0], utility.GranularityQuarter, "en"))
+	assert.Equal(t, "Q2 2025", utility.BucketLabel(buckets[2], utility.GranularityQuarter, "en"))
+
+	_, ok = utility.Buckets(from, to, utility.GranularityDay, 100)
+	assert.False(t, ok)
+}
+
+func TestGetIncomeExpenseSeries(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardUtil := &utility.DashboardUtil{DB: db}
+	userID := uint(1)
+
+	from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC)
+	buckets, _ := utility.Buckets(from, to, utility.GranularityWeek, 10)
+
+	// minggu kedua tidak punya transaksi sehingga tidak ada di hasil query
+	mock.ExpectQuery("SELECT date_trunc\\(\\?, transactions.date AT TIME ZONE 'UTC'\\) AS bucket, (.+) FROM `transactions` "+
+		"WHERE user_id = \\? AND deleted_at IS NULL AND date >= \\? AND date < \\? GROUP BY `bucket` ORDER BY bucket").
+		WithArgs("week", userID, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "income", "expense"}).
+			AddRow(time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), 5000000, 750000).
+			AddRow(time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC), 0, 1200000))
+
+	series, err := dashboardUtil.GetIncomeExpenseSeries(userID, buckets, to, utility.GranularityWeek)
+
+	assert.NoError(t, err)
+	assert.Len(t, series, 3)
+	assert.Equal(t, float64(5000000), series[0].Income)
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), series[1].Bucket)
+	assert.Equal(t, float64(0), series[1].Expense)
+	assert.Equal(t, float64(1200000), series[2].Expense)
+	assert.Equal(t, "2025-03-17", utility.BucketLabel(series[2].Bucket, utility.GranularityWeek, "en"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCalculateMonthlyTotals_SkipTrashedTransactions(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardUtil := &utility.DashboardUtil{DB: db}
+	userID := uint(1)
+
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\((.+)\\), 0\\) FROM `transactions` WHERE user_id = \\? AND type = 'income' AND deleted_at IS NULL AND date >= \\?").
+		WithArgs(userID, "2025-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(5000000))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\((.+)\\), 0\\) FROM `transactions` WHERE user_id = \\? AND type = 'expense' AND deleted_at IS NULL AND date >= \\?").
+		WithArgs(userID, "2025-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(750000))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(CASE WHEN type = 'income' (.+) END\\), 0\\) FROM `transactions` WHERE user_id = \\? AND deleted_at IS NULL").
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(4250000))
+
+	income, err := dashboardUtil.CalculateMonthlyIncome(userID, "2025-03-01")
+	assert.NoError(t, err)
+	expense, err := dashboardUtil.CalculateMonthlyExpense(userID, "2025-03-01")
+	assert.NoError(t, err)
+	savings, err := dashboardUtil.CalculateTotalSavings(userID)
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(5000000), income)
+	assert.Equal(t, float64(750000), expense)
+	assert.Equal(t, float64(4250000), savings)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDashboardCharts_InvalidRange(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewDashboardService(db)
+
+	expectUserPreferences(mock, 1, "UTC")
+	_, err := dashboardService.GetDashboardCharts(1, request.DashboardChartsFilter{From: "2025-03-01", To: "2025-02-01"})
+	assert.ErrorIs(t, err, service.ErrInvalidChartRange)
+
+	expectUserPreferences(mock, 1, "UTC")
+	_, err = dashboardService.GetDashboardCharts(1, request.DashboardChartsFilter{From: "01/03/2025"})
+	assert.ErrorIs(t, err, service.ErrInvalidChartDate)
+
+	expectUserPreferences(mock, 1, "UTC")
+	_, err = dashboardService.GetDashboardCharts(1, request.DashboardChartsFilter{From: "2020-01-01", To: "2025-01-01", Granularity: "day"})
+	assert.ErrorIs(t, err, service.ErrTooManyChartBuckets)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,232 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"go-electroshop/internal/exchangerate"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const exchangeRateCSV = `date,from,to,rate
+2025-01-02,USD,IDR,16200
+2025-01-20,USD,IDR,16350
+2025-01-02,IDR,SGD,0.000083
+`
+
+func TestFileProvider_Rate(t *testing.T) {
+	provider, err := exchangerate.ReadFileProvider(strings.NewReader(exchangeRateCSV))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	// kurs terakhir pada atau sebelum tanggal
+	rate, err := provider.Rate(ctx, "USD", "IDR", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 16200.0, rate)
+
+	rate, err = provider.Rate(ctx, "usd", "idr", time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 16350.0, rate)
+
+	// hanya ada IDR ke SGD, SGD ke IDR memakai kebalikannya
+	rate, err = provider.Rate(ctx, "SGD", "IDR", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.InDelta(t, 12048.19, rate, 0.01)
+
+	_, err = provider.Rate(ctx, "USD", "IDR", time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+	assert.True(t, errors.Is(err, exchangerate.ErrRateNotFound))
+
+	_, err = exchangerate.ReadFileProvider(strings.NewReader("2025-01-02,USD,IDR,abc\n"))
+	assert.EqualError(t, err, `line 1: invalid rate "abc"`)
+}
+
+func TestBaseRateSQL_OrdersLaterRatesAscending(t *testing.T) {
+	expr := utility.BaseRateSQL("transactions.currency", "transactions.date", "transactions.user_id")
+
+	// kurs pada atau sebelum tanggal diurutkan dari yang terbaru, kalau tidak ada baru kurs
+	// setelah tanggal dari yang terdekat, bukan kurs terbaru yang tersimpan
+	assert.Equal(t, 2, strings.Count(expr, "ORDER BY exchange_rates.date <= transactions.date DESC, "+
+		"CASE WHEN exchange_rates.date <= transactions.date THEN exchange_rates.date END DESC, "+
+		"exchange_rates.date ASC LIMIT 1"))
+	assert.NotContains(t, expr, "exchange_rates.date DESC LIMIT 1")
+}
+
+func usdAccountRows(userID uint) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name", "type", "opening_balance", "currency"}).
+		AddRow(3, now, now, nil, userID, "Chase", "bank", 0.0, "USD")
+}
+
+func TestCreateTransaction_ForeignAccountFetchesRate(t *testing.T) {
+	db, mock := setupTestDB(t)
+	provider, _ := exchangerate.ReadFileProvider(strings.NewReader(exchangeRateCSV))
+	transactionService := service.NewTransactionService(db)
+	transactionService.RateProvider = provider
+	userID := uint(1)
+	accountID := uint(3)
+	date := time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	expectPersonalLedger(mock, userID, 4)
+	mock.ExpectQuery("SELECT (.+) FROM `categories` WHERE \\(id = \\? AND ledger_id = \\?\\)").
+		WithArgs(2, 4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name"}).
+			AddRow(2, now, now, nil, userID, "Travel"))
+	mock.ExpectQuery("SELECT (.+) FROM `accounts` WHERE \\(id = \\? AND user_id = \\?\\)").
+		WithArgs(accountID, userID, 1).
+		WillReturnRows(usdAccountRows(userID))
+	expectUserPreferences(mock, userID, "UTC")
+
+	// belum ada kurs USD/IDR milik user, diambil dari provider lalu disimpan
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `exchange_rates` WHERE \\(user_id = \\? AND date <= \\?\\) "+
+		"AND \\(\\(from_currency = \\? AND to_currency = \\?\\) OR \\(from_currency = \\? AND to_currency = \\?\\)\\)").
+		WithArgs(userID, date, "USD", "IDR", "IDR", "USD").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `exchange_rates`").
+		WithArgs(userID, "USD", "IDR", date, 16350.0, "file", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `transactions`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(4), 2, accountID, 120.0, "USD", "expense", "Hotel", date).
+		WillReturnResult(sqlmock.NewResult(40, 1))
+	mock.ExpectCommit()
+
+	result, err := transactionService.CreateTransaction(userID, request.CreateTransactionRequest{
+		CategoryID:  2,
+		AccountID:   &accountID,
+		Amount:      120,
+		Type:        "expense",
+		Description: "Hotel",
+		Date:        "2025-01-25",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", result.Currency)
+	assert.Equal(t, "Chase", result.Account)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTransaction_CurrencyMustMatchAccount(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+	accountID := uint(3)
+	now := time.Now()
+
+	expectPersonalLedger(mock, userID, 4)
+	mock.ExpectQuery("SELECT (.+) FROM `categories` WHERE \\(id = \\? AND ledger_id = \\?\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name"}).
+			AddRow(2, now, now, nil, userID, "Travel"))
+	mock.ExpectQuery("SELECT (.+) FROM `accounts` WHERE \\(id = \\? AND user_id = \\?\\)").
+		WillReturnRows(usdAccountRows(userID))
+	expectUserPreferences(mock, userID, "UTC")
+
+	result, err := transactionService.CreateTransaction(userID, request.CreateTransactionRequest{
+		CategoryID: 2,
+		AccountID:  &accountID,
+		Amount:     120,
+		Currency:   "EUR",
+		Type:       "expense",
+		Date:       "2025-01-25",
+	})
+
+	assert.EqualError(t, err, "currency must match the account currency")
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTransaction_MissingExchangeRate(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+	now := time.Now()
+
+	expectPersonalLedger(mock, userID, 4)
+	mock.ExpectQuery("SELECT (.+) FROM `categories` WHERE \\(id = \\? AND ledger_id = \\?\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name"}).
+			AddRow(2, now, now, nil, userID, "Travel"))
+	expectUserPreferences(mock, userID, "UTC")
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `exchange_rates`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	// tanpa rate provider, kurs harus diinput user terlebih dahulu
+	result, err := transactionService.CreateTransaction(userID, request.CreateTransactionRequest{
+		CategoryID: 2,
+		Amount:     50,
+		Currency:   "sgd",
+		Type:       "expense",
+		Date:       "2025-01-25",
+	})
+
+	assert.EqualError(t, err, "no exchange rate from SGD to IDR on 2025-01-25, add one first")
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveExchangeRate_Upsert(t *testing.T) {
+	db, mock := setupTestDB(t)
+	exchangeRateService := service.NewExchangeRateService(db, nil)
+	userID := uint(1)
+	date := time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	expectUserPreferences(mock, userID, "Asia/Jakarta")
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `exchange_rates` (.+) ON DUPLICATE KEY UPDATE").
+		WithArgs(userID, "USD", "IDR", date, 16400.0, "manual", sqlmock.AnyArg(), sqlmock.AnyArg(), 16400.0, "manual", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT (.+) FROM `exchange_rates` WHERE user_id = \\? AND from_currency = \\? AND to_currency = \\? AND date = \\?").
+		WithArgs(userID, "USD", "IDR", date, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "from_currency", "to_currency", "date", "rate", "source", "created_at", "updated_at"}).
+			AddRow(5, userID, "USD", "IDR", date, 16400.0, "manual", now, now))
+
+	result, err := exchangeRateService.SaveExchangeRate(userID, request.ExchangeRateRequest{
+		FromCurrency: "usd",
+		ToCurrency:   "IDR",
+		Rate:         16400,
+		Date:         "2025-01-20",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), result.ID)
+	assert.Equal(t, "USD", result.FromCurrency)
+
+	_, err = exchangeRateService.SaveExchangeRate(userID, request.ExchangeRateRequest{FromCurrency: "IDR", ToCurrency: "idr", Rate: 1})
+	assert.EqualError(t, err, "exchange rate currencies must be different")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTransfer_DifferentCurrencies(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transferService := service.NewTransferService(db)
+	userID := uint(1)
+	now := time.Now()
+
+	expectUserPreferences(mock, userID, "UTC")
+	mock.ExpectQuery("SELECT (.+) FROM `accounts` WHERE \\(id IN \\(\\?,\\?\\) AND user_id = \\?\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name", "type", "opening_balance", "currency"}).
+			AddRow(1, now, now, nil, userID, "BCA", "bank", 1000000.0, "IDR").
+			AddRow(3, now, now, nil, userID, "Chase", "bank", 0.0, "USD"))
+
+	result, err := transferService.CreateTransfer(userID, request.TransferRequest{
+		FromAccountID: 1,
+		ToAccountID:   3,
+		Amount:        1000000,
+		Date:          "2025-01-29",
+	})
+
+	assert.EqualError(t, err, "cannot transfer between accounts with different currencies")
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
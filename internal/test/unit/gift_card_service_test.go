@@ -0,0 +1,86 @@
+package unit
+
+import (
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateGiftCardCode(t *testing.T) {
+	code, err := utility.GenerateGiftCardCode()
+
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[A-HJ-NP-Z2-9]{4}(-[A-HJ-NP-Z2-9]{4}){3}$`), code)
+	assert.Equal(t, utility.HashGiftCardCode(code), utility.HashGiftCardCode(" "+code[:9]+" "+code[9:]+" "))
+}
+
+func giftCardRows(balance float64) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{"id", "code_hash", "code_last4", "initial_balance", "balance", "issued_by", "note", "expires_at", "disabled", "created_at", "updated_at"}).
+		AddRow(1, utility.HashGiftCardCode("ABCD-EFGH-JKLM-NPQR"), "NPQR", 100000.0, balance, 1, "", nil, false, now, now)
+}
+
+func TestRedeemGiftCard_PartialBalance(t *testing.T) {
+	db, mock := setupTestDB(t)
+	giftCardService := service.NewGiftCardService(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `gift_cards` WHERE code_hash = \\? (.+) FOR UPDATE").
+		WithArgs(utility.HashGiftCardCode("abcd efgh jklm npqr"), 1).
+		WillReturnRows(giftCardRows(30000))
+	mock.ExpectQuery("SELECT (.+) FROM `gift_card_usages` WHERE gift_card_id = \\? AND reference = \\? AND amount < 0").
+		WithArgs(uint(1), "checkout-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	// saldo 30.000 dipakai semua untuk pembayaran 50.000
+	mock.ExpectExec("UPDATE `gift_cards` SET `balance`=\\?").
+		WithArgs(float64(0), sqlmock.AnyArg(), uint(1), float64(30000)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `gift_card_usages`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := giftCardService.Redeem(2, "abcd efgh jklm npqr", 50000, "checkout-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(30000), result.AppliedAmount)
+	assert.Equal(t, float64(0), result.RemainingBalance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedeemGiftCard_SameReferenceIsIdempotent(t *testing.T) {
+	db, mock := setupTestDB(t)
+	giftCardService := service.NewGiftCardService(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `gift_cards` WHERE code_hash = \\? (.+) FOR UPDATE").
+		WithArgs(utility.HashGiftCardCode("ABCD-EFGH-JKLM-NPQR"), 1).
+		WillReturnRows(giftCardRows(70000))
+	mock.ExpectQuery("SELECT (.+) FROM `gift_card_usages` WHERE gift_card_id = \\? AND reference = \\? AND amount < 0").
+		WithArgs(uint(1), "checkout-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "gift_card_id", "amount", "balance_after", "reference"}).
+			AddRow(5, 1, -30000.0, 70000.0, "checkout-1"))
+	mock.ExpectCommit()
+
+	result, err := giftCardService.Redeem(2, "ABCD-EFGH-JKLM-NPQR", 30000, "checkout-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(30000), result.AppliedAmount)
+	assert.Equal(t, float64(70000), result.RemainingBalance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedeemGiftCard_IssueReferenceIsReserved(t *testing.T) {
+	db, mock := setupTestDB(t)
+	giftCardService := service.NewGiftCardService(db)
+
+	result, err := giftCardService.Redeem(2, "ABCD-EFGH-JKLM-NPQR", 30000, "issue")
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "reference is reserved")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,156 @@
+package unit
+
+import (
+	"context"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var expenseLineColumns = []string{"transaction_id", "category_id", "category_name", "date", "amount"}
+
+func TestDetectSpendingInsights(t *testing.T) {
+	monthStart := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	line := func(transactionID, categoryID uint, category string, date time.Time, amount float64) utility.ExpenseLine {
+		return utility.ExpenseLine{TransactionID: transactionID, CategoryID: categoryID, Category: category, Date: date, Amount: amount}
+	}
+
+	history := []utility.ExpenseLine{
+		line(1, 2, "Groceries", time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), 100000),
+		line(2, 2, "Groceries", time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), 120000),
+		line(3, 2, "Groceries", time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC), 80000),
+		line(4, 2, "Groceries", time.Date(2025, 2, 20, 0, 0, 0, 0, time.UTC), 100000),
+		line(5, 2, "Groceries", time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), 100000),
+		line(6, 2, "Groceries", time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC), 100000),
+		// history Transport terlalu sedikit sehingga tidak pernah ditandai
+		line(7, 3, "Transport", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), 10000),
+		line(8, 3, "Transport", time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), 10000),
+		// rata-rata bulanan Electronics 1.000.000
+		line(9, 4, "Electronics", time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), 1000000),
+		line(10, 4, "Electronics", time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC), 1000000),
+		line(11, 4, "Electronics", time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), 1000000),
+	}
+	current := []utility.ExpenseLine{
+		line(20, 2, "Groceries", time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC), 90000),
+		line(21, 2, "Groceries", time.Date(2025, 4, 8, 0, 0, 0, 0, time.UTC), 550000),
+		line(22, 3, "Transport", time.Date(2025, 4, 9, 0, 0, 0, 0, time.UTC), 500000),
+		line(23, 4, "Electronics", time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC), 1400000),
+	}
+
+	insights := utility.DetectSpendingInsights(history, current, monthStart)
+
+	assert.Len(t, insights, 3)
+
+	// median Groceries 100.000, transaksi 550.000 lebih dari 5x
+	assert.Equal(t, "unusual_transaction:21:2", insights[0].Key)
+	assert.Equal(t, entity.InsightSeverityCritical, insights[0].Severity)
+	assert.Equal(t, uint(21), insights[0].TransactionID)
+	assert.Equal(t, 100000.0, insights[0].Baseline)
+
+	// rata-rata bulanan Groceries 200.000, bulan ini 640.000
+	assert.Equal(t, "category_spike:2:2025-04", insights[1].Key)
+	assert.Equal(t, entity.InsightSeverityCritical, insights[1].Severity)
+	assert.Equal(t, 640000.0, insights[1].Amount)
+	assert.Equal(t, 200000.0, insights[1].Baseline)
+
+	assert.Equal(t, "category_spike:4:2025-04", insights[2].Key)
+	assert.Equal(t, entity.InsightSeverityInfo, insights[2].Severity)
+	assert.InDelta(t, 1.4, insights[2].Ratio(), 0.0001)
+
+	assert.Empty(t, utility.DetectSpendingInsights(nil, current, monthStart))
+}
+
+func TestRefreshInsights(t *testing.T) {
+	db, mock := setupTestDB(t)
+	insightService := service.NewInsightService(db)
+	userID := uint(1)
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT DISTINCT `user_id` FROM `transactions` WHERE \\(type = \\? AND date >= \\?\\)").
+		WithArgs("expense", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
+	mock.ExpectQuery("SELECT DISTINCT `user_id` FROM `insights`").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
+
+	expectUserPreferences(mock, userID, "UTC")
+	history := sqlmock.NewRows(expenseLineColumns)
+	for month := 1; month <= 3; month++ {
+		date := monthStart.AddDate(0, -month, 0)
+		history.AddRow(10+month, 2, "Groceries", date, 100000.0)
+		history.AddRow(20+month, 2, "Groceries", date.AddDate(0, 0, 10), 100000.0)
+	}
+	mock.ExpectQuery("SELECT category_lines.transaction_id(.+)ORDER BY category_lines.date ASC, category_lines.transaction_id ASC").
+		WillReturnRows(history)
+	mock.ExpectQuery("SELECT category_lines.transaction_id(.+)ORDER BY category_lines.date ASC, category_lines.transaction_id ASC").
+		WillReturnRows(sqlmock.NewRows(expenseLineColumns).AddRow(40, 2, "Groceries", monthStart, 400000.0))
+
+	label := utility.BucketLabel(monthStart, utility.GranularityMonth, "en")
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `insights` (.+) ON DUPLICATE KEY UPDATE `kind`=VALUES\\(`kind`\\)").
+		WithArgs(
+			userID, "unusual_transaction:40:2", "unusual_transaction", "warning",
+			"Unusual Groceries expense of IDR 400,000.00 on "+monthStart.Format("2 Jan 2006")+", 4.0x your typical IDR 100,000.00",
+			2, 40, monthStart, 400000.0, 100000.0, nil, sqlmock.AnyArg(), sqlmock.AnyArg(),
+			userID, "category_spike:2:"+monthStart.Format("2006-01"), "category_spike", "critical",
+			"You spent 100% more on Groceries in "+label+": IDR 400,000.00 so far vs IDR 200,000.00 in a typical month",
+			2, nil, monthStart, 400000.0, 200000.0, nil, sqlmock.AnyArg(), sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectExec("DELETE FROM `insights` WHERE \\(user_id = \\? AND period >= \\?\\) AND key NOT IN \\(\\?,\\?\\)").
+		WithArgs(userID, monthStart, "unusual_transaction:40:2", "category_spike:2:"+monthStart.Format("2006-01")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `insights` WHERE user_id = \\? AND period < \\?").
+		WithArgs(userID, monthStart.AddDate(0, -3, 0)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := insightService.RefreshInsights(context.Background())
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetInsights(t *testing.T) {
+	db, mock := setupTestDB(t)
+	insightService := service.NewInsightService(db)
+	userID := uint(1)
+	period := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	expectUserPreferences(mock, userID, "UTC")
+	mock.ExpectQuery("SELECT \\* FROM `insights` WHERE user_id = \\? AND dismissed_at IS NULL ORDER BY CASE severity (.+) END,period DESC, amount DESC").
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "key", "kind", "severity", "message", "category_id", "transaction_id", "period", "amount", "baseline"}).
+			AddRow(5, userID, "category_spike:2:2025-04", "category_spike", "critical", "You spent 100% more on Groceries", 2, nil, period, 400000.0, 200000.0))
+
+	insights, err := insightService.GetInsights(userID, request.InsightFilter{})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, insights, 1)
+	assert.Equal(t, "2025-04", insights[0].Period)
+	assert.Equal(t, "IDR", insights[0].Currency)
+	assert.Nil(t, insights[0].TransactionID)
+}
+
+func TestDismissInsight_NotFound(t *testing.T) {
+	db, mock := setupTestDB(t)
+	insightService := service.NewInsightService(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `insights` SET `dismissed_at`=COALESCE\\(dismissed_at, \\?\\) WHERE id = \\? AND user_id = \\?").
+		WithArgs(sqlmock.AnyArg(), 9, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := insightService.DismissInsight(1, 9)
+
+	assert.ErrorIs(t, err, service.ErrInsightNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
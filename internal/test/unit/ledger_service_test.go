@@ -0,0 +1,194 @@
+package unit
+
+import (
+	"context"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectPersonalLedger(mock sqlmock.Sqlmock, userID, ledgerID uint) {
+	mock.ExpectQuery("SELECT `id` FROM `ledgers` WHERE \\(owner_id = \\? AND personal = \\?\\)").
+		WithArgs(userID, true, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(ledgerID))
+}
+
+func expectLedgerRole(mock sqlmock.Sqlmock, userID, ledgerID uint, role string) {
+	rows := sqlmock.NewRows([]string{"role"})
+	if role != "" {
+		rows.AddRow(role)
+	}
+	mock.ExpectQuery("SELECT `ledger_members`.`role` FROM `ledger_members` JOIN ledgers ON ledgers.id = ledger_members.ledger_id AND ledgers.deleted_at IS NULL "+
+		"WHERE ledger_members.ledger_id = \\? AND ledger_members.user_id = \\?").
+		WithArgs(ledgerID, userID, 1).
+		WillReturnRows(rows)
+}
+
+func expectLedger(mock sqlmock.Sqlmock, ledgerID, ownerID uint, name string, personal bool) {
+	now := time.Now()
+	mock.ExpectQuery("SELECT \\* FROM `ledgers` WHERE `ledgers`.`id` = \\?").
+		WithArgs(ledgerID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "name", "owner_id", "personal"}).
+			AddRow(ledgerID, now, now, nil, name, ownerID, personal))
+}
+
+func TestCreateTransaction_ViewerCannotWrite(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+
+	expectLedgerRole(mock, 2, 7, "viewer")
+
+	result, err := transactionService.CreateTransaction(2, request.CreateTransactionRequest{
+		LedgerID:   7,
+		CategoryID: 3,
+		Amount:     50000,
+		Type:       "expense",
+		Date:       "2025-01-29",
+	})
+
+	assert.ErrorIs(t, err, service.ErrLedgerReadOnly)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTransactionByUser_NotLedgerMember(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+
+	expectLedgerRole(mock, 2, 7, "")
+
+	result, err := transactionService.GetTransactionByUser(2, request.TransactionFilter{LedgerID: 7, Page: 1, Limit: 10})
+
+	assert.ErrorIs(t, err, service.ErrLedgerNotFound)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInviteMember(t *testing.T) {
+	db, mock := setupTestDB(t)
+	recorder := &recordingNotifier{}
+	ledgerService := service.NewLedgerService(db, recorder)
+	ownerID := uint(1)
+
+	expectLedger(mock, 7, ownerID, "Household", false)
+	expectLedgerRole(mock, ownerID, 7, "owner")
+	mock.ExpectQuery("SELECT `id` FROM `users` WHERE LOWER\\(email\\) = \\?").
+		WithArgs("partner@example.com", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	expectLedgerRole(mock, 2, 7, "")
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `ledger_invitations` (.+) ON DUPLICATE KEY UPDATE `role`=VALUES\\(`role`\\)").
+		WithArgs(uint(7), "partner@example.com", "editor", ownerID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
+
+	invitation, err := ledgerService.InviteMember(context.Background(), ownerID, 7, request.LedgerInvitationRequest{
+		Email: " Partner@Example.com ",
+		Role:  "editor",
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, "partner@example.com", invitation.Email)
+	assert.Equal(t, "Household", invitation.Ledger)
+	assert.Equal(t, []uint{2}, recorder.userIDs)
+	assert.Equal(t, "Invitation to Household", recorder.messages[0].Subject)
+}
+
+func TestInviteMember_PersonalLedger(t *testing.T) {
+	db, mock := setupTestDB(t)
+	ledgerService := service.NewLedgerService(db, &recordingNotifier{})
+
+	expectLedger(mock, 4, 1, "Personal", true)
+	expectLedgerRole(mock, 1, 4, "owner")
+
+	invitation, err := ledgerService.InviteMember(context.Background(), 1, 4, request.LedgerInvitationRequest{
+		Email: "partner@example.com",
+		Role:  "viewer",
+	})
+
+	assert.ErrorIs(t, err, service.ErrPersonalLedger)
+	assert.Nil(t, invitation)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	db, mock := setupTestDB(t)
+	ledgerService := service.NewLedgerService(db, &recordingNotifier{})
+	userID := uint(2)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT `email` FROM `users` WHERE id = \\?").
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("Partner@example.com"))
+	mock.ExpectQuery("SELECT \\* FROM `ledger_invitations` WHERE id = \\? AND email = \\? AND expires_at > \\?").
+		WithArgs(3, "partner@example.com", sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ledger_id", "email", "role", "invited_by", "expires_at", "created_at", "updated_at"}).
+			AddRow(3, 7, "partner@example.com", "editor", 1, now.Add(time.Hour), now, now))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `ledger_members` \\(`ledger_id`,`user_id`,`role`,`created_at`,`updated_at`\\) VALUES (.+) ON DUPLICATE KEY UPDATE `id`=`id`").
+		WithArgs(uint(7), userID, "editor", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec("DELETE FROM `ledger_invitations` WHERE `ledger_invitations`.`id` = \\?").
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := ledgerService.AcceptInvitation(userID, 3)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoveMember_OnlyOwnerRemovesOthers(t *testing.T) {
+	db, mock := setupTestDB(t)
+	ledgerService := service.NewLedgerService(db, &recordingNotifier{})
+
+	expectLedger(mock, 7, 1, "Household", false)
+	expectLedgerRole(mock, 2, 7, "editor")
+
+	err := ledgerService.RemoveMember(2, 7, 3)
+
+	assert.ErrorIs(t, err, service.ErrLedgerOwnerOnly)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLedgerSummary(t *testing.T) {
+	db, mock := setupTestDB(t)
+	ledgerService := service.NewLedgerService(db, &recordingNotifier{})
+	userID := uint(2)
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	expectLedger(mock, 7, 1, "Household", false)
+	expectLedgerRole(mock, userID, 7, "viewer")
+	expectUserPreferences(mock, userID, "UTC")
+	mock.ExpectQuery("SELECT transactions.user_id, users.name AS user_name, (.+) FROM `transactions` JOIN users ON users.id = transactions.user_id "+
+		"WHERE \\(transactions.ledger_id = \\? AND transactions.deleted_at IS NULL\\) AND \\(transactions.date >= \\? AND transactions.date < \\?\\) "+
+		"GROUP BY transactions.user_id, users.name ORDER BY expense DESC, transactions.user_id ASC").
+		WithArgs(7, start, end).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "user_name", "income", "expense", "transactions"}).
+			AddRow(1, "Budi", 10000000.0, 4500000.0, 12).
+			AddRow(2, "Sari", 0.0, 1500000.0, 5))
+	mock.ExpectQuery("SELECT categories.name as category_name, (.+) FROM \\(SELECT (.+) WHERE \\(transactions.ledger_id = \\? AND transactions.deleted_at IS NULL\\)(.+)\\) AS category_lines").
+		WithArgs(7, start, end).
+		WillReturnRows(sqlmock.NewRows([]string{"category_name", "type", "total"}).
+			AddRow("groceries", "expense", 6000000.0).
+			AddRow("salary", "income", 10000000.0))
+
+	summary, err := ledgerService.GetLedgerSummary(userID, 7, request.LedgerSummaryFilter{Month: "2025-03"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, "2025-03", summary.Month)
+	assert.Equal(t, 10000000.0, summary.Income)
+	assert.Equal(t, 6000000.0, summary.Expense)
+	assert.Equal(t, 4000000.0, summary.Net)
+	assert.Len(t, summary.Members, 2)
+	assert.Equal(t, "Sari", summary.Members[1].Name)
+	assert.Equal(t, "groceries", summary.Categories[0].Category)
+}
@@ -0,0 +1,64 @@
+package unit
+
+import (
+	"go-electroshop/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectLockLoyaltyAccount(mock sqlmock.Sqlmock, userID uint, balance int64) {
+	mock.ExpectExec("INSERT INTO `loyalty_accounts`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT (.+) FROM `loyalty_accounts` WHERE user_id = \\? (.+) FOR UPDATE").
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "balance", "updated_at"}).
+			AddRow(userID, balance, time.Now()))
+}
+
+func TestRedeemLoyaltyPoints(t *testing.T) {
+	db, mock := setupTestDB(t)
+	loyaltyService := service.NewLoyaltyService(db)
+	userID := uint(1)
+
+	mock.ExpectBegin()
+	expectLockLoyaltyAccount(mock, userID, 500)
+	mock.ExpectQuery("SELECT (.+) FROM `loyalty_ledger_entries`").
+		WithArgs(userID, "redeem", "checkout-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("UPDATE `loyalty_accounts` SET `balance`=\\?").
+		WithArgs(int64(300), sqlmock.AnyArg(), userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `loyalty_ledger_entries`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := loyaltyService.Redeem(userID, 200, "checkout-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-200), result.Entry.Points)
+	assert.Equal(t, int64(300), result.Entry.Balance)
+	assert.Equal(t, float64(20000), result.Discount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedeemLoyaltyPoints_InsufficientBalance(t *testing.T) {
+	db, mock := setupTestDB(t)
+	loyaltyService := service.NewLoyaltyService(db)
+	userID := uint(1)
+
+	mock.ExpectBegin()
+	expectLockLoyaltyAccount(mock, userID, 50)
+	mock.ExpectQuery("SELECT (.+) FROM `loyalty_ledger_entries`").
+		WithArgs(userID, "redeem", "checkout-2", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	result, err := loyaltyService.Redeem(userID, 200, "checkout-2")
+
+	assert.ErrorIs(t, err, service.ErrInsufficientPoints)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,78 @@
+package unit
+
+import (
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateProduct_RecordsPriceHistory(t *testing.T) {
+	db, mock := setupTestDB(t)
+	productService := service.NewProductService(db)
+	now := time.Now()
+
+	req := &request.UpdateProductRequest{
+		Category: "Iphone",
+		Name:     "Iphone 13 Pro",
+		Price:    11000000,
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `products`").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "category", "name", "price"}).
+			AddRow(1, now, now, nil, "Iphone", "Iphone 13 Pro", 12000000.0))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `products`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO `product_price_histories`").
+		WithArgs(uint(1), 12000000.0, 11000000.0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// harga turun, cek price alert yang target-nya tercapai
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `price_alerts`").
+		WithArgs(uint(1), true, 11000000.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "product_id", "target_price", "active"}))
+	mock.ExpectCommit()
+
+	result, err := productService.UpdateProduct(1, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 11000000.0, result.Price)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateProduct_SamePriceSkipsHistory(t *testing.T) {
+	db, mock := setupTestDB(t)
+	productService := service.NewProductService(db)
+	now := time.Now()
+
+	req := &request.UpdateProductRequest{
+		Category: "Iphone",
+		Name:     "Iphone 13 Pro Max",
+		Price:    12000000,
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `products`").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "category", "name", "price"}).
+			AddRow(1, now, now, nil, "Iphone", "Iphone 13 Pro", 12000000.0))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `products`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := productService.UpdateProduct(1, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Iphone 13 Pro Max", result.Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,64 @@
+package unit
+
+import (
+	"context"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetViewAnalytics_RangeTooLong(t *testing.T) {
+	db, mock := setupTestDB(t)
+	productViewService := service.NewProductViewService(db)
+
+	result, err := productViewService.GetViewAnalytics(request.ProductViewFilter{From: "2024-01-01", To: "2025-06-30"})
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "date range cannot exceed 366 days")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetViewAnalytics_SkipsDeletedProducts(t *testing.T) {
+	db, mock := setupTestDB(t)
+	productViewService := service.NewProductViewService(db)
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT (.+) FROM `product_view_stats` JOIN products ON products.id = product_view_stats.product_id AND products.deleted_at IS NULL "+
+		"WHERE product_view_stats.date BETWEEN \\? AND \\? GROUP BY product_view_stats.product_id, products.name ORDER BY total_views DESC LIMIT \\?").
+		WithArgs("2025-03-01", "2025-03-07", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "product_name", "total_views"}).AddRow(3, "Kipas Angin", 42))
+	mock.ExpectQuery("SELECT \\* FROM `product_view_stats` WHERE product_id IN \\(\\?\\) AND date BETWEEN \\? AND \\? ORDER BY date ASC").
+		WithArgs(3, "2025-03-01", "2025-03-07").
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "date", "view_count"}).AddRow(3, date, 42))
+
+	result, err := productViewService.GetViewAnalytics(request.ProductViewFilter{From: "2025-03-01", To: "2025-03-07"})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Products, 1)
+	assert.Equal(t, "Kipas Angin", result.Products[0].ProductName)
+	assert.Equal(t, int64(42), result.Products[0].TotalViews)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPruneViews_DeletesInBatches(t *testing.T) {
+	db, mock := setupTestDB(t)
+	productViewService := service.NewProductViewService(db)
+
+	// batch pertama penuh sehingga dilanjutkan, batch kedua berisi sisa row
+	for _, deleted := range []int64{1000, 12} {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM `product_views` WHERE id IN \\(SELECT `id` FROM `product_views` WHERE viewed_at < \\? LIMIT \\?\\)").
+			WithArgs(sqlmock.AnyArg(), 1000).
+			WillReturnResult(sqlmock.NewResult(0, deleted))
+		mock.ExpectCommit()
+	}
+
+	err := productViewService.PruneViews(context.Background())
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
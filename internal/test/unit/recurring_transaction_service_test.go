@@ -0,0 +1,132 @@
+package unit
+
+import (
+	"context"
+	"go-electroshop/internal/payload/entity"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecurrenceDate(t *testing.T) {
+	endOfJanuary := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+	leapDay := time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		start     time.Time
+		frequency string
+		interval  int
+		n         int
+		expected  time.Time
+	}{
+		{"daily", endOfJanuary, entity.RecurrenceDaily, 1, 1, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"every two weeks", endOfJanuary, entity.RecurrenceWeekly, 2, 2, time.Date(2024, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{"monthly clamps to end of february", endOfJanuary, entity.RecurrenceMonthly, 1, 1, leapDay},
+		{"monthly returns to 31 after short month", endOfJanuary, entity.RecurrenceMonthly, 1, 2, time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		{"yearly on leap day", leapDay, entity.RecurrenceYearly, 1, 1, time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{"yearly back on leap day", leapDay, entity.RecurrenceYearly, 1, 4, time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, utility.RecurrenceDate(tt.start, tt.frequency, tt.interval, tt.n))
+		})
+	}
+}
+
+func TestMaterializeDue_CatchUpSkipsSkippedOccurrence(t *testing.T) {
+	db, mock := setupTestDB(t)
+	recurringService := service.NewRecurringTransactionService(db)
+
+	today := utility.TruncateToDate(time.Now())
+	lastWeek := today.AddDate(0, 0, -7)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT recurring_transactions.id, recurring_transactions.next_run_date, users.timezone FROM `recurring_transactions` JOIN users ON users.id = recurring_transactions.user_id " +
+		"WHERE \\(recurring_transactions.active = \\? AND recurring_transactions.next_run_date <= \\?\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "next_run_date", "timezone"}).
+			AddRow(1, lastWeek, "UTC").
+			// jadwal user lain yang secara lokal belum jatuh tempo dilewati
+			AddRow(2, today.AddDate(0, 0, 1), "UTC"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `recurring_transactions` (.+) FOR UPDATE").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "category_id", "account_id", "amount", "type", "description", "frequency", "interval", "start_date", "end_date", "max_occurrences", "occurrence_count", "next_run_date", "active"}).
+			AddRow(1, now, now, nil, 1, 2, 3, 5000000.0, "income", "Salary", entity.RecurrenceWeekly, 1, lastWeek, nil, nil, 0, lastWeek, true))
+
+	// occurrence minggu lalu sudah di-skip user, tidak diposting
+	mock.ExpectQuery("SELECT (.+) FROM `recurring_occurrences` WHERE recurring_transaction_id = \\? AND date = \\?").
+		WithArgs(uint(1), lastWeek, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recurring_transaction_id", "date", "status"}).
+			AddRow(7, 1, lastWeek, entity.OccurrenceSkipped))
+
+	// occurrence hari ini diposting
+	mock.ExpectQuery("SELECT (.+) FROM `recurring_occurrences` WHERE recurring_transaction_id = \\? AND date = \\?").
+		WithArgs(uint(1), today, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	// transaksi mengikuti mata uang account recurring
+	mock.ExpectQuery("SELECT `currency` FROM `accounts` WHERE \\(id = \\? AND user_id = \\?\\)").
+		WithArgs(uint(3), uint(1), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"currency"}).AddRow("USD"))
+	// transaksi dicatat di ledger kategori recurring
+	mock.ExpectQuery("SELECT `ledger_id` FROM `categories` WHERE id = \\? LIMIT \\?").
+		WithArgs(uint(2), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"ledger_id"}).AddRow(4))
+	expectLedgerRole(mock, 1, 4, "owner")
+	mock.ExpectExec("INSERT INTO `transactions`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, uint(1), uint(4), uint(2), uint(3), 5000000.0, "USD", "income", "Salary", today).
+		WillReturnResult(sqlmock.NewResult(10, 1))
+	mock.ExpectExec("INSERT INTO `recurring_occurrences`").
+		WithArgs(uint(1), today, entity.OccurrencePosted, nil, nil, uint(10), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+
+	mock.ExpectExec("UPDATE `recurring_transactions` SET `updated_at`=\\?,`occurrence_count`=\\?,`next_run_date`=\\?,`active`=\\?").
+		WithArgs(sqlmock.AnyArg(), 2, today.AddDate(0, 0, 7), true, uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := recurringService.MaterializeDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMaterializeDue_SkipsOwnerNoLongerEditor(t *testing.T) {
+	db, mock := setupTestDB(t)
+	recurringService := service.NewRecurringTransactionService(db)
+
+	today := utility.TruncateToDate(time.Now())
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT recurring_transactions.id, recurring_transactions.next_run_date, users.timezone FROM `recurring_transactions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "next_run_date", "timezone"}).AddRow(1, today, "UTC"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `recurring_transactions` (.+) FOR UPDATE").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "category_id", "account_id", "amount", "type", "description", "frequency", "interval", "start_date", "end_date", "max_occurrences", "occurrence_count", "next_run_date", "active"}).
+			AddRow(1, now, now, nil, 2, 5, 3, 150000.0, "expense", "Internet", entity.RecurrenceMonthly, 1, today, nil, nil, 0, today, true))
+	mock.ExpectQuery("SELECT (.+) FROM `recurring_occurrences` WHERE recurring_transaction_id = \\? AND date = \\?").
+		WithArgs(uint(1), today, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT `currency` FROM `accounts` WHERE \\(id = \\? AND user_id = \\?\\)").
+		WithArgs(uint(3), uint(2), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"currency"}).AddRow("IDR"))
+	mock.ExpectQuery("SELECT `ledger_id` FROM `categories` WHERE id = \\? LIMIT \\?").
+		WithArgs(uint(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"ledger_id"}).AddRow(7))
+	// pemilik recurring sudah diturunkan jadi viewer di shared ledger kategorinya
+	expectLedgerRole(mock, 2, 7, "viewer")
+	mock.ExpectRollback()
+
+	err := recurringService.MaterializeDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
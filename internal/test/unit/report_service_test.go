@@ -0,0 +1,226 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/report"
+	"go-electroshop/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func sampleMonthlyReport() *response.MonthlyReportResponse {
+	incomeChange := 25.0
+	return &response.MonthlyReportResponse{
+		Month:          "2025-01",
+		Label:          "Jan 2025",
+		PeriodStart:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:      time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		Currency:       "IDR",
+		OpeningBalance: 2000000,
+		ClosingBalance: 3500000,
+		Income:         5000000,
+		Expense:        3500000,
+		Net:            1500000,
+		Categories: []response.ReportCategoryTotal{
+			{Category: "Food", Type: "expense", Amount: 2000000, PreviousAmount: 1800000},
+			{Category: "Transport", Type: "expense", Amount: 1500000},
+			{Category: "Salary", Type: "income", Amount: 5000000, PreviousAmount: 4000000},
+		},
+		TopExpenses: []response.ReportExpense{
+			{TransactionID: 7, Date: time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC), Category: "Transport", Description: "Flight (Jakarta - Bali)", Amount: 100, Currency: "USD", BaseAmount: 1620000},
+		},
+		Budgets: []response.BudgetResponse{
+			{ID: 1, CategoryName: "Food", Limit: 1500000, Spent: 2000000, Remaining: -500000, UtilizationPercentage: 133.33, Overspent: true},
+		},
+		Comparison: response.ReportComparison{
+			PreviousMonth:   "2024-12",
+			PreviousLabel:   "Dec 2024",
+			PreviousIncome:  4000000,
+			PreviousExpense: 0,
+			PreviousNet:     4000000,
+			IncomeChange:    &incomeChange,
+		},
+	}
+}
+
+func TestWriteMonthlyReport_XLSX(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NoError(t, report.Write(&buffer, sampleMonthlyReport(), report.FormatXLSX, "en"))
+
+	f, err := excelize.OpenReader(&buffer)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, []string{"Summary", "Categories", "Top Expenses", "Budgets"}, f.GetSheetList())
+
+	closing, _ := f.GetCellValue("Summary", "B9")
+	assert.Equal(t, "3,500,000.00", closing)
+
+	// expense ditulis sebelum income supaya pie chart mengambil satu blok baris
+	rows, _ := f.GetRows("Categories")
+	assert.Equal(t, "Transport", rows[2][1])
+	assert.Equal(t, "income", rows[3][0])
+
+	status, _ := f.GetCellValue("Budgets", "F2")
+	assert.Equal(t, "Overspent", status)
+}
+
+func TestWriteMonthlyReport_PDF(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NoError(t, report.Write(&buffer, sampleMonthlyReport(), report.FormatPDF, "id"))
+
+	pdf := buffer.String()
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, "(Monthly Report Jan 2025) Tj")
+	// format angka mengikuti locale id, tanda kurung di deskripsi di-escape
+	assert.Contains(t, pdf, "(3.500.000,00) Tj")
+	assert.Contains(t, pdf, "(Flight \\(Jakarta - Bali\\)) Tj")
+	assert.Contains(t, pdf, "(USD 100,00) Tj")
+	assert.Contains(t, pdf, "(+25.0%) Tj")
+
+	assert.EqualError(t, report.Write(&buffer, sampleMonthlyReport(), "csv", "en"), "unsupported report format: csv")
+}
+
+// expectMonthlyReportQueries mengharapkan query laporan bulan start setelah preferensi user dimuat
+func expectMonthlyReportQueries(mock sqlmock.Sqlmock, userID uint, start time.Time) {
+	end := start.AddDate(0, 1, 0)
+	previous := start.AddDate(0, -1, 0)
+
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\((.+)FROM `transactions` WHERE user_id = \\? AND deleted_at IS NULL AND date < \\?").
+		WithArgs(userID, userID, start).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(2000000.0))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\((.+)FROM `transactions` WHERE user_id = \\? AND deleted_at IS NULL AND date < \\?").
+		WithArgs(userID, userID, end).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(3500000.0))
+	mock.ExpectQuery("SELECT date_trunc(.+) FROM `transactions`").
+		WithArgs("month", userID, previous, end).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "income", "expense"}).
+			AddRow(previous, 4000000.0, 0.0).
+			AddRow(start, 5000000.0, 3500000.0))
+	mock.ExpectQuery("SELECT categories.name as category_name, category_lines.type(.+)GROUP BY categories.name, category_lines.type").
+		WithArgs(userID, start, end).
+		WillReturnRows(sqlmock.NewRows([]string{"category_name", "type", "total"}).
+			AddRow("Food", "expense", 2000000.0).
+			AddRow("Transport", "expense", 1500000.0).
+			AddRow("Salary", "income", 5000000.0))
+	mock.ExpectQuery("SELECT categories.name as category_name, category_lines.type(.+)GROUP BY categories.name, category_lines.type").
+		WithArgs(userID, previous, start).
+		WillReturnRows(sqlmock.NewRows([]string{"category_name", "type", "total"}).
+			AddRow("Salary", "income", 4000000.0).
+			AddRow("Gift", "income", 250000.0))
+	mock.ExpectQuery("SELECT transactions.id AS transaction_id(.+)ORDER BY base_amount DESC, transactions.date ASC LIMIT \\?").
+		WithArgs(userID, start, end, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "date", "category_name", "description", "amount", "currency", "base_amount"}).
+			AddRow(7, start.AddDate(0, 0, 11), "Transport", "Flight", 100.0, "USD", 1620000.0))
+	mock.ExpectQuery("SELECT budgets.id as budget_id(.+)FROM `budgets`").
+		WithArgs(start, end, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"budget_id", "category_id", "category_name", "budget_limit", "spent"}).
+			AddRow(1, 2, "Food", 1500000.0, 2000000.0))
+}
+
+func TestGetMonthlyReport(t *testing.T) {
+	db, mock := setupTestDB(t)
+	reportService := service.NewReportService(db, nil)
+	userID := uint(1)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	expectUserPreferences(mock, userID, "Asia/Jakarta")
+	expectMonthlyReportQueries(mock, userID, start)
+
+	result, err := reportService.GetMonthlyReport(userID, "2025-01")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, "Jan 2025", result.Label)
+	assert.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), result.PeriodEnd)
+	assert.Equal(t, 2000000.0, result.OpeningBalance)
+	assert.Equal(t, 3500000.0, result.ClosingBalance)
+	assert.Equal(t, 1500000.0, result.Net)
+
+	// kategori yang hanya ada di bulan sebelumnya tetap muncul dengan amount 0
+	assert.Len(t, result.Categories, 4)
+	assert.Equal(t, response.ReportCategoryTotal{Category: "Salary", Type: "income", Amount: 5000000, PreviousAmount: 4000000}, result.Categories[2])
+	assert.Equal(t, response.ReportCategoryTotal{Category: "Gift", Type: "income", PreviousAmount: 250000}, result.Categories[3])
+
+	assert.Equal(t, "Dec 2024", result.Comparison.PreviousLabel)
+	assert.Equal(t, 25.0, *result.Comparison.IncomeChange)
+	assert.Nil(t, result.Comparison.ExpenseChange)
+	assert.True(t, result.Budgets[0].Overspent)
+	assert.Equal(t, "USD", result.TopExpenses[0].Currency)
+
+	_, err = reportService.GetMonthlyReport(userID, "01-2025")
+	assert.EqualError(t, err, "invalid month format, use YYYY-MM")
+}
+
+func TestDeliverScheduledReports(t *testing.T) {
+	db, mock := setupTestDB(t)
+	recorder := &recordingNotifier{}
+	reportService := service.NewReportService(db, recorder)
+	userID := uint(1)
+	now := time.Now().UTC()
+	period := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	sent := period.AddDate(0, -1, 0)
+
+	mock.ExpectQuery("SELECT (.+) FROM `report_schedules` WHERE enabled = \\?").
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "format", "day_of_month", "enabled", "last_period", "created_at", "updated_at"}).
+			AddRow(1, userID, "pdf", 1, true, sent, now, now).
+			AddRow(2, 2, "xlsx", 1, true, period, now, now))
+
+	expectUserPreferences(mock, userID, "UTC")
+	expectMonthlyReportQueries(mock, userID, period)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `report_schedules` SET `last_period`=\\?,`updated_at`=\\? WHERE `id` = \\?").
+		WithArgs(period, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// laporan user 2 untuk periode ini sudah terkirim sehingga tidak dikirim lagi
+	expectUserPreferences(mock, 2, "UTC")
+
+	err := reportService.DeliverScheduledReports(context.Background())
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []uint{userID}, recorder.userIDs)
+	assert.Len(t, recorder.messages[0].Attachments, 1)
+
+	attachment := recorder.messages[0].Attachments[0]
+	assert.Equal(t, "report_"+period.Format("2006-01")+".pdf", attachment.Filename)
+	assert.Equal(t, "application/pdf", attachment.ContentType)
+	assert.True(t, bytes.HasPrefix(attachment.Data, []byte("%PDF-")))
+}
+
+func TestSaveReportSchedule(t *testing.T) {
+	db, mock := setupTestDB(t)
+	reportService := service.NewReportService(db, nil)
+	userID := uint(1)
+	now := time.Now()
+	disabled := false
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `report_schedules` (.+) ON DUPLICATE KEY UPDATE").
+		WithArgs(userID, "xlsx", 1, false, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), 1, false, "xlsx", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT (.+) FROM `report_schedules` WHERE user_id = \\?").
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "format", "day_of_month", "enabled", "last_period", "created_at", "updated_at"}).
+			AddRow(3, userID, "xlsx", 1, false, nil, now, now))
+
+	result, err := reportService.SaveReportSchedule(userID, request.ReportScheduleRequest{Format: "xlsx", Enabled: &disabled})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, 1, result.DayOfMonth)
+	assert.False(t, result.Enabled)
+}
@@ -0,0 +1,146 @@
+package unit
+
+import (
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectSavingsGoal(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	startDate := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	targetDate := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	// 3 juta dalam 90 hari terakhir = 1 juta per bulan, sisa 6 juta butuh 180 hari
+	projection := utility.ProjectSavingsGoal(10000000, 4000000, 3000000, startDate, &targetDate, now)
+
+	assert.Equal(t, float64(6000000), projection.Remaining)
+	assert.Equal(t, float64(40), projection.ProgressPercentage)
+	assert.False(t, projection.Completed)
+	assert.Equal(t, float64(1000000), projection.MonthlyRate)
+	assert.Equal(t, time.Date(2025, 8, 28, 0, 0, 0, 0, time.UTC), *projection.ProjectedCompletionDate)
+	assert.True(t, *projection.OnTrack)
+	assert.Greater(t, projection.RequiredMonthly, float64(0))
+	assert.Less(t, projection.RequiredMonthly, projection.MonthlyRate)
+}
+
+func TestProjectSavingsGoal_NoContributions(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	targetDate := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	projection := utility.ProjectSavingsGoal(5000000, 0, 0, now, &targetDate, now)
+
+	assert.Nil(t, projection.ProjectedCompletionDate)
+	assert.False(t, *projection.OnTrack)
+	// kurang dari sebulan tersisa, seluruh sisa harus disetor
+	assert.Equal(t, float64(5000000), projection.RequiredMonthly)
+}
+
+func TestProjectSavingsGoal_Completed(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	projection := utility.ProjectSavingsGoal(5000000, 5500000, 500000, now.AddDate(0, -2, 0), nil, now)
+
+	assert.True(t, projection.Completed)
+	assert.Equal(t, float64(0), projection.Remaining)
+	assert.Equal(t, float64(100), projection.ProgressPercentage)
+	assert.Nil(t, projection.ProjectedCompletionDate)
+	assert.Nil(t, projection.OnTrack)
+}
+
+func TestGetSavingsGoals(t *testing.T) {
+	db, mock := setupTestDB(t)
+	savingsGoalService := service.NewSavingsGoalService(db)
+	userID := uint(1)
+	now := time.Now()
+	accountID := uint(3)
+
+	mock.ExpectQuery("SELECT \\* FROM `savings_goals` WHERE user_id = \\? AND `savings_goals`.`deleted_at` IS NULL ORDER BY id ASC").
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name", "target_amount", "target_date", "start_date", "account_id", "category_id"}).
+			AddRow(1, now, now, nil, userID, "Dana Darurat", 30000000, nil, now.AddDate(0, -6, 0), accountID, nil).
+			AddRow(2, now, now, nil, userID, "Liburan", 8000000, nil, now.AddDate(0, -1, 0), nil, nil))
+	expectUserPreferences(mock, userID, "Asia/Jakarta")
+	mock.ExpectQuery("SELECT goal_flows.goal_id, (.+) FROM \\(SELECT savings_goals.id AS goal_id, savings_contributions.amount, (.+) UNION ALL (.+)\\) AS goal_flows GROUP BY `goal_flows`.`goal_id`").
+		WillReturnRows(sqlmock.NewRows([]string{"goal_id", "total", "recent"}).
+			AddRow(1, 12000000, 4500000))
+
+	result, err := savingsGoalService.GetSavingsGoals(userID)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, float64(12000000), result[0].SavedAmount)
+	assert.Equal(t, float64(18000000), result[0].RemainingAmount)
+	assert.Equal(t, float64(1500000), result[0].MonthlyContributionRate)
+	assert.NotNil(t, result[0].ProjectedCompletionDate)
+	assert.Equal(t, &accountID, result[0].AccountID)
+	// goal tanpa dana sama sekali tidak punya proyeksi
+	assert.Equal(t, float64(0), result[1].SavedAmount)
+	assert.Nil(t, result[1].ProjectedCompletionDate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateSavingsGoal_AccountAndCategory(t *testing.T) {
+	db, mock := setupTestDB(t)
+	savingsGoalService := service.NewSavingsGoalService(db)
+	accountID, categoryID := uint(3), uint(4)
+
+	expectUserPreferences(mock, 1, "UTC")
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `accounts`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `categories`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	_, err := savingsGoalService.CreateSavingsGoal(1, request.SavingsGoalRequest{
+		Name:         "Rumah",
+		TargetAmount: 100000000,
+		AccountID:    &accountID,
+		CategoryID:   &categoryID,
+	})
+
+	assert.EqualError(t, err, "savings goal can be linked to an account or a category, not both")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddContribution(t *testing.T) {
+	db, mock := setupTestDB(t)
+	savingsGoalService := service.NewSavingsGoalService(db)
+	userID := uint(1)
+	now := time.Now()
+	goalRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name", "target_amount", "target_date", "start_date"}).
+			AddRow(2, now, now, nil, userID, "Liburan", 8000000, nil, now.AddDate(0, -1, 0))
+	}
+
+	mock.ExpectQuery("SELECT \\* FROM `savings_goals` WHERE \\(id = \\? AND user_id = \\?\\)").
+		WithArgs(2, userID, 1).
+		WillReturnRows(goalRows())
+	expectUserPreferences(mock, userID, "Asia/Jakarta")
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `savings_contributions` \\(`goal_id`,`amount`,`date`,`note`,`created_at`\\)").
+		WithArgs(2, 500000.0, time.Date(2025, 2, 14, 0, 0, 0, 0, time.UTC), "bonus", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT \\* FROM `savings_goals` WHERE \\(id = \\? AND user_id = \\?\\)").
+		WithArgs(2, userID, 1).
+		WillReturnRows(goalRows())
+	expectUserPreferences(mock, userID, "Asia/Jakarta")
+	mock.ExpectQuery("AS goal_flows").
+		WillReturnRows(sqlmock.NewRows([]string{"goal_id", "total", "recent"}).AddRow(2, 500000, 500000))
+
+	result, err := savingsGoalService.AddContribution(userID, 2, request.SavingsContributionRequest{
+		Amount: 500000,
+		Date:   "2025-02-14",
+		Note:   " bonus ",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(500000), result.SavedAmount)
+	assert.Equal(t, float64(6.25), result.ProgressPercentage)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,99 @@
+package unit
+
+import (
+	"context"
+	"go-electroshop/internal/notifier"
+	"go-electroshop/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingNotifier struct {
+	userIDs  []uint
+	messages []notifier.Message
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, userID uint, msg notifier.Message) error {
+	n.userIDs = append(n.userIDs, userID)
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+func productRowsWithStock(stock int) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "category", "name", "price", "stock"}).
+		AddRow(1, now, now, nil, "Xiaomi", "Xiaomi Redmi Note 11 Pro", 3200000.0, stock)
+}
+
+func TestNotifyRestock_BatchSizeFollowsStock(t *testing.T) {
+	db, mock := setupTestDB(t)
+	recorder := &recordingNotifier{}
+	stockService := service.NewStockNotificationService(db, recorder)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `products` (.+) FOR UPDATE").
+		WithArgs(uint(1), 1).
+		WillReturnRows(productRowsWithStock(2))
+	mock.ExpectQuery("SELECT MAX\\(notified_at\\) FROM `stock_subscriptions`").
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil))
+	// 2 unit stok -> maksimal 6 subscriber terlama
+	mock.ExpectQuery("SELECT (.+) FROM `stock_subscriptions` WHERE product_id = \\? AND notified_at IS NULL ORDER BY created_at ASC, id ASC LIMIT \\?").
+		WithArgs(uint(1), 6).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "product_id", "created_at", "notified_at"}).
+			AddRow(10, 7, 1, now.Add(-2*time.Hour), nil).
+			AddRow(11, 8, 1, now.Add(-time.Hour), nil))
+	mock.ExpectExec("UPDATE `stock_subscriptions` SET `notified_at`=\\? WHERE id IN \\(\\?,\\?\\)").
+		WithArgs(sqlmock.AnyArg(), uint(10), uint(11)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := stockService.NotifyRestock(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{7, 8}, recorder.userIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotifyRestock_RespectsBatchInterval(t *testing.T) {
+	db, mock := setupTestDB(t)
+	recorder := &recordingNotifier{}
+	stockService := service.NewStockNotificationService(db, recorder)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `products` (.+) FOR UPDATE").
+		WithArgs(uint(1), 1).
+		WillReturnRows(productRowsWithStock(5))
+	mock.ExpectQuery("SELECT MAX\\(notified_at\\) FROM `stock_subscriptions`").
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(time.Now().Add(-5 * time.Minute)))
+	mock.ExpectCommit()
+
+	err := stockService.NotifyRestock(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Empty(t, recorder.userIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubscribe_UntrackedStockIsInStock(t *testing.T) {
+	db, mock := setupTestDB(t)
+	stockService := service.NewStockNotificationService(db, &recordingNotifier{})
+	now := time.Now()
+
+	// produk lama yang stoknya belum pernah diisi
+	mock.ExpectQuery("SELECT (.+) FROM `products` WHERE `products`.`id` = \\?").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "category", "name", "price", "stock"}).
+			AddRow(1, now, now, nil, "Xiaomi", "Xiaomi Redmi Note 11 Pro", 3200000.0, nil))
+
+	result, err := stockService.Subscribe(2, 1)
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "product is still in stock")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
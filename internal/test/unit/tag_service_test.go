@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTransaction_WithTags(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+	now := time.Now()
+
+	expectPersonalLedger(mock, userID, 4)
+	mock.ExpectQuery("SELECT (.+) FROM `categories` WHERE \\(id = \\? AND ledger_id = \\?\\)").
+		WithArgs(2, 4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name"}).
+			AddRow(2, now, now, nil, userID, "Transport"))
+	expectUserPreferences(mock, userID, "UTC")
+	mock.ExpectBegin()
+	// nama tag dinormalisasi dan duplikat dibuang sebelum disimpan
+	mock.ExpectExec("INSERT INTO `tags` \\(`user_id`,`name`,`created_at`\\) VALUES \\(\\?,\\?,\\?\\),\\(\\?,\\?,\\?\\)").
+		WithArgs(userID, "trip-bali", sqlmock.AnyArg(), userID, "work-reimbursable", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT \\* FROM `tags` WHERE user_id = \\? AND name IN \\(\\?,\\?\\)").
+		WithArgs(userID, "trip-bali", "work-reimbursable").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).
+			AddRow(4, userID, "trip-bali").
+			AddRow(5, userID, "work-reimbursable"))
+	mock.ExpectExec("INSERT INTO `transactions`").
+		WillReturnResult(sqlmock.NewResult(20, 1))
+	mock.ExpectExec("INSERT INTO `transaction_tags` \\(`transaction_id`,`tag_id`\\)").
+		WithArgs(20, 4, 20, 5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	result, err := transactionService.CreateTransaction(userID, request.CreateTransactionRequest{
+		CategoryID: 2,
+		Amount:     450000,
+		Type:       "expense",
+		Date:       "2025-01-29",
+		Tags:       []string{"Trip Bali", "work_reimbursable", "#trip-bali"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"trip-bali", "work-reimbursable"}, result.Tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTransactionByUser_TagFilter(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `transactions` WHERE user_id = \\? AND id IN \\(SELECT transaction_tags.transaction_id FROM `transaction_tags` "+
+		"JOIN tags ON tags.id = transaction_tags.tag_id WHERE tags.name IN \\(\\?,\\?\\) GROUP BY `transaction_tags`.`transaction_id` HAVING COUNT\\(DISTINCT tags.id\\) = \\?\\)").
+		WithArgs(userID, "trip-bali", "food", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(transactions.amount \\* (.+)\\), 0\\) FROM `transactions`").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(0))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(transactions.amount \\* (.+)\\), 0\\) FROM `transactions`").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(0))
+	mock.ExpectQuery("SELECT \\* FROM `transactions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	result, err := transactionService.GetTransactionByUser(userID, request.TransactionFilter{Tags: " Trip-Bali, food ,", Page: 1, Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Transactions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTagSummary(t *testing.T) {
+	db, mock := setupTestDB(t)
+	tagService := service.NewTagService(db)
+	userID := uint(1)
+
+	mock.ExpectQuery("SELECT tags.id AS tag_id, (.+) FROM `tags` LEFT JOIN transaction_tags (.+) LEFT JOIN transactions ON (.+) AND transactions.date >= \\? WHERE tags.user_id = \\? GROUP BY tags.id, tags.name").
+		WithArgs(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), userID).
+		WillReturnRows(sqlmock.NewRows([]string{"tag_id", "tag", "transaction_count", "total_income", "total_expense"}).
+			AddRow(4, "trip-bali", 6, 0.0, 3500000.0).
+			AddRow(5, "work-reimbursable", 2, 750000.0, 900000.0))
+
+	result, err := tagService.GetTagSummary(userID, request.TagSummaryFilter{StartDate: "2025-01-01"})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "trip-bali", result[0].Tag)
+	assert.Equal(t, float64(-3500000), result[0].Balance)
+	assert.Equal(t, float64(-150000), result[1].Balance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,136 @@
+package unit
+
+import (
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkUpdateTransactions_Recategorize(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `categories` WHERE \\(id = \\? AND ledger_id IN \\(SELECT `ledger_id` FROM `ledger_members` WHERE user_id = \\? AND role IN \\(\\?,\\?\\)\\)\\)").
+		WithArgs(9, userID, "owner", "editor").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id` FROM `transactions` WHERE ledger_id IN \\(SELECT `ledger_id` FROM `ledger_members` WHERE user_id = \\? AND role IN \\(\\?,\\?\\)\\) AND id IN \\(\\?,\\?,\\?\\) AND `transactions`.`deleted_at` IS NULL ORDER BY id LIMIT \\?").
+		WithArgs(userID, "owner", "editor", 3, 4, 5, 5001).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(4).AddRow(5))
+	mock.ExpectQuery("SELECT DISTINCT `transaction_id` FROM `transaction_splits` WHERE transaction_id IN \\(\\?,\\?,\\?\\)").
+		WithArgs(3, 4, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"transaction_id"}).AddRow(4))
+	// transaksi ikut pindah ke ledger kategori barunya
+	mock.ExpectExec("UPDATE `transactions` SET `category_id`=\\?,`ledger_id`=\\(SELECT `ledger_id` FROM `categories` WHERE id = \\? (.+)\\),`updated_at`=\\? WHERE id IN \\(\\?,\\?,\\?\\) AND id NOT IN \\(\\?\\)").
+		WithArgs(9, 9, sqlmock.AnyArg(), 3, 4, 5, 4).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	result, err := transactionService.BulkUpdateTransactions(userID, request.BulkTransactionRequest{
+		Action:     request.BulkActionRecategorize,
+		IDs:        []uint{3, 4, 5},
+		CategoryID: 9,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(3), result.Matched)
+	assert.Equal(t, int64(2), result.Updated)
+	assert.Equal(t, int64(1), result.Skipped)
+}
+
+func TestBulkUpdateTransactions_RejectsForeignIDs(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+
+	// transaksi 8 ada di ledger yang tidak bisa diubah user sehingga tidak ikut terpilih, semua perubahan dibatalkan
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id` FROM `transactions` WHERE ledger_id IN \\(SELECT `ledger_id` FROM `ledger_members` WHERE user_id = \\? AND role IN \\(\\?,\\?\\)\\) AND id IN \\(\\?,\\?\\)").
+		WithArgs(userID, "owner", "editor", 3, 8, 5001).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectRollback()
+
+	result, err := transactionService.BulkUpdateTransactions(userID, request.BulkTransactionRequest{
+		Action: request.BulkActionDelete,
+		IDs:    []uint{3, 8},
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrBulkTransactionsNotFound)
+	assert.EqualError(t, err, "transactions not found: 8")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBulkUpdateTransactions_AddTagsByFilter(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id` FROM `transactions` WHERE ledger_id IN \\(SELECT `ledger_id` FROM `ledger_members` WHERE user_id = \\? AND role IN \\(\\?,\\?\\)\\) AND user_id = \\? AND type = \\?").
+		WithArgs(userID, "owner", "editor", userID, "expense", 5001).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(5))
+	mock.ExpectExec("INSERT INTO `tags`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT \\* FROM `tags` WHERE user_id = \\? AND name IN \\(\\?\\)").
+		WithArgs(userID, "import-cleanup").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(2, userID, "import-cleanup"))
+	mock.ExpectExec("INSERT INTO `transaction_tags` \\(`tag_id`,`transaction_id`\\) VALUES \\(\\?,\\?\\),\\(\\?,\\?\\) ON DUPLICATE KEY UPDATE").
+		WithArgs(2, 3, 2, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := transactionService.BulkUpdateTransactions(userID, request.BulkTransactionRequest{
+		Action: request.BulkActionAddTags,
+		Filter: &request.TransactionFilter{Type: "expense"},
+		Tags:   []string{"Import Cleanup"},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(2), result.Matched)
+	// transaksi 3 sudah punya tag tersebut
+	assert.Equal(t, int64(1), result.Updated)
+}
+
+func TestBulkUpdateTransactions_Validation(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+
+	_, err := transactionService.BulkUpdateTransactions(1, request.BulkTransactionRequest{
+		Action: request.BulkActionDelete,
+		IDs:    []uint{3},
+		Filter: &request.TransactionFilter{},
+	})
+	assert.EqualError(t, err, "either ids or filter is required")
+
+	_, err = transactionService.BulkUpdateTransactions(1, request.BulkTransactionRequest{
+		Action: request.BulkActionShiftDate,
+		IDs:    []uint{3},
+	})
+	assert.EqualError(t, err, "days is required")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBulkUpdateTransactions_ViewerLedgerFilter(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+
+	mock.ExpectBegin()
+	expectLedgerRole(mock, 2, 7, "viewer")
+	mock.ExpectRollback()
+
+	result, err := transactionService.BulkUpdateTransactions(2, request.BulkTransactionRequest{
+		Action: request.BulkActionDelete,
+		Filter: &request.TransactionFilter{LedgerID: 7},
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrLedgerReadOnly)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,151 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/payload/response"
+	"go-electroshop/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func expectExportSummary(mock sqlmock.Sqlmock, income, expense float64, filterArgs ...driver.Value) {
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(transactions.amount \\* (.+)\\), 0\\) FROM `transactions` WHERE user_id = \\? (.+)type = \\?").
+		WithArgs(append(filterArgs, "income")...).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(income))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(transactions.amount \\* (.+)\\), 0\\) FROM `transactions` WHERE user_id = \\? (.+)type = \\?").
+		WithArgs(append(filterArgs, "expense")...).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(expense))
+}
+
+// expectExportPreloads mengharapkan preload satu batch export, account kosong sehingga tidak di-query.
+// Semua transaksi berkategori Food, kategori split hanya dimuat jika ada split.
+func expectExportPreloads(mock sqlmock.Sqlmock, splits *sqlmock.Rows, hasSplits bool) {
+	mock.ExpectQuery("SELECT \\* FROM `transaction_attachments`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "file_name", "content_type", "size"}))
+	mock.ExpectQuery("SELECT \\* FROM `categories`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(1, 1, "Food"))
+	mock.ExpectQuery("SELECT \\* FROM `transaction_splits`").
+		WillReturnRows(splits)
+	if hasSplits {
+		mock.ExpectQuery("SELECT \\* FROM `categories`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(1, 1, "Food").AddRow(2, 1, "Household"))
+	}
+	mock.ExpectQuery("SELECT \\* FROM `transaction_tags`").
+		WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "tag_id"}))
+}
+
+func exportTransactionRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "category_id", "amount", "currency", "type", "description", "date"})
+}
+
+func TestExportTransactions_CSVStreamsAllBatches(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+	now := time.Now()
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	expectExportSummary(mock, 0, 50100, userID)
+
+	// batch pertama penuh (500 baris) sehingga batch berikutnya dimuat mulai setelah baris terakhir
+	firstBatch := exportTransactionRows()
+	for id := 501; id >= 2; id-- {
+		firstBatch.AddRow(id, now, now, nil, userID, 1, 100.0, "IDR", "expense", "Lunch", date)
+	}
+	mock.ExpectQuery("SELECT \\* FROM `transactions` WHERE user_id = \\? AND `transactions`.`deleted_at` IS NULL ORDER BY date DESC, id DESC LIMIT \\?").
+		WithArgs(userID, 500).
+		WillReturnRows(firstBatch)
+	expectExportPreloads(mock, sqlmock.NewRows([]string{"id", "transaction_id", "category_id", "amount", "description"}), false)
+
+	mock.ExpectQuery("SELECT \\* FROM `transactions` WHERE user_id = \\? AND \\(date < \\? OR \\(date = \\? AND id < \\?\\)\\) AND `transactions`.`deleted_at` IS NULL ORDER BY date DESC, id DESC LIMIT \\?").
+		WithArgs(userID, date, date, 2, 500).
+		WillReturnRows(exportTransactionRows().AddRow(1, now, now, nil, userID, 1, 100.0, "IDR", "expense", "Groceries", date))
+	expectExportPreloads(mock, sqlmock.NewRows([]string{"id", "transaction_id", "category_id", "amount", "description"}).
+		AddRow(1, 1, 1, 60.0, "").
+		AddRow(2, 1, 2, 40.0, "Soap"), true)
+
+	var buffer bytes.Buffer
+	err := transactionService.ExportTransactions(context.Background(), userID, request.TransactionExportFilter{Format: "csv"}, &buffer)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	assert.NoError(t, err)
+	// header + 500 transaksi + 2 baris split transaksi terakhir
+	assert.Len(t, records, 503)
+	assert.Equal(t, []string{"Date", "Type", "Category", "Amount", "Currency", "Account", "Description", "Tags", "Attachments"}, records[0])
+	assert.Equal(t, []string{"2025-01-15", "expense", "Food", "100.00", "IDR", "", "Lunch", "", "0"}, records[1])
+	assert.Equal(t, []string{"2025-01-15", "expense", "Household", "40.00", "IDR", "", "Groceries (Soap)", "", "0"}, records[502])
+}
+
+func TestExportTransactions_JSONAndXLSX(t *testing.T) {
+	userID := uint(1)
+	now := time.Now()
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	run := func(format string) *bytes.Buffer {
+		db, mock := setupTestDB(t)
+		transactionService := service.NewTransactionService(db)
+
+		expectExportSummary(mock, 250, 100, userID, "expense")
+		mock.ExpectQuery("SELECT \\* FROM `transactions` WHERE user_id = \\? AND type = \\? AND `transactions`.`deleted_at` IS NULL ORDER BY date DESC, id DESC LIMIT \\?").
+			WithArgs(userID, "expense", 500).
+			WillReturnRows(exportTransactionRows().AddRow(1, now, now, nil, userID, 1, 100.0, "IDR", "expense", "Lunch", date))
+		expectExportPreloads(mock, sqlmock.NewRows([]string{"id", "transaction_id", "category_id", "amount", "description"}), false)
+
+		var buffer bytes.Buffer
+		filter := request.TransactionExportFilter{TransactionFilter: request.TransactionFilter{Type: "expense"}, Format: format}
+		assert.NoError(t, transactionService.ExportTransactions(context.Background(), userID, filter, &buffer))
+		assert.NoError(t, mock.ExpectationsWereMet())
+		return &buffer
+	}
+
+	var result struct {
+		Transactions []response.TransactionResponse `json:"transactions"`
+		Summary      response.TransactionSummary    `json:"summary"`
+	}
+	assert.NoError(t, json.Unmarshal(run("json").Bytes(), &result))
+	assert.Len(t, result.Transactions, 1)
+	assert.Equal(t, "Food", result.Transactions[0].Category)
+	assert.Equal(t, 150.0, result.Summary.Balance)
+
+	f, err := excelize.OpenReader(run("xlsx"))
+	assert.NoError(t, err)
+	defer f.Close()
+
+	rows, err := f.GetRows("Transactions")
+	assert.NoError(t, err)
+	assert.Equal(t, "Lunch", rows[1][6])
+	// summary ditulis dua baris setelah data terakhir
+	assert.Equal(t, []string{"Summary", "Total Pemasukan", "250.00"}, rows[4])
+	assert.Equal(t, []string{"", "Saldo", "150.00"}, rows[6])
+}
+
+func TestExportTransactions_XLSXQueryFailure(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+
+	expectExportSummary(mock, 0, 0, userID)
+	mock.ExpectQuery("SELECT \\* FROM `transactions` WHERE user_id = \\?").
+		WillReturnError(errors.New("connection reset"))
+
+	// writer xlsx dibersihkan tanpa menulis workbook yang belum lengkap
+	var buffer bytes.Buffer
+	err := transactionService.ExportTransactions(context.Background(), userID, request.TransactionExportFilter{Format: "xlsx"}, &buffer)
+
+	assert.EqualError(t, err, "failed to export transactions")
+	assert.Zero(t, buffer.Len())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
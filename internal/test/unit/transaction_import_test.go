@@ -0,0 +1,250 @@
+package unit
+
+import (
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCSV_WithMapping(t *testing.T) {
+	csv := "\ufeffTanggal;Keterangan;Nominal;Jenis\n" +
+		"29/01/2025;Gaji Januari;10.000.000,00;CR\n" +
+		"30/01/2025;Makan siang;-45.500,50;\n" +
+		"31/13/2025;Salah tanggal;1.000;DB\n"
+
+	rows, err := utility.ParseCSV(strings.NewReader(csv), utility.CSVMapping{
+		DateColumn:        "tanggal",
+		AmountColumn:      "Nominal",
+		DescriptionColumn: "Keterangan",
+		TypeColumn:        "Jenis",
+		DateFormat:        "DD/MM/YYYY",
+		DecimalSeparator:  ",",
+		Delimiter:         ";",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC), rows[0].Date)
+	assert.Equal(t, 10000000.0, rows[0].Amount)
+	assert.Equal(t, "income", rows[0].Type)
+	assert.Equal(t, 45500.5, rows[1].Amount)
+	assert.Equal(t, "expense", rows[1].Type)
+	assert.NotEmpty(t, rows[2].Error)
+}
+
+func TestParseCSV_UnknownColumn(t *testing.T) {
+	_, err := utility.ParseCSV(strings.NewReader("date,amount\n2025-01-29,10\n"), utility.CSVMapping{
+		DateColumn:   "date",
+		AmountColumn: "total",
+	})
+
+	assert.Error(t, err)
+}
+
+func TestParseOFX(t *testing.T) {
+	ofx := `OFXHEADER:100
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20250129120000[+7:WIB]<TRNAMT>-150000.00<NAME>Indomaret<MEMO>Belanja &amp; snack
+</STMTTRN>
+<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20250130<TRNAMT>2500000<NAME>Transfer masuk</STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`
+
+	rows, err := utility.ParseOFX(strings.NewReader(ofx))
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC), rows[0].Date)
+	assert.Equal(t, 150000.0, rows[0].Amount)
+	assert.Equal(t, "expense", rows[0].Type)
+	assert.Equal(t, "Indomaret - Belanja & snack", rows[0].Description)
+	assert.Equal(t, "income", rows[1].Type)
+}
+
+func TestParseQIF(t *testing.T) {
+	qif := "!Type:Bank\nD01/29/2025\nT-45,000.00\nPWarung Padang\n^\nD1/30'25\nU1,500,000.00\nPBonus\nMProject\n^\n"
+
+	rows, err := utility.ParseQIF(strings.NewReader(qif))
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, 45000.0, rows[0].Amount)
+	assert.Equal(t, "expense", rows[0].Type)
+	assert.Equal(t, time.Date(2025, 1, 30, 0, 0, 0, 0, time.UTC), rows[1].Date)
+	assert.Equal(t, "Bonus - Project", rows[1].Description)
+	assert.Equal(t, "income", rows[1].Type)
+}
+
+func TestParseAmount(t *testing.T) {
+	cases := map[string]float64{
+		"1,234.50":  1234.5,
+		"(250.00)":  -250,
+		"Rp 10.000": 10000,
+		"-75":       -75,
+	}
+	for value, expected := range cases {
+		separator := "."
+		if value == "Rp 10.000" {
+			separator = ","
+		}
+		amount, err := utility.ParseAmount(value, separator)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, amount, value)
+	}
+
+	_, err := utility.ParseAmount("abc", ".")
+	assert.Error(t, err)
+}
+
+func TestPreviewImport_MarksDuplicates(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+
+	mock.ExpectQuery("SELECT (.+) FROM `category_rules`").
+		WillReturnRows(categoryRuleRows().
+			AddRow(1, time.Now(), time.Now(), nil, userID, 5, "", 0, "bensin", "", nil, nil, ""))
+	mock.ExpectQuery("SELECT `id`,`date`,`amount`,`description` FROM `transactions` WHERE \\(user_id = \\? AND date >= \\? AND date < \\?\\)").
+		WithArgs(userID, time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date", "amount", "description"}).
+			AddRow(7, time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC), 45000.0, "warung padang "))
+
+	csv := "date,description,amount\n2025-01-29,Warung Padang,-45000\n2025-01-30,Bensin,-20000\n"
+	result, err := transactionService.PreviewImport(userID, strings.NewReader(csv), request.ImportPreviewRequest{
+		Format:            "csv",
+		DateColumn:        "date",
+		AmountColumn:      "amount",
+		DescriptionColumn: "description",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.TotalRows)
+	assert.Equal(t, 1, result.Duplicates)
+	assert.True(t, result.Rows[0].Duplicate)
+	assert.Equal(t, uint(7), result.Rows[0].DuplicateTransactionID)
+	assert.False(t, result.Rows[1].Duplicate)
+	assert.Equal(t, uint(5), result.Rows[1].CategoryID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreviewImport_MarksRepeatedRowsInFile(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM `category_rules`").
+		WillReturnRows(categoryRuleRows())
+	mock.ExpectQuery("SELECT `id`,`date`,`amount`,`description` FROM `transactions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date", "amount", "description"}))
+
+	csv := "date,description,amount\n2025-01-29,Kopi,-25000\n2025-01-29,Kopi,-25000\n2025-01-29,Kopi,-30000\n"
+	result, err := transactionService.PreviewImport(1, strings.NewReader(csv), request.ImportPreviewRequest{
+		Format:            "csv",
+		DateColumn:        "date",
+		AmountColumn:      "amount",
+		DescriptionColumn: "description",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Duplicates)
+	assert.False(t, result.Rows[0].Duplicate)
+	assert.True(t, result.Rows[1].Duplicate)
+	assert.Equal(t, 0, *result.Rows[1].DuplicateOfIndex)
+	assert.False(t, result.Rows[2].Duplicate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommitImport_SkipsRepeatedRows(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+	date := time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC)
+
+	expectUserPreferences(mock, userID, "UTC")
+	mock.ExpectQuery("SELECT (.+) FROM `category_rules`").
+		WillReturnRows(categoryRuleRows())
+	mock.ExpectQuery("SELECT `id`,`ledger_id` FROM `categories`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ledger_id"}).AddRow(5, 4))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id`,`date`,`amount`,`description` FROM `transactions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date", "amount", "description"}))
+	// baris kedua kembar dengan baris pertama sehingga hanya dua transaksi yang dibuat
+	mock.ExpectExec("INSERT INTO `transactions` (.+) VALUES \\((.+)\\),\\((.+)\\)$").
+		WithArgs(
+			sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(4), uint(5), nil, 25000.0, "IDR", "expense", "Kopi", date,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(4), uint(5), nil, 30000.0, "IDR", "expense", "Kopi", date,
+		).
+		WillReturnResult(sqlmock.NewResult(10, 2))
+	mock.ExpectCommit()
+
+	result, err := transactionService.CommitImport(userID, request.ImportCommitRequest{
+		DefaultCategoryID: 5,
+		Rows: []request.ImportRow{
+			{Index: 0, Date: "2025-01-29", Amount: 25000, Type: "expense", Description: "Kopi"},
+			{Index: 1, Date: "2025-01-29", Amount: 25000, Type: "expense", Description: "Kopi"},
+			{Index: 2, Date: "2025-01-29", Amount: 30000, Type: "expense", Description: "Kopi"},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 1, result.Duplicates)
+	assert.Equal(t, "duplicate", result.Results[1].Status)
+	assert.Equal(t, result.Results[0].TransactionID, result.Results[1].TransactionID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommitImport_InvalidRowsFailIndividually(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+	date := time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC)
+
+	expectUserPreferences(mock, userID, "UTC")
+	mock.ExpectQuery("SELECT (.+) FROM `category_rules`").
+		WillReturnRows(categoryRuleRows())
+	mock.ExpectQuery("SELECT `id`,`ledger_id` FROM `categories`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ledger_id"}).AddRow(5, 4))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id`,`date`,`amount`,`description` FROM `transactions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date", "amount", "description"}))
+	mock.ExpectExec("INSERT INTO `transactions`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(4), uint(5), nil, 25000.0, "IDR", "expense", "Kopi", date).
+		WillReturnResult(sqlmock.NewResult(10, 1))
+	mock.ExpectCommit()
+
+	// baris dengan error parse dikirim balik apa adanya dari preview
+	result, err := transactionService.CommitImport(userID, request.ImportCommitRequest{
+		DefaultCategoryID: 5,
+		Rows: []request.ImportRow{
+			{Index: 0, Date: "2025-01-29", Amount: 25000, Type: "expense", Description: "Kopi"},
+			{Index: 1, Date: "2025-01-29", Description: "Baris rusak"},
+			{Index: 2, Date: "2025-01-29", Amount: 10000, Type: "transfer", Description: "Kopi"},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, "amount must be greater than 0", result.Results[1].Error)
+	assert.Equal(t, "type must be income or expense", result.Results[2].Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreviewImport_CSVWithoutMapping(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+
+	csv := "Tanggal,Keterangan,Nominal\n29/01/2025,Gaji,1000\n"
+	result, err := transactionService.PreviewImport(1, strings.NewReader(csv), request.ImportPreviewRequest{Format: "csv"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Tanggal", "Keterangan", "Nominal"}, result.Headers)
+	assert.Len(t, result.SampleRows, 1)
+	assert.Empty(t, result.Rows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"database/sql/driver"
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTSQuery(t *testing.T) {
+	assert.Equal(t, "kopi:* & susu:*", utility.SearchTSQuery("  Kopi  Susu! "))
+	// operator tsquery dari input user dibuang
+	assert.Equal(t, "grab:* & car:*", utility.SearchTSQuery("grab & !car:*"))
+	assert.Equal(t, "", utility.SearchTSQuery("&|!()"))
+	assert.Equal(t, "café:*", utility.SearchTSQuery("Café"))
+}
+
+func TestGetTransactionByUser_SearchAndSort(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+	minAmount := 50000.0
+	filter := request.TransactionFilter{Q: "kopi sus", MinAmount: &minAmount, Sort: "amount_desc", Page: 1, Limit: 10}
+
+	search := "\\(\\(to_tsvector\\('simple', coalesce\\(description, ''\\)\\) @@ to_tsquery\\('simple', \\?\\) OR EXISTS \\(SELECT 1 FROM categories (.+)\\) AND amount >= \\?"
+	args := []driver.Value{userID, "kopi:* & sus:*", "kopi:* & sus:*", "kopi:* & sus:*", minAmount}
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `transactions` WHERE user_id = \\? AND " + search).
+		WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	// summary ikut memakai pencarian
+	expectExportSummary(mock, 0, 0, args...)
+	mock.ExpectQuery("SELECT \\* FROM `transactions` WHERE user_id = \\? AND " + search + "(.+) ORDER BY transactions.amount \\* (.+) DESC, date DESC LIMIT \\?").
+		WithArgs(append(args, 10)...).
+		WillReturnRows(exportTransactionRows())
+
+	result, err := transactionService.GetTransactionByUser(userID, filter)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Empty(t, result.Transactions)
+}
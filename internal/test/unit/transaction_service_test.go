@@ -74,13 +74,13 @@ func (suite *TransactionServiceTestSuite) TestGetTransactionByUser() {
 
 	// Mock income query
 	incomeRows := sqlmock.NewRows([]string{"sum"}).AddRow(1000.0)
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(SUM(amount), 0) FROM `transactions` WHERE user_id = ? AND `transactions`.`deleted_at` IS NULL AND type = ?")).
+	suite.mock.ExpectQuery("SELECT COALESCE\\(SUM\\(transactions.amount \\* (.+)\\), 0\\) FROM `transactions` WHERE user_id = \\? AND `transactions`.`deleted_at` IS NULL AND type = \\?").
 		WithArgs(userID, "income").
 		WillReturnRows(incomeRows)
 
 	// Mock expense query
 	expenseRows := sqlmock.NewRows([]string{"sum"}).AddRow(500.0)
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(SUM(amount), 0) FROM `transactions` WHERE user_id = ? AND `transactions`.`deleted_at` IS NULL AND type = ?")).
+	suite.mock.ExpectQuery("SELECT COALESCE\\(SUM\\(transactions.amount \\* (.+)\\), 0\\) FROM `transactions` WHERE user_id = \\? AND `transactions`.`deleted_at` IS NULL AND type = \\?").
 		WithArgs(userID, "expense").
 		WillReturnRows(expenseRows)
 
@@ -97,6 +97,12 @@ func (suite *TransactionServiceTestSuite) TestGetTransactionByUser() {
 		WithArgs(userID, 10).
 		WillReturnRows(transactionRows)
 
+	// Mock preload, GORM menjalankan preload berurutan sesuai nama relasi
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transaction_attachments` WHERE `transaction_attachments`.`transaction_id` IN (?,?) AND `transaction_attachments`.`deleted_at` IS NULL")).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "file_name", "content_type", "size"}).
+			AddRow(1, 2, "struk.jpg", "image/jpeg", 2048))
+
 	// Mock category preload (using IN clause)
 	categoryRows := sqlmock.NewRows([]string{
 		"id", "created_at", "updated_at", "deleted_at",
@@ -109,6 +115,18 @@ func (suite *TransactionServiceTestSuite) TestGetTransactionByUser() {
 		WithArgs(1, 2).
 		WillReturnRows(categoryRows)
 
+	// Mock splits preload, transaksi ini tidak di-split
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transaction_splits` WHERE `transaction_splits`.`transaction_id` IN (?,?)")).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "category_id", "amount", "description"}))
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transaction_tags` WHERE `transaction_tags`.`transaction_id` IN (?,?)")).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "tag_id"}).AddRow(1, 9))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tags` WHERE `tags`.`id` = ?")).
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(9, userID, "work-reimbursable"))
+
 	result, err := suite.service.GetTransactionByUser(userID, filter)
 
 	assert.NoError(suite.T(), err)
@@ -117,6 +135,8 @@ func (suite *TransactionServiceTestSuite) TestGetTransactionByUser() {
 	assert.Equal(suite.T(), float64(1000), result.Summary.TotalIncome)
 	assert.Equal(suite.T(), float64(500), result.Summary.TotalExpense)
 	assert.Equal(suite.T(), float64(500), result.Summary.Balance)
+	assert.Len(suite.T(), result.Transactions[1].Attachments, 1)
+	assert.Equal(suite.T(), []string{"work-reimbursable"}, result.Transactions[0].Tags)
 }
 
 func (suite *TransactionServiceTestSuite) TestCreateTransaction() {
@@ -137,14 +157,19 @@ func (suite *TransactionServiceTestSuite) TestCreateTransaction() {
 		"user_id", "name",
 	}).AddRow(1, now, now, nil, userID, "Salary")
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE `categories`.`id` = ? AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
-		WithArgs(req.CategoryID, 1).
+	// tanpa ledger_id, transaksi dicatat di personal ledger user
+	expectPersonalLedger(suite.mock, userID, 4)
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE (id = ? AND ledger_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
+		WithArgs(req.CategoryID, 4, 1).
 		WillReturnRows(categoryRows)
 
+	// tanpa account, transaksi memakai base currency user
+	expectUserPreferences(suite.mock, userID, "UTC")
+
 	// Mock create transaction
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `transactions` (`created_at`,`updated_at`,`deleted_at`,`user_id`,`category_id`,`amount`,`type`,`description`,`date`) VALUES (?,?,?,?,?,?,?,?,?)")).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, req.CategoryID, req.Amount, req.Type, req.Description, sqlmock.AnyArg()).
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `transactions` (`created_at`,`updated_at`,`deleted_at`,`user_id`,`ledger_id`,`category_id`,`account_id`,`amount`,`currency`,`type`,`description`,`date`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?)")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(4), req.CategoryID, nil, req.Amount, "IDR", req.Type, req.Description, sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mock.ExpectCommit()
 
@@ -154,7 +179,10 @@ func (suite *TransactionServiceTestSuite) TestCreateTransaction() {
 	assert.NotNil(suite.T(), result)
 	assert.Equal(suite.T(), req.Amount, result.Amount)
 	assert.Equal(suite.T(), req.Type, result.Type)
+	assert.Equal(suite.T(), "IDR", result.Currency)
 	assert.Equal(suite.T(), "Salary", result.Category)
+	assert.Equal(suite.T(), uint(4), result.LedgerID)
+	assert.Equal(suite.T(), userID, result.CreatedBy)
 }
 
 func (suite *TransactionServiceTestSuite) TestUpdateTransaction() {
@@ -172,12 +200,13 @@ func (suite *TransactionServiceTestSuite) TestUpdateTransaction() {
 	// Mock get existing transaction
 	txRows := sqlmock.NewRows([]string{
 		"id", "created_at", "updated_at", "deleted_at",
-		"user_id", "category_id", "amount", "type",
+		"user_id", "ledger_id", "category_id", "amount", "type",
 		"description", "date",
-	}).AddRow(transactionID, now, now, nil, userID, 1, 1000.0, "income", "Salary", now)
+	}).AddRow(transactionID, now, now, nil, userID, 4, 1, 1000.0, "income", "Salary", now)
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE (id = ? AND user_id = ?) AND `transactions`.`deleted_at` IS NULL ORDER BY `transactions`.`id` LIMIT ?")).
-		WithArgs(transactionID, userID, 1).
+	// owner dan editor ledger transaksi bisa mengubahnya
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE (id = ? AND ledger_id IN (SELECT `ledger_id` FROM `ledger_members` WHERE user_id = ? AND role IN (?,?))) AND `transactions`.`deleted_at` IS NULL ORDER BY `transactions`.`id` LIMIT ?")).
+		WithArgs(transactionID, userID, "owner", "editor", 1).
 		WillReturnRows(txRows)
 
 	// Mock category check
@@ -186,15 +215,22 @@ func (suite *TransactionServiceTestSuite) TestUpdateTransaction() {
 		"user_id", "name",
 	}).AddRow(1, now, now, nil, userID, "Salary")
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE `categories`.`id` = ? AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
-		WithArgs(req.CategoryID, 1).
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE (id = ? AND ledger_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
+		WithArgs(req.CategoryID, 4, 1).
 		WillReturnRows(categoryRows)
+	expectUserPreferences(suite.mock, userID, "UTC")
 
-	// Mock update
+	// Mock update, split lama dihapus dulu
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE `transactions` SET `created_at`=?,`updated_at`=?,`deleted_at`=?,`user_id`=?,`category_id`=?,`amount`=?,`type`=?,`description`=?,`date`=? WHERE `transactions`.`deleted_at` IS NULL AND `id` = ?")).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, req.CategoryID, req.Amount, req.Type, req.Description, sqlmock.AnyArg(), transactionID).
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `transaction_splits` WHERE transaction_id = ?")).
+		WithArgs(transactionID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE `transactions` SET `created_at`=?,`updated_at`=?,`deleted_at`=?,`user_id`=?,`ledger_id`=?,`category_id`=?,`account_id`=?,`amount`=?,`currency`=?,`type`=?,`description`=?,`date`=? WHERE `transactions`.`deleted_at` IS NULL AND `id` = ?")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(4), req.CategoryID, nil, req.Amount, "IDR", req.Type, req.Description, sqlmock.AnyArg(), transactionID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM transaction_tags WHERE transaction_id = ?")).
+		WithArgs(transactionID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 	suite.mock.ExpectCommit()
 
 	result, err := suite.service.UpdateTransaction(userID, transactionID, req)
@@ -205,13 +241,71 @@ func (suite *TransactionServiceTestSuite) TestUpdateTransaction() {
 	assert.Equal(suite.T(), req.Description, result.Description)
 }
 
+func (suite *TransactionServiceTestSuite) TestCreateTransaction_WithSplits() {
+	userID := uint(1)
+	now := time.Now()
+	req := request.CreateTransactionRequest{
+		Amount:      350000,
+		Type:        "expense",
+		Description: "Supermarket",
+		Date:        "2025-01-29",
+		Splits: []request.TransactionSplitRequest{
+			{CategoryID: 3, Amount: 200000, Description: "Groceries"},
+			{CategoryID: 4, Amount: 150000, Description: "Household"},
+		},
+	}
+
+	expectPersonalLedger(suite.mock, userID, 4)
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE (id IN (?,?) AND ledger_id = ?) AND `categories`.`deleted_at` IS NULL")).
+		WithArgs(3, 4, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).
+			AddRow(3, userID, "Groceries").
+			AddRow(4, userID, "Household"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE (id = ? AND ledger_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
+		WithArgs(3, 4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name"}).
+			AddRow(3, now, now, nil, userID, "Groceries"))
+	expectUserPreferences(suite.mock, userID, "UTC")
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `transactions`")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(4), uint(3), nil, req.Amount, "IDR", req.Type, req.Description, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(10, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `transaction_splits` (`transaction_id`,`category_id`,`amount`,`description`) VALUES (?,?,?,?),(?,?,?,?)")).
+		WithArgs(uint(10), uint(3), 200000.0, "Groceries", uint(10), uint(4), 150000.0, "Household").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	suite.mock.ExpectCommit()
+
+	result, err := suite.service.CreateTransaction(userID, req)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(3), result.CategoryID)
+	assert.Len(suite.T(), result.Splits, 2)
+	assert.Equal(suite.T(), "Household", result.Splits[1].Category)
+	assert.Equal(suite.T(), 150000.0, result.Splits[1].Amount)
+}
+
+func (suite *TransactionServiceTestSuite) TestCreateTransaction_SplitTotalMismatch() {
+	result, err := suite.service.CreateTransaction(1, request.CreateTransactionRequest{
+		Amount: 350000,
+		Type:   "expense",
+		Date:   "2025-01-29",
+		Splits: []request.TransactionSplitRequest{
+			{CategoryID: 3, Amount: 200000},
+			{CategoryID: 4, Amount: 100000},
+		},
+	})
+
+	assert.EqualError(suite.T(), err, "split amounts must add up to the transaction amount")
+	assert.Nil(suite.T(), result)
+}
+
 func (suite *TransactionServiceTestSuite) TestDeleteTransaction() {
 	userID := uint(1)
 	transactionID := uint(1)
 
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE `transactions` SET `deleted_at`=? WHERE (id = ? AND user_id = ?) AND `transactions`.`deleted_at` IS NULL")).
-		WithArgs(sqlmock.AnyArg(), transactionID, userID).
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE `transactions` SET `deleted_at`=? WHERE (id = ? AND ledger_id IN (SELECT `ledger_id` FROM `ledger_members` WHERE user_id = ? AND role IN (?,?))) AND `transactions`.`deleted_at` IS NULL")).
+		WithArgs(sqlmock.AnyArg(), transactionID, userID, "owner", "editor").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mock.ExpectCommit()
 
@@ -229,8 +323,9 @@ func (suite *TransactionServiceTestSuite) TestCreateTransaction_CategoryNotFound
 		Date:        "2025-01-29",
 	}
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE `categories`.`id` = ? AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
-		WithArgs(req.CategoryID, 1).
+	expectPersonalLedger(suite.mock, userID, 4)
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE (id = ? AND ledger_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
+		WithArgs(req.CategoryID, 4, 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	result, err := suite.service.CreateTransaction(userID, req)
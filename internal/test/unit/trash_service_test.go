@@ -0,0 +1,200 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// expectTrashedTransaction: userID adalah owner/editor yang membuka trash, creatorID member
+// yang mencatat transaksi
+func expectTrashedTransaction(mock sqlmock.Sqlmock, userID, creatorID, transactionID uint, accountID interface{}) {
+	now := time.Now()
+	mock.ExpectQuery("SELECT \\* FROM `transactions` WHERE id = \\? AND ledger_id IN \\(SELECT `ledger_id` FROM `ledger_members` WHERE user_id = \\? AND role IN \\(\\?,\\?\\)\\) AND deleted_at IS NOT NULL").
+		WithArgs(transactionID, userID, "owner", "editor", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "ledger_id", "category_id", "account_id", "amount", "currency", "type", "description", "date"}).
+			AddRow(transactionID, now, now, now, creatorID, 4, 1, accountID, 150.0, "IDR", "expense", "Groceries", now))
+	mock.ExpectQuery("SELECT \\* FROM `transaction_splits` WHERE `transaction_splits`.`transaction_id` = \\?").
+		WithArgs(transactionID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "category_id", "amount", "description"}).
+			AddRow(1, transactionID, 1, 100.0, "").
+			AddRow(2, transactionID, 2, 50.0, "Soap"))
+}
+
+func TestRestoreTransaction(t *testing.T) {
+	db, mock := setupTestDB(t)
+	trashService := service.NewTrashService(db, storage.NewLocalStorage(t.TempDir()))
+	userID, transactionID := uint(1), uint(7)
+
+	expectTrashedTransaction(mock, userID, userID, transactionID, 3)
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `categories` WHERE id IN \\(\\?,\\?,\\?\\) AND deleted_at IS NOT NULL").
+		WithArgs(1, 1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `accounts` WHERE \\(id = \\? AND user_id = \\?\\) AND `accounts`.`deleted_at` IS NULL").
+		WithArgs(3, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `transactions` SET `deleted_at`=\\? WHERE id = \\?").
+		WithArgs(nil, transactionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, trashService.RestoreTransaction(userID, transactionID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreTransaction_CategoryInTrash(t *testing.T) {
+	db, mock := setupTestDB(t)
+	trashService := service.NewTrashService(db, storage.NewLocalStorage(t.TempDir()))
+	userID, transactionID := uint(1), uint(7)
+
+	expectTrashedTransaction(mock, userID, userID, transactionID, nil)
+	// kategori split kedua masih di trash
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `categories` WHERE id IN \\(\\?,\\?,\\?\\) AND deleted_at IS NOT NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	err := trashService.RestoreTransaction(userID, transactionID)
+
+	assert.ErrorIs(t, err, service.ErrCategoryInTrash)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreTransaction_DeletedByLedgerEditor(t *testing.T) {
+	db, mock := setupTestDB(t)
+	trashService := service.NewTrashService(db, storage.NewLocalStorage(t.TempDir()))
+	editorID, creatorID, transactionID := uint(2), uint(1), uint(7)
+
+	// editor shared ledger me-restore transaksi yang dicatat member lain
+	expectTrashedTransaction(mock, editorID, creatorID, transactionID, 3)
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `categories` WHERE id IN \\(\\?,\\?,\\?\\) AND deleted_at IS NOT NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	// account tetap dicek terhadap member yang mencatat transaksi
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `accounts` WHERE \\(id = \\? AND user_id = \\?\\)").
+		WithArgs(3, creatorID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `transactions` SET `deleted_at`=\\? WHERE id = \\?").
+		WithArgs(nil, transactionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, trashService.RestoreTransaction(editorID, transactionID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeTransaction_RemovesAttachments(t *testing.T) {
+	db, mock := setupTestDB(t)
+	store := storage.NewLocalStorage(t.TempDir())
+	trashService := service.NewTrashService(db, store)
+	userID, transactionID := uint(1), uint(7)
+	ctx := context.Background()
+	key := "attachments/1/7/receipt.png"
+	now := time.Now()
+
+	assert.NoError(t, store.Put(ctx, key, bytes.NewReader(pngHeader), int64(len(pngHeader)), "image/png"))
+
+	expectTrashedTransaction(mock, userID, userID, transactionID, nil)
+	mock.ExpectQuery("SELECT \\* FROM `transaction_attachments` WHERE transaction_id IN \\(\\?\\)").
+		WithArgs(transactionID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "transaction_id", "user_id", "file_name", "content_type", "size", "storage_key"}).
+			AddRow(4, now, now, nil, transactionID, userID, "receipt.png", "image/png", len(pngHeader), key))
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `transaction_splits` WHERE transaction_id IN \\(\\?\\)").
+		WithArgs(transactionID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM transaction_tags WHERE transaction_id IN \\(\\?\\)").
+		WithArgs(transactionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `transaction_attachments` WHERE transaction_id IN \\(\\?\\)").
+		WithArgs(transactionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `recurring_occurrences` SET `transaction_id`=\\?,`updated_at`=\\? WHERE transaction_id IN \\(\\?\\)").
+		WithArgs(nil, sqlmock.AnyArg(), transactionID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM `insights` WHERE transaction_id IN \\(\\?\\)").
+		WithArgs(transactionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `transactions` WHERE id IN \\(\\?\\)").
+		WithArgs(transactionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, trashService.PurgeTransaction(ctx, userID, transactionID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err := store.Get(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestPurgeTransaction_NotInTrash(t *testing.T) {
+	db, mock := setupTestDB(t)
+	trashService := service.NewTrashService(db, storage.NewLocalStorage(t.TempDir()))
+
+	mock.ExpectQuery("SELECT \\* FROM `transactions` WHERE id = \\? AND ledger_id IN \\(SELECT `ledger_id` FROM `ledger_members` WHERE user_id = \\? AND role IN \\(\\?,\\?\\)\\) AND deleted_at IS NOT NULL").
+		WithArgs(7, 2, "owner", "editor", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err := trashService.PurgeTransaction(context.Background(), 2, 7)
+
+	assert.ErrorIs(t, err, service.ErrTrashedTransactionNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectTrashedCategory(mock sqlmock.Sqlmock, userID, categoryID uint) {
+	now := time.Now()
+	mock.ExpectQuery("SELECT \\* FROM `categories` WHERE id = \\? AND ledger_id IN \\(SELECT `ledger_id` FROM `ledger_members` WHERE user_id = \\? AND role IN \\(\\?,\\?\\)\\) AND deleted_at IS NOT NULL").
+		WithArgs(categoryID, userID, "owner", "editor", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name"}).
+			AddRow(categoryID, now, now, now, userID, "Snacks"))
+}
+
+func TestPurgeCategory(t *testing.T) {
+	db, mock := setupTestDB(t)
+	trashService := service.NewTrashService(db, storage.NewLocalStorage(t.TempDir()))
+	userID, categoryID := uint(1), uint(4)
+
+	expectTrashedCategory(mock, userID, categoryID)
+	for _, table := range []string{"transactions", "transaction_splits", "recurring_transactions", "savings_goals"} {
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `" + table + "` WHERE category_id = \\?$").
+			WithArgs(categoryID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `budgets` WHERE category_id = \\?").
+		WithArgs(categoryID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `category_rules` WHERE category_id = \\?").
+		WithArgs(categoryID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM `categories` WHERE `categories`.`id` = \\?").
+		WithArgs(categoryID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, trashService.PurgeCategory(userID, categoryID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeCategory_StillUsedByTrashedTransaction(t *testing.T) {
+	db, mock := setupTestDB(t)
+	trashService := service.NewTrashService(db, storage.NewLocalStorage(t.TempDir()))
+	userID, categoryID := uint(1), uint(4)
+
+	expectTrashedCategory(mock, userID, categoryID)
+	// transaksi di trash ikut dihitung, tanpa filter deleted_at
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `transactions` WHERE category_id = \\?$").
+		WithArgs(categoryID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	err := trashService.PurgeCategory(userID, categoryID)
+
+	assert.ErrorIs(t, err, service.ErrCategoryInUse)
+	assert.EqualError(t, err, "category is still in use by transactions")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"go-electroshop/internal/payload/request"
+	"go-electroshop/internal/service"
+	"go-electroshop/internal/utility"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// expectUserPreferences menyiapkan query timezone/locale/base currency user yang dijalankan
+// sebelum menghitung tanggal hari ini, batas bulan atau mata uang transaksi
+func expectUserPreferences(mock sqlmock.Sqlmock, userID uint, timezone string) {
+	expectUserPreferencesWithCurrency(mock, userID, timezone, "IDR")
+}
+
+func expectUserPreferencesWithCurrency(mock sqlmock.Sqlmock, userID uint, timezone, baseCurrency string) {
+	mock.ExpectQuery("SELECT `timezone`,`locale`,`base_currency` FROM `users` WHERE id = \\?").
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"timezone", "locale", "base_currency"}).AddRow(timezone, "en", baseCurrency))
+}
+
+func TestParseLocalDate(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Time
+	}{
+		{"plain date", "2025-01-31", time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"23:30 WIB stays in january", "2025-01-31T23:30:00+07:00", time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"utc timestamp of 23:30 WIB", "2025-01-31T16:30:00Z", time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"utc timestamp after midnight WIB", "2025-01-31T17:30:00Z", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, err := utility.ParseLocalDate(tt.value, jakarta)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, date)
+		})
+	}
+
+	_, err := utility.ParseLocalDate("31/01/2025", jakarta)
+	assert.Error(t, err)
+}
+
+func TestLocalDate_MonthBoundary(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	// 1 Februari 06:00 WIB masih 31 Januari di UTC
+	now := time.Date(2025, 1, 31, 23, 0, 0, 0, time.UTC)
+
+	today := utility.LocalDate(now, jakarta)
+	start, end := utility.CurrentBudgetPeriod(today)
+
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), today)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestUpdateUserSettings(t *testing.T) {
+	db, mock := setupTestDB(t)
+	userService := &service.UserService{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `users` SET `base_currency`=\\?,`locale`=\\?,`timezone`=\\?,`updated_at`=\\? WHERE id = \\?").
+		WithArgs("USD", "id", "Asia/Jakarta", sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT `id`,`timezone`,`locale`,`base_currency` FROM `users` WHERE `users`.`id` = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "timezone", "locale", "base_currency"}).AddRow(1, "Asia/Jakarta", "id", "USD"))
+
+	settings, err := userService.UpdateUserSettings(1, request.UserSettingsRequest{Timezone: "Asia/Jakarta", Locale: "id", BaseCurrency: "usd"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Jakarta", settings.Timezone)
+	assert.Equal(t, "USD", settings.BaseCurrency)
+
+	_, err = userService.UpdateUserSettings(1, request.UserSettingsRequest{Timezone: "Asia/Atlantis", Locale: "id"})
+	assert.EqualError(t, err, "unknown timezone: Asia/Atlantis")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTransaction_TimestampUsesUserTimezone(t *testing.T) {
+	db, mock := setupTestDB(t)
+	transactionService := service.NewTransactionService(db)
+	userID := uint(1)
+	now := time.Now()
+
+	expectPersonalLedger(mock, userID, 4)
+	mock.ExpectQuery("SELECT (.+) FROM `categories` WHERE \\(id = \\? AND ledger_id = \\?\\)").
+		WithArgs(2, 4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "user_id", "name"}).
+			AddRow(2, now, now, nil, userID, "Food"))
+	expectUserPreferences(mock, userID, "Asia/Jakarta")
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `transactions`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, uint(4), 2, nil, 85000.0, "IDR", "expense", "Nasi goreng", time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)).
+		WillReturnResult(sqlmock.NewResult(30, 1))
+	mock.ExpectCommit()
+
+	result, err := transactionService.CreateTransaction(userID, request.CreateTransactionRequest{
+		CategoryID:  2,
+		Amount:      85000,
+		Type:        "expense",
+		Description: "Nasi goreng",
+		Date:        "2025-01-31T16:30:00Z",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), result.Date)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
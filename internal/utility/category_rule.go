@@ -0,0 +1,80 @@
+package utility
+
+import (
+	"go-electroshop/internal/payload/entity"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CategoryRuleMatcher mencocokkan transaksi dengan rule kategori milik user.
+// Regex dikompilasi sekali supaya bisa dipakai untuk banyak transaksi sekaligus.
+type CategoryRuleMatcher struct {
+	rules []compiledCategoryRule
+}
+
+type compiledCategoryRule struct {
+	rule     entity.CategoryRule
+	contains string
+	pattern  *regexp.Regexp
+}
+
+func NewCategoryRuleMatcher(rules []entity.CategoryRule) *CategoryRuleMatcher {
+	sorted := make([]entity.CategoryRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority < sorted[j].Priority
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	matcher := &CategoryRuleMatcher{}
+	for _, rule := range sorted {
+		compiled := compiledCategoryRule{
+			rule:     rule,
+			contains: strings.ToLower(strings.TrimSpace(rule.DescriptionContains)),
+		}
+		if rule.DescriptionPattern != "" {
+			pattern, err := regexp.Compile(rule.DescriptionPattern)
+			if err != nil {
+				// rule dengan regex rusak dilewati, validasi ada di entity
+				continue
+			}
+			compiled.pattern = pattern
+		}
+		matcher.rules = append(matcher.rules, compiled)
+	}
+
+	return matcher
+}
+
+// Match mengembalikan kategori dari rule pertama yang cocok
+func (m *CategoryRuleMatcher) Match(description string, amount float64, transactionType string) (uint, bool) {
+	if m == nil {
+		return 0, false
+	}
+
+	lowerDescription := strings.ToLower(description)
+	for _, compiled := range m.rules {
+		rule := compiled.rule
+		if rule.Type != "" && rule.Type != transactionType {
+			continue
+		}
+		if rule.MinAmount != nil && amount < *rule.MinAmount {
+			continue
+		}
+		if rule.MaxAmount != nil && amount > *rule.MaxAmount {
+			continue
+		}
+		if compiled.contains != "" && !strings.Contains(lowerDescription, compiled.contains) {
+			continue
+		}
+		if compiled.pattern != nil && !compiled.pattern.MatchString(description) {
+			continue
+		}
+		return rule.CategoryID, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,44 @@
+package utility
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const DefaultCurrency = "IDR"
+
+// NormalizeCurrency mengubah kode mata uang ke huruf besar dan memastikan formatnya
+// tiga huruf seperti kode ISO 4217
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", errors.New("invalid currency code: " + code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", errors.New("invalid currency code: " + code)
+		}
+	}
+	return code, nil
+}
+
+// BaseRateSQL menghasilkan ekspresi SQL kurs dari currency ke base currency user pada date.
+// Yang dipakai adalah kurs terakhir pada atau sebelum date, atau kurs terdekat setelahnya jika
+// belum ada, termasuk kebalikan dari kurs base ke currency. Hasilnya NULL jika user belum punya
+// kurs untuk pasangan tersebut sehingga amount-nya tidak ikut dijumlahkan.
+func BaseRateSQL(currency, date, userID string) string {
+	base := fmt.Sprintf("(SELECT users.base_currency FROM users WHERE users.id = %s)", userID)
+	lookup := func(rate, from, to string) string {
+		return fmt.Sprintf("(SELECT %s FROM exchange_rates WHERE exchange_rates.user_id = %s "+
+			"AND exchange_rates.from_currency = %s AND exchange_rates.to_currency = %s "+
+			"ORDER BY exchange_rates.date <= %[5]s DESC, CASE WHEN exchange_rates.date <= %[5]s THEN exchange_rates.date END DESC, "+
+			"exchange_rates.date ASC LIMIT 1)", rate, userID, from, to, date)
+	}
+
+	return fmt.Sprintf("(CASE WHEN %s = %s THEN 1 ELSE COALESCE(%s, %s) END)",
+		currency, base, lookup("exchange_rates.rate", currency, base), lookup("1 / exchange_rates.rate", base, currency))
+}
+
+// TransactionBaseAmountSQL adalah ekspresi amount transaksi dalam base currency memakai kurs tanggal transaksi
+var TransactionBaseAmountSQL = "transactions.amount * " + BaseRateSQL("transactions.currency", "transactions.date", "transactions.user_id")
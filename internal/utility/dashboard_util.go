@@ -1,6 +1,8 @@
 package utility
 
 import (
+	"go-electroshop/internal/payload/response"
+	"strconv"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,21 +13,50 @@ type DashboardUtil struct {
 }
 
 // Financial Overview
+// CalculateCurrentBalance menjumlahkan saldo awal semua account dengan income dikurangi expense,
+// dikonversi ke base currency dengan kurs hari ini. Transfer antar account tidak mengubah total saldo.
 func (u *DashboardUtil) CalculateCurrentBalance(userID uint) (float64, error) {
 	var balance float64
 	err := u.DB.Table("transactions").
-		Select("COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE -amount END), 0)").
-		Where("user_id = ?", userID).
+		Select("COALESCE(SUM(CASE WHEN type = 'income' THEN transactions.amount ELSE -transactions.amount END * "+
+			BaseRateSQL("transactions.currency", "CURRENT_DATE", "transactions.user_id")+"), 0) + "+
+			"(SELECT COALESCE(SUM(accounts.opening_balance * "+BaseRateSQL("accounts.currency", "CURRENT_DATE", "accounts.user_id")+"), 0) "+
+			"FROM accounts WHERE user_id = ? AND deleted_at IS NULL)", userID).
+		Where("user_id = ? AND deleted_at IS NULL", userID).
 		Row().
 		Scan(&balance)
 	return balance, err
 }
 
+// GetAccountBalances menghitung saldo setiap account dalam mata uang account: saldo awal + income
+// - expense + transfer masuk - transfer keluar. BaseBalance adalah saldo tersebut dalam base currency
+// dengan kurs hari ini.
+func (u *DashboardUtil) GetAccountBalances(userID uint) ([]response.AccountBalance, error) {
+	var balances []response.AccountBalance
+
+	accountBalances := u.DB.Table("accounts").
+		Select("accounts.id, accounts.user_id, accounts.name, accounts.type, accounts.currency, accounts.opening_balance"+
+			" + COALESCE((SELECT SUM(CASE WHEN transactions.type = 'income' THEN transactions.amount ELSE -transactions.amount END) FROM transactions WHERE transactions.account_id = accounts.id AND transactions.deleted_at IS NULL), 0)"+
+			" + COALESCE((SELECT SUM(transfers.amount) FROM transfers WHERE transfers.to_account_id = accounts.id AND transfers.deleted_at IS NULL), 0)"+
+			" - COALESCE((SELECT SUM(transfers.amount) FROM transfers WHERE transfers.from_account_id = accounts.id AND transfers.deleted_at IS NULL), 0) as balance").
+		Where("accounts.user_id = ? AND accounts.deleted_at IS NULL", userID)
+
+	err := u.DB.Table("(?) AS account_balances", accountBalances).
+		Select("account_balances.id, account_balances.name, account_balances.type, account_balances.currency, account_balances.balance, " +
+			"account_balances.balance * " + BaseRateSQL("account_balances.currency", "CURRENT_DATE", "account_balances.user_id") + " AS base_balance").
+		Order("account_balances.id ASC").
+		Scan(&balances).Error
+
+	return balances, err
+}
+
+// CalculateMonthlyIncome dan CalculateMonthlyExpense menjumlahkan transaksi sejak awal bulan
+// dalam base currency dengan kurs pada tanggal masing-masing transaksi
 func (u *DashboardUtil) CalculateMonthlyIncome(userID uint, startOfMonth string) (float64, error) {
 	var income float64
 	err := u.DB.Table("transactions").
-		Select("COALESCE(SUM(amount), 0)").
-		Where("user_id = ? AND type = 'income' AND date >= ?", userID, startOfMonth).
+		Select("COALESCE(SUM("+TransactionBaseAmountSQL+"), 0)").
+		Where("user_id = ? AND type = 'income' AND deleted_at IS NULL AND date >= ?", userID, startOfMonth).
 		Row().
 		Scan(&income)
 	return income, err
@@ -34,8 +65,8 @@ func (u *DashboardUtil) CalculateMonthlyIncome(userID uint, startOfMonth string)
 func (u *DashboardUtil) CalculateMonthlyExpense(userID uint, startOfMonth string) (float64, error) {
 	var expense float64
 	err := u.DB.Table("transactions").
-		Select("COALESCE(SUM(amount), 0)").
-		Where("user_id = ? AND type = 'expense' AND date >= ?", userID, startOfMonth).
+		Select("COALESCE(SUM("+TransactionBaseAmountSQL+"), 0)").
+		Where("user_id = ? AND type = 'expense' AND deleted_at IS NULL AND date >= ?", userID, startOfMonth).
 		Row().
 		Scan(&expense)
 	return expense, err
@@ -44,61 +75,97 @@ func (u *DashboardUtil) CalculateMonthlyExpense(userID uint, startOfMonth string
 func (u *DashboardUtil) CalculateTotalSavings(userID uint) (float64, error) {
 	var savings float64
 	err := u.DB.Table("transactions").
-		Select("COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE -amount END), 0)").
-		Where("user_id = ?", userID).
+		Select("COALESCE(SUM(CASE WHEN type = 'income' THEN "+TransactionBaseAmountSQL+" ELSE -"+TransactionBaseAmountSQL+" END), 0)").
+		Where("user_id = ? AND deleted_at IS NULL", userID).
 		Row().
 		Scan(&savings)
 	return savings, err
 }
 
 // Expense Analysis
-func (u *DashboardUtil) GetLastSixMonthsData(userID uint) ([]string, []float64, []float64, error) {
-	var labels []string
-	var incomeData []float64
-	var expenseData []float64
-
-	// Get current time
-	now := time.Now().UTC()
-
-	// Generate last 6 months
-	for i := 5; i >= 0; i-- {
-		date := now.AddDate(0, -i, 0)
-		startOfMonth := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
-		endOfMonth := startOfMonth.AddDate(0, 1, 0).Add(-time.Second)
-
-		// Get monthly income
-		var income float64
-		err := u.DB.Table("transactions").
-			Select("COALESCE(SUM(amount), 0)").
-			Where("user_id = ? AND type = 'income' AND deleted_at IS NULL AND date BETWEEN ? AND ?",
-				userID, startOfMonth.Format("2006-01-02"), endOfMonth.Format("2006-01-02")).
-			Row().
-			Scan(&income)
-		if err != nil {
-			return nil, nil, nil, err
-		}
-
-		// Get monthly expense
-		var expense float64
-		err = u.DB.Table("transactions").
-			Select("COALESCE(SUM(amount), 0)").
-			Where("user_id = ? AND type = 'expense' AND deleted_at IS NULL AND date BETWEEN ? AND ?",
-				userID, startOfMonth.Format("2006-01-02"), endOfMonth.Format("2006-01-02")).
-			Row().
-			Scan(&expense)
-		if err != nil {
-			return nil, nil, nil, err
-		}
-
-		labels = append(labels, date.Format("Jan"))
-		incomeData = append(incomeData, income)
-		expenseData = append(expenseData, expense)
-	}
-
-	return labels, incomeData, expenseData, nil
-}
-
-func (u *DashboardUtil) GetCategoryDistribution(userID uint) ([]string, []float64, error) {
+type PeriodTotal struct {
+	Bucket  time.Time `gorm:"column:bucket"`
+	Income  float64   `gorm:"column:income"`
+	Expense float64   `gorm:"column:expense"`
+}
+
+// GetIncomeExpenseSeries menjumlahkan income dan expense per bucket pada rentang [from, to)
+// dalam base currency dengan satu query ber-GROUP BY. Bucket tanpa transaksi tetap dikembalikan
+// dengan nilai 0.
+func (u *DashboardUtil) GetIncomeExpenseSeries(userID uint, buckets []time.Time, to time.Time, granularity string) ([]PeriodTotal, error) {
+	series := make([]PeriodTotal, len(buckets))
+	if len(buckets) == 0 {
+		return series, nil
+	}
+
+	// date_trunc dilakukan di UTC supaya sama dengan bucket yang dihitung di Go
+	var rows []PeriodTotal
+	err := u.DB.Table("transactions").
+		Select("date_trunc(?, transactions.date AT TIME ZONE 'UTC') AS bucket, "+
+			"COALESCE(SUM(CASE WHEN type = 'income' THEN "+TransactionBaseAmountSQL+" ELSE 0 END), 0) AS income, "+
+			"COALESCE(SUM(CASE WHEN type = 'expense' THEN "+TransactionBaseAmountSQL+" ELSE 0 END), 0) AS expense", granularity).
+		Where("user_id = ? AND deleted_at IS NULL AND date >= ? AND date < ?", userID, buckets[0], to).
+		Group("bucket").
+		Order("bucket").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totalByBucket := make(map[time.Time]PeriodTotal, len(rows))
+	for _, row := range rows {
+		totalByBucket[BucketStart(row.Bucket.UTC(), granularity)] = row
+	}
+
+	for i, bucket := range buckets {
+		series[i] = totalByBucket[bucket]
+		series[i].Bucket = bucket
+	}
+
+	return series, nil
+}
+
+// GetRecurringMonthlyNet menjumlahkan net (income - expense) transaksi hasil posting
+// recurring per bulan sejak start dalam base currency, di-index dengan format "2006-01"
+func (u *DashboardUtil) GetRecurringMonthlyNet(userID uint, start time.Time) (map[string]float64, error) {
+	type recurringLine struct {
+		Date time.Time `gorm:"column:date"`
+		Net  float64   `gorm:"column:net"`
+	}
+
+	var lines []recurringLine
+	err := u.DB.Table("transactions").
+		Select("transactions.date, CASE WHEN transactions.type = 'income' THEN "+TransactionBaseAmountSQL+" ELSE -"+TransactionBaseAmountSQL+" END AS net").
+		Joins("JOIN recurring_occurrences ON recurring_occurrences.transaction_id = transactions.id").
+		Where("transactions.user_id = ? AND transactions.deleted_at IS NULL AND transactions.date >= ?", userID, start.Format("2006-01-02")).
+		Find(&lines).Error
+	if err != nil {
+		return nil, err
+	}
+
+	monthly := make(map[string]float64)
+	for _, line := range lines {
+		monthly[line.Date.Format("2006-01")] += line.Net
+	}
+
+	return monthly, nil
+}
+
+// categoryLines menghasilkan satu baris per kategori transaksi: baris split jika transaksi
+// di-split, atau transaksinya sendiri. Amount sudah dalam base currency. Dipakai semua agregasi
+// per kategori.
+func (u *DashboardUtil) categoryLines() *gorm.DB {
+	return u.DB.Table("transactions").
+		Select("transactions.id AS transaction_id, transactions.user_id, transactions.type, transactions.date, " +
+			"COALESCE(transaction_splits.category_id, transactions.category_id) AS category_id, " +
+			"COALESCE(transaction_splits.amount, transactions.amount) * " +
+			BaseRateSQL("transactions.currency", "transactions.date", "transactions.user_id") + " AS amount").
+		Joins("LEFT JOIN transaction_splits ON transaction_splits.transaction_id = transactions.id").
+		Where("transactions.deleted_at IS NULL")
+}
+
+// GetCategoryDistribution menjumlahkan expense per kategori pada rentang [from, to)
+func (u *DashboardUtil) GetCategoryDistribution(userID uint, from, to time.Time) ([]string, []float64, error) {
 	type CategoryTotal struct {
 		Category string  `gorm:"column:category_name"`
 		Total    float64 `gorm:"column:total"`
@@ -106,10 +173,11 @@ func (u *DashboardUtil) GetCategoryDistribution(userID uint) ([]string, []float6
 
 	var results []CategoryTotal
 
-	err := u.DB.Table("transactions").
-		Select("categories.name as category_name, COALESCE(SUM(transactions.amount), 0) as total").
-		Joins("LEFT JOIN categories ON transactions.category_id = categories.id").
-		Where("transactions.user_id = ? AND transactions.type = 'expense' AND transactions.deleted_at IS NULL AND categories.deleted_at IS NULL", userID).
+	err := u.DB.Table("(?) AS category_lines", u.categoryLines()).
+		Select("categories.name as category_name, COALESCE(SUM(category_lines.amount), 0) as total").
+		Joins("LEFT JOIN categories ON category_lines.category_id = categories.id").
+		Where("category_lines.user_id = ? AND category_lines.type = 'expense' AND categories.deleted_at IS NULL", userID).
+		Where("category_lines.date >= ? AND category_lines.date < ?", from, to).
 		Group("categories.name").
 		Order("total DESC").
 		Find(&results).Error
@@ -128,7 +196,7 @@ func (u *DashboardUtil) GetCategoryDistribution(userID uint) ([]string, []float6
 	return labels, data, nil
 }
 
-func (u *DashboardUtil) GetTopExpenseCategories(userID uint, limit int) ([]string, []float64, error) {
+func (u *DashboardUtil) GetTopExpenseCategories(userID uint, from, to time.Time, limit int) ([]string, []float64, error) {
 	type CategoryTotal struct {
 		Category string  `gorm:"column:category_name"`
 		Total    float64 `gorm:"column:total"`
@@ -136,10 +204,11 @@ func (u *DashboardUtil) GetTopExpenseCategories(userID uint, limit int) ([]strin
 
 	var results []CategoryTotal
 
-	err := u.DB.Table("transactions").
-		Select("categories.name as category_name, COALESCE(SUM(transactions.amount), 0) as total").
-		Joins("LEFT JOIN categories ON transactions.category_id = categories.id").
-		Where("transactions.user_id = ? AND transactions.type = 'expense' AND transactions.deleted_at IS NULL AND categories.deleted_at IS NULL", userID).
+	err := u.DB.Table("(?) AS category_lines", u.categoryLines()).
+		Select("categories.name as category_name, COALESCE(SUM(category_lines.amount), 0) as total").
+		Joins("LEFT JOIN categories ON category_lines.category_id = categories.id").
+		Where("category_lines.user_id = ? AND category_lines.type = 'expense' AND categories.deleted_at IS NULL", userID).
+		Where("category_lines.date >= ? AND category_lines.date < ?", from, to).
 		Group("categories.name").
 		Order("total DESC").
 		Limit(limit).
@@ -158,3 +227,249 @@ func (u *DashboardUtil) GetTopExpenseCategories(userID uint, limit int) ([]strin
 
 	return labels, data, nil
 }
+
+// Budget
+type BudgetSpending struct {
+	BudgetID     uint    `gorm:"column:budget_id"`
+	CategoryID   uint    `gorm:"column:category_id"`
+	CategoryName string  `gorm:"column:category_name"`
+	Limit        float64 `gorm:"column:budget_limit"`
+	Spent        float64 `gorm:"column:spent"`
+}
+
+// GetBudgetSpending menghitung total expense per budget kategori pada periode [start, end)
+func (u *DashboardUtil) GetBudgetSpending(userID uint, start, end time.Time) ([]BudgetSpending, error) {
+	var results []BudgetSpending
+
+	err := u.DB.Table("budgets").
+		Select("budgets.id as budget_id, budgets.category_id, categories.name as category_name, budgets.amount as budget_limit, COALESCE(SUM(category_lines.amount), 0) as spent").
+		Joins("JOIN categories ON categories.id = budgets.category_id AND categories.deleted_at IS NULL").
+		Joins("LEFT JOIN (?) AS category_lines ON category_lines.category_id = budgets.category_id AND category_lines.user_id = budgets.user_id AND category_lines.type = 'expense' AND category_lines.date >= ? AND category_lines.date < ?", u.categoryLines(), start, end).
+		Where("budgets.user_id = ?", userID).
+		Group("budgets.id, budgets.category_id, categories.name, budgets.amount").
+		Order("categories.name ASC").
+		Find(&results).Error
+
+	return results, err
+}
+
+// CurrentBudgetPeriod mengembalikan awal bulan berjalan dan awal bulan berikutnya
+func CurrentBudgetPeriod(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Savings Goal
+type SavingsGoalFlow struct {
+	GoalID uint    `gorm:"column:goal_id"`
+	Total  float64 `gorm:"column:total"`
+	Recent float64 `gorm:"column:recent"`
+}
+
+// GetSavingsGoalFlows menjumlahkan dana yang masuk ke setiap savings goal: kontribusi manual,
+// arus dana account yang di-link (transaksi dan transfer) atau transaksi kategori yang di-link
+// sejak start date goal. Kontribusi manual dicatat dalam base currency, arus dana account dikonversi
+// dengan kurs tanggalnya. Recent hanya menghitung dana sejak recentSince untuk laju kontribusi.
+func (u *DashboardUtil) GetSavingsGoalFlows(userID uint, recentSince time.Time) (map[uint]SavingsGoalFlow, error) {
+	goals := func() *gorm.DB {
+		return u.DB.Table("savings_goals").Where("savings_goals.user_id = ? AND savings_goals.deleted_at IS NULL", userID)
+	}
+
+	contributions := goals().
+		Select("savings_goals.id AS goal_id, savings_contributions.amount, savings_contributions.date").
+		Joins("JOIN savings_contributions ON savings_contributions.goal_id = savings_goals.id")
+
+	// expense ke kategori tabungan berarti dana disisihkan, income berarti dana ditarik
+	categoryTransactions := goals().
+		Select("savings_goals.id AS goal_id, CASE WHEN category_lines.type = 'expense' THEN category_lines.amount ELSE -category_lines.amount END AS amount, category_lines.date").
+		Joins("JOIN (?) AS category_lines ON category_lines.category_id = savings_goals.category_id AND category_lines.user_id = savings_goals.user_id AND category_lines.date >= savings_goals.start_date", u.categoryLines())
+
+	accountTransactions := goals().
+		Select("savings_goals.id AS goal_id, CASE WHEN transactions.type = 'income' THEN " + TransactionBaseAmountSQL + " ELSE -" + TransactionBaseAmountSQL + " END AS amount, transactions.date").
+		Joins("JOIN transactions ON transactions.account_id = savings_goals.account_id AND transactions.deleted_at IS NULL AND transactions.date >= savings_goals.start_date")
+
+	// transfer selalu dalam mata uang account goal
+	transferAmount := "transfers.amount * " + BaseRateSQL("accounts.currency", "transfers.date", "accounts.user_id")
+
+	transfersIn := goals().
+		Select("savings_goals.id AS goal_id, " + transferAmount + " AS amount, transfers.date").
+		Joins("JOIN accounts ON accounts.id = savings_goals.account_id").
+		Joins("JOIN transfers ON transfers.to_account_id = savings_goals.account_id AND transfers.deleted_at IS NULL AND transfers.date >= savings_goals.start_date")
+
+	transfersOut := goals().
+		Select("savings_goals.id AS goal_id, -" + transferAmount + " AS amount, transfers.date").
+		Joins("JOIN accounts ON accounts.id = savings_goals.account_id").
+		Joins("JOIN transfers ON transfers.from_account_id = savings_goals.account_id AND transfers.deleted_at IS NULL AND transfers.date >= savings_goals.start_date")
+
+	var results []SavingsGoalFlow
+	err := u.DB.Table("(?) AS goal_flows",
+		u.DB.Raw("? UNION ALL ? UNION ALL ? UNION ALL ? UNION ALL ?",
+			contributions, categoryTransactions, accountTransactions, transfersIn, transfersOut)).
+		Select("goal_flows.goal_id, COALESCE(SUM(goal_flows.amount), 0) AS total, "+
+			"COALESCE(SUM(CASE WHEN goal_flows.date >= ? THEN goal_flows.amount ELSE 0 END), 0) AS recent", recentSince).
+		Group("goal_flows.goal_id").
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make(map[uint]SavingsGoalFlow, len(results))
+	for _, result := range results {
+		flows[result.GoalID] = result
+	}
+
+	return flows, nil
+}
+
+// Monthly Report
+// CalculateBalanceAt menghitung total saldo semua account sebelum tanggal before (saldo awal +
+// income - expense), dikonversi ke base currency dengan kurs pada hari terakhir sebelum before
+func (u *DashboardUtil) CalculateBalanceAt(userID uint, before time.Time) (float64, error) {
+	// tanggal berasal dari time.Time sehingga aman ditulis langsung sebagai literal SQL
+	rateDate := "'" + before.AddDate(0, 0, -1).Format("2006-01-02") + "'"
+
+	var balance float64
+	err := u.DB.Table("transactions").
+		Select("COALESCE(SUM(CASE WHEN type = 'income' THEN transactions.amount ELSE -transactions.amount END * "+
+			BaseRateSQL("transactions.currency", rateDate, "transactions.user_id")+"), 0) + "+
+			"(SELECT COALESCE(SUM(accounts.opening_balance * "+BaseRateSQL("accounts.currency", rateDate, "accounts.user_id")+"), 0) "+
+			"FROM accounts WHERE user_id = ? AND deleted_at IS NULL)", userID).
+		Where("user_id = ? AND deleted_at IS NULL AND date < ?", userID, before).
+		Row().
+		Scan(&balance)
+	return balance, err
+}
+
+type CategoryTypeTotal struct {
+	Category string  `gorm:"column:category_name"`
+	Type     string  `gorm:"column:type"`
+	Total    float64 `gorm:"column:total"`
+}
+
+// GetCategoryTotals menjumlahkan income dan expense per kategori pada rentang [from, to)
+func (u *DashboardUtil) GetCategoryTotals(userID uint, from, to time.Time) ([]CategoryTypeTotal, error) {
+	var results []CategoryTypeTotal
+
+	err := u.DB.Table("(?) AS category_lines", u.categoryLines()).
+		Select("categories.name as category_name, category_lines.type, COALESCE(SUM(category_lines.amount), 0) as total").
+		Joins("LEFT JOIN categories ON category_lines.category_id = categories.id").
+		Where("category_lines.user_id = ? AND categories.deleted_at IS NULL", userID).
+		Where("category_lines.date >= ? AND category_lines.date < ?", from, to).
+		Group("categories.name, category_lines.type").
+		Order("category_lines.type ASC, total DESC").
+		Find(&results).Error
+
+	return results, err
+}
+
+type TopExpense struct {
+	TransactionID uint      `gorm:"column:transaction_id"`
+	Date          time.Time `gorm:"column:date"`
+	Category      string    `gorm:"column:category_name"`
+	Description   string    `gorm:"column:description"`
+	Amount        float64   `gorm:"column:amount"`
+	Currency      string    `gorm:"column:currency"`
+	BaseAmount    float64   `gorm:"column:base_amount"`
+}
+
+// GetTopExpenses mengembalikan transaksi expense terbesar dalam base currency pada rentang [from, to)
+func (u *DashboardUtil) GetTopExpenses(userID uint, from, to time.Time, limit int) ([]TopExpense, error) {
+	var results []TopExpense
+
+	err := u.DB.Table("transactions").
+		Select("transactions.id AS transaction_id, transactions.date, categories.name AS category_name, transactions.description, "+
+			"transactions.amount, transactions.currency, "+TransactionBaseAmountSQL+" AS base_amount").
+		Joins("LEFT JOIN categories ON categories.id = transactions.category_id").
+		Where("transactions.user_id = ? AND transactions.type = 'expense' AND transactions.deleted_at IS NULL", userID).
+		Where("transactions.date >= ? AND transactions.date < ?", from, to).
+		Order("base_amount DESC, transactions.date ASC").
+		Limit(limit).
+		Find(&results).Error
+
+	return results, err
+}
+
+// Insights
+type ExpenseLine struct {
+	TransactionID uint      `gorm:"column:transaction_id"`
+	CategoryID    uint      `gorm:"column:category_id"`
+	Category      string    `gorm:"column:category_name"`
+	Date          time.Time `gorm:"column:date"`
+	Amount        float64   `gorm:"column:amount"`
+}
+
+// GetExpenseLines mengembalikan baris expense per kategori dalam base currency pada rentang
+// [from, to). Baris tanpa kurs ke base currency dilewati.
+func (u *DashboardUtil) GetExpenseLines(userID uint, from, to time.Time) ([]ExpenseLine, error) {
+	var results []ExpenseLine
+
+	err := u.DB.Table("(?) AS category_lines", u.categoryLines()).
+		Select("category_lines.transaction_id, category_lines.category_id, categories.name AS category_name, category_lines.date, category_lines.amount").
+		Joins("JOIN categories ON categories.id = category_lines.category_id").
+		Where("category_lines.user_id = ? AND category_lines.type = 'expense' AND categories.deleted_at IS NULL", userID).
+		Where("category_lines.date >= ? AND category_lines.date < ? AND category_lines.amount IS NOT NULL", from, to).
+		Order("category_lines.date ASC, category_lines.transaction_id ASC").
+		Find(&results).Error
+
+	return results, err
+}
+
+// Ledger
+type LedgerMemberTotal struct {
+	UserID       uint    `gorm:"column:user_id"`
+	Name         string  `gorm:"column:user_name"`
+	Income       float64 `gorm:"column:income"`
+	Expense      float64 `gorm:"column:expense"`
+	Transactions int64   `gorm:"column:transactions"`
+}
+
+// ledgerRateSQL adalah kurs transaksi ke base currency viewer. Transaksi ledger bisa dicatat
+// member dengan base currency berbeda, jadi semua dijumlahkan memakai kurs milik viewer.
+func ledgerRateSQL(viewerID uint) string {
+	return BaseRateSQL("transactions.currency", "transactions.date", strconv.FormatUint(uint64(viewerID), 10))
+}
+
+// GetLedgerMemberTotals menjumlahkan income dan expense per member yang mencatat transaksi di
+// ledger pada rentang [from, to), dalam base currency viewer
+func (u *DashboardUtil) GetLedgerMemberTotals(ledgerID, viewerID uint, from, to time.Time) ([]LedgerMemberTotal, error) {
+	var results []LedgerMemberTotal
+	rate := ledgerRateSQL(viewerID)
+
+	err := u.DB.Table("transactions").
+		Select("transactions.user_id, users.name AS user_name, "+
+			"COALESCE(SUM(CASE WHEN transactions.type = 'income' THEN transactions.amount * "+rate+" END), 0) AS income, "+
+			"COALESCE(SUM(CASE WHEN transactions.type = 'expense' THEN transactions.amount * "+rate+" END), 0) AS expense, "+
+			"COUNT(*) AS transactions").
+		Joins("JOIN users ON users.id = transactions.user_id").
+		Where("transactions.ledger_id = ? AND transactions.deleted_at IS NULL", ledgerID).
+		Where("transactions.date >= ? AND transactions.date < ?", from, to).
+		Group("transactions.user_id, users.name").
+		Order("expense DESC, transactions.user_id ASC").
+		Find(&results).Error
+
+	return results, err
+}
+
+// GetLedgerCategoryTotals menjumlahkan income dan expense per kategori ledger pada rentang
+// [from, to), dalam base currency viewer. Transaksi split dihitung per split seperti categoryLines.
+func (u *DashboardUtil) GetLedgerCategoryTotals(ledgerID, viewerID uint, from, to time.Time) ([]CategoryTypeTotal, error) {
+	var results []CategoryTypeTotal
+
+	lines := u.DB.Table("transactions").
+		Select("transactions.type, COALESCE(transaction_splits.category_id, transactions.category_id) AS category_id, "+
+			"COALESCE(transaction_splits.amount, transactions.amount) * "+ledgerRateSQL(viewerID)+" AS amount").
+		Joins("LEFT JOIN transaction_splits ON transaction_splits.transaction_id = transactions.id").
+		Where("transactions.ledger_id = ? AND transactions.deleted_at IS NULL", ledgerID).
+		Where("transactions.date >= ? AND transactions.date < ?", from, to)
+
+	err := u.DB.Table("(?) AS category_lines", lines).
+		Select("categories.name as category_name, category_lines.type, COALESCE(SUM(category_lines.amount), 0) as total").
+		Joins("LEFT JOIN categories ON category_lines.category_id = categories.id").
+		Where("categories.deleted_at IS NULL").
+		Group("categories.name, category_lines.type").
+		Order("category_lines.type ASC, total DESC").
+		Find(&results).Error
+
+	return results, err
+}
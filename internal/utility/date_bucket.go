@@ -0,0 +1,91 @@
+package utility
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	GranularityDay     = "day"
+	GranularityWeek    = "week"
+	GranularityMonth   = "month"
+	GranularityQuarter = "quarter"
+	GranularityYear    = "year"
+)
+
+// BucketStart mengembalikan awal bucket yang berisi t, sama dengan date_trunc PostgreSQL
+// (minggu dimulai hari Senin)
+func BucketStart(t time.Time, granularity string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	switch granularity {
+	case GranularityWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case GranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case GranularityQuarter:
+		month := time.Month((int(t.Month())-1)/3*3 + 1)
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+	case GranularityYear:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	}
+
+	return day
+}
+
+// NextBucket mengembalikan awal bucket setelah bucket yang dimulai pada start
+func NextBucket(start time.Time, granularity string) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return start.AddDate(0, 1, 0)
+	case GranularityQuarter:
+		return start.AddDate(0, 3, 0)
+	case GranularityYear:
+		return start.AddDate(1, 0, 0)
+	}
+
+	return start.AddDate(0, 0, 1)
+}
+
+// nama bulan singkat per locale, locale lain memakai format bawaan Go (en)
+var shortMonthNames = map[string][12]string{
+	"id": {"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"},
+}
+
+// ShortMonthName mengembalikan nama bulan singkat sesuai locale user
+func ShortMonthName(month time.Month, locale string) string {
+	if names, ok := shortMonthNames[locale]; ok {
+		return names[month-1]
+	}
+	return month.String()[:3]
+}
+
+// BucketLabel menghasilkan label chart untuk bucket yang dimulai pada start
+func BucketLabel(start time.Time, granularity, locale string) string {
+	switch granularity {
+	case GranularityMonth:
+		return fmt.Sprintf("%s %d", ShortMonthName(start.Month(), locale), start.Year())
+	case GranularityQuarter:
+		return fmt.Sprintf("Q%d %d", (int(start.Month())-1)/3+1, start.Year())
+	case GranularityYear:
+		return start.Format("2006")
+	}
+
+	return start.Format("2006-01-02")
+}
+
+// Buckets mengembalikan awal setiap bucket yang beririsan dengan rentang [from, to).
+// Bernilai false jika jumlah bucket melebihi limit.
+func Buckets(from, to time.Time, granularity string, limit int) ([]time.Time, bool) {
+	var buckets []time.Time
+	for start := BucketStart(from, granularity); start.Before(to); start = NextBucket(start, granularity) {
+		if len(buckets) == limit {
+			return nil, false
+		}
+		buckets = append(buckets, start)
+	}
+	return buckets, true
+}
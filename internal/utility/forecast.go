@@ -0,0 +1,62 @@
+package utility
+
+import "math"
+
+// z-score untuk confidence band ~80%
+const forecastConfidenceZ = 1.28
+
+type ForecastPoint struct {
+	Balance float64
+	Lower   float64
+	Upper   float64
+}
+
+// ForecastBalances memproyeksikan saldo akhir bulan. Setiap bulan saldo bertambah rata-rata
+// net non-recurring historis ditambah net recurring yang terjadwal di bulan tersebut.
+// Band melebar sebesar z * stddev * sqrt(k) karena deviasi bulanan ikut terakumulasi.
+func ForecastBalances(startBalance float64, historicalNet, scheduledNet []float64) []ForecastPoint {
+	mean, stdDev := meanStdDev(historicalNet)
+
+	points := make([]ForecastPoint, len(scheduledNet))
+	balance := startBalance
+	for k, scheduled := range scheduledNet {
+		balance += mean + scheduled
+		spread := forecastConfidenceZ * stdDev * math.Sqrt(float64(k+1))
+		points[k] = ForecastPoint{
+			Balance: math.Round(balance*100) / 100,
+			Lower:   math.Round((balance-spread)*100) / 100,
+			Upper:   math.Round((balance+spread)*100) / 100,
+		}
+	}
+
+	return points
+}
+
+// HistoricalNetStats mengembalikan rata-rata dan standar deviasi (sampel) net bulanan
+func HistoricalNetStats(historicalNet []float64) (float64, float64) {
+	mean, stdDev := meanStdDev(historicalNet)
+	return math.Round(mean*100) / 100, math.Round(stdDev*100) / 100
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	if len(values) < 2 {
+		return mean, 0
+	}
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+
+	return mean, math.Sqrt(squares / float64(len(values)-1))
+}
@@ -0,0 +1,145 @@
+package utility
+
+import (
+	"fmt"
+	"go-electroshop/internal/payload/entity"
+	"sort"
+	"time"
+)
+
+const (
+	// jumlah transaksi minimal di history supaya median kategori bisa dipercaya
+	minInsightSamples = 5
+	// jumlah bulan minimal kategori punya pengeluaran supaya rata-rata bulanannya bisa dipercaya
+	minInsightMonths = 3
+
+	// transaksi ditandai jika amount-nya minimal kelipatan ini dari median kategori
+	unusualTransactionRatio  = 3
+	criticalTransactionRatio = 5
+
+	// kategori ditandai jika total bulan ini minimal kelipatan ini dari rata-rata bulanan
+	categorySpikeRatio         = 1.3
+	warningCategorySpikeRatio  = 1.5
+	criticalCategorySpikeRatio = 2
+)
+
+// SpendingInsight adalah satu temuan dari DetectSpendingInsights, Key stabil untuk temuan yang sama
+type SpendingInsight struct {
+	Key           string
+	Kind          string
+	Severity      string
+	CategoryID    uint
+	Category      string
+	TransactionID uint      // hanya untuk unusual_transaction
+	Date          time.Time // tanggal transaksi, atau awal bulan untuk category_spike
+	Amount        float64
+	Baseline      float64
+}
+
+// Ratio adalah perbandingan Amount terhadap Baseline
+func (i SpendingInsight) Ratio() float64 {
+	return i.Amount / i.Baseline
+}
+
+// DetectSpendingInsights membandingkan pengeluaran bulan berjalan (current) dengan history
+// beberapa bulan penuh sebelumnya. Yang ditandai adalah transaksi yang minimal 3x median
+// transaksi di kategori yang sama, dan kategori yang total bulan ini sudah minimal 30% di atas
+// rata-rata bulanannya. Kategori dengan history terlalu sedikit dilewati.
+func DetectSpendingInsights(history, current []ExpenseLine, monthStart time.Time) []SpendingInsight {
+	samples := map[uint][]float64{}
+	monthly := map[uint]map[string]float64{}
+	for _, line := range history {
+		samples[line.CategoryID] = append(samples[line.CategoryID], line.Amount)
+		if monthly[line.CategoryID] == nil {
+			monthly[line.CategoryID] = map[string]float64{}
+		}
+		monthly[line.CategoryID][line.Date.Format("2006-01")] += line.Amount
+	}
+
+	medians := map[uint]float64{}
+	for categoryID, amounts := range samples {
+		if len(amounts) >= minInsightSamples {
+			medians[categoryID] = median(amounts)
+		}
+	}
+
+	var insights []SpendingInsight
+	currentTotals := map[uint]float64{}
+	categoryNames := map[uint]string{}
+	for _, line := range current {
+		currentTotals[line.CategoryID] += line.Amount
+		categoryNames[line.CategoryID] = line.Category
+
+		baseline := medians[line.CategoryID]
+		if baseline <= 0 || line.Amount < baseline*unusualTransactionRatio {
+			continue
+		}
+		severity := entity.InsightSeverityWarning
+		if line.Amount >= baseline*criticalTransactionRatio {
+			severity = entity.InsightSeverityCritical
+		}
+		insights = append(insights, SpendingInsight{
+			Key:           fmt.Sprintf("%s:%d:%d", entity.InsightKindUnusualTransaction, line.TransactionID, line.CategoryID),
+			Kind:          entity.InsightKindUnusualTransaction,
+			Severity:      severity,
+			CategoryID:    line.CategoryID,
+			Category:      line.Category,
+			TransactionID: line.TransactionID,
+			Date:          line.Date,
+			Amount:        line.Amount,
+			Baseline:      baseline,
+		})
+	}
+
+	categoryIDs := make([]uint, 0, len(currentTotals))
+	for categoryID := range currentTotals {
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	sort.Slice(categoryIDs, func(i, j int) bool { return categoryIDs[i] < categoryIDs[j] })
+
+	for _, categoryID := range categoryIDs {
+		months := monthly[categoryID]
+		if len(months) < minInsightMonths {
+			continue
+		}
+		var total float64
+		for _, amount := range months {
+			total += amount
+		}
+		baseline := total / float64(len(months))
+
+		amount := currentTotals[categoryID]
+		if baseline <= 0 || amount < baseline*categorySpikeRatio {
+			continue
+		}
+		severity := entity.InsightSeverityInfo
+		switch {
+		case amount >= baseline*criticalCategorySpikeRatio:
+			severity = entity.InsightSeverityCritical
+		case amount >= baseline*warningCategorySpikeRatio:
+			severity = entity.InsightSeverityWarning
+		}
+		insights = append(insights, SpendingInsight{
+			Key:        fmt.Sprintf("%s:%d:%s", entity.InsightKindCategorySpike, categoryID, monthStart.Format("2006-01")),
+			Kind:       entity.InsightKindCategorySpike,
+			Severity:   severity,
+			CategoryID: categoryID,
+			Category:   categoryNames[categoryID],
+			Date:       monthStart,
+			Amount:     amount,
+			Baseline:   baseline,
+		})
+	}
+
+	return insights
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -2,7 +2,20 @@ package utility
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// giftCardAlphabet tanpa karakter yang mudah tertukar (0/O, 1/I), panjangnya 32
+// sehingga setiap karakter tepat 5 bit acak
+const giftCardAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const (
+	giftCardCodeLength = 16
+	giftCardGroupSize  = 4
 )
 
 func GenerateRandomString(length int) string {
@@ -10,3 +23,60 @@ func GenerateRandomString(length int) string {
 	rand.Read(b)
 	return base64.URLEncoding.EncodeToString(b)[:length]
 }
+
+// GenerateRandomCode membuat kode acak dari alphabet menggunakan crypto/rand.
+// Byte acak di luar kelipatan panjang alphabet dibuang supaya tidak ada modulo bias.
+func GenerateRandomCode(length int, alphabet string) (string, error) {
+	if length <= 0 || len(alphabet) == 0 || len(alphabet) > 256 {
+		return "", errors.New("invalid code length or alphabet")
+	}
+
+	limit := 256 - (256 % len(alphabet))
+	code := make([]byte, 0, length)
+	buf := make([]byte, length)
+
+	for len(code) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			code = append(code, alphabet[int(b)%len(alphabet)])
+			if len(code) == length {
+				break
+			}
+		}
+	}
+
+	return string(code), nil
+}
+
+// GenerateGiftCardCode membuat kode gift card dengan format XXXX-XXXX-XXXX-XXXX
+func GenerateGiftCardCode() (string, error) {
+	code, err := GenerateRandomCode(giftCardCodeLength, giftCardAlphabet)
+	if err != nil {
+		return "", err
+	}
+
+	groups := make([]string, 0, giftCardCodeLength/giftCardGroupSize)
+	for i := 0; i < len(code); i += giftCardGroupSize {
+		groups = append(groups, code[i:i+giftCardGroupSize])
+	}
+
+	return strings.Join(groups, "-"), nil
+}
+
+// NormalizeGiftCardCode menghapus spasi/strip dan mengubah kode menjadi huruf besar
+func NormalizeGiftCardCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.Join(strings.Fields(code), "")
+}
+
+// HashGiftCardCode menghasilkan hash kode gift card untuk disimpan di database
+func HashGiftCardCode(code string) string {
+	sum := sha256.Sum256([]byte(NormalizeGiftCardCode(code)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,49 @@
+package utility
+
+import (
+	"go-electroshop/internal/payload/entity"
+	"time"
+)
+
+// RecurrenceDate menghitung tanggal occurrence ke-n (mulai dari 0) dari jadwal recurring.
+// Perhitungan selalu dari start supaya tanggal tidak bergeser, misalnya jadwal
+// bulanan tanggal 31 tetap jatuh di akhir bulan untuk Februari dan kembali ke 31 di Maret.
+func RecurrenceDate(start time.Time, frequency string, interval, n int) time.Time {
+	if interval <= 0 {
+		interval = 1
+	}
+	steps := n * interval
+
+	switch frequency {
+	case entity.RecurrenceDaily:
+		return start.AddDate(0, 0, steps)
+	case entity.RecurrenceWeekly:
+		return start.AddDate(0, 0, 7*steps)
+	case entity.RecurrenceMonthly:
+		return addMonthsClamped(start, steps)
+	case entity.RecurrenceYearly:
+		return addMonthsClamped(start, 12*steps)
+	}
+
+	return start
+}
+
+// addMonthsClamped seperti AddDate(0, months, 0) tetapi tanggal yang tidak ada
+// di bulan tujuan dipotong ke hari terakhir bulan tersebut
+func addMonthsClamped(t time.Time, months int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).AddDate(0, months, 0)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// TruncateToDate membuang komponen jam sehingga hanya tanggal (UTC) yang tersisa
+func TruncateToDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
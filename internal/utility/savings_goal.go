@@ -0,0 +1,77 @@
+package utility
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// laju kontribusi dihitung dari dana yang masuk dalam window ini
+	SavingsRateWindow = 90 * 24 * time.Hour
+	// window minimal supaya kontribusi pertama tidak membuat proyeksi terlalu optimis
+	minSavingsRateWindow = 30 * 24 * time.Hour
+	daysPerMonth         = 30.0
+)
+
+type SavingsProjection struct {
+	Remaining               float64
+	ProgressPercentage      float64
+	Completed               bool
+	MonthlyRate             float64
+	RequiredMonthly         float64
+	ProjectedCompletionDate *time.Time
+	OnTrack                 *bool
+}
+
+// SavingsRateWindowStart mengembalikan awal window laju kontribusi, tidak lebih awal dari start date goal
+func SavingsRateWindowStart(startDate, now time.Time) time.Time {
+	windowStart := now.Add(-SavingsRateWindow)
+	if startDate.After(windowStart) {
+		return startDate
+	}
+	return windowStart
+}
+
+// ProjectSavingsGoal memproyeksikan tanggal tercapainya goal dari laju kontribusi terakhir.
+// recent adalah dana yang masuk sejak SavingsRateWindowStart(startDate, now).
+func ProjectSavingsGoal(target, saved, recent float64, startDate time.Time, targetDate *time.Time, now time.Time) SavingsProjection {
+	projection := SavingsProjection{
+		Remaining: math.Max(target-saved, 0),
+		Completed: saved >= target,
+	}
+	if target > 0 {
+		projection.ProgressPercentage = math.Round(math.Min(saved/target, 1)*10000) / 100
+	}
+
+	window := now.Sub(SavingsRateWindowStart(startDate, now))
+	if window < minSavingsRateWindow {
+		window = minSavingsRateWindow
+	}
+	dailyRate := recent / window.Hours() * 24
+	projection.MonthlyRate = math.Round(dailyRate*daysPerMonth*100) / 100
+
+	if !projection.Completed && dailyRate > 0 {
+		days := math.Ceil(projection.Remaining / dailyRate)
+		projected := truncateDay(now).AddDate(0, 0, int(days))
+		projection.ProjectedCompletionDate = &projected
+	}
+
+	if targetDate != nil {
+		daysLeft := targetDate.Sub(truncateDay(now)).Hours() / 24
+		if daysLeft > 0 {
+			projection.RequiredMonthly = math.Round(projection.Remaining/math.Max(daysLeft/daysPerMonth, 1)*100) / 100
+		} else {
+			projection.RequiredMonthly = projection.Remaining
+		}
+
+		onTrack := projection.Completed ||
+			(projection.ProjectedCompletionDate != nil && !projection.ProjectedCompletionDate.After(*targetDate))
+		projection.OnTrack = &onTrack
+	}
+
+	return projection
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,59 @@
+package utility
+
+import (
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ViewerSessionCookie = "electroshop_sid"
+	ViewerSessionHeader = "X-Session-ID"
+)
+
+// GetOrSetViewerSession mengambil session ID anonim dari header atau cookie,
+// dan membuat session baru jika belum ada
+func GetOrSetViewerSession(ctx *gin.Context) string {
+	if sessionID := ctx.GetHeader(ViewerSessionHeader); sessionID != "" && len(sessionID) <= 64 {
+		return sessionID
+	}
+
+	if sessionID, err := ctx.Cookie(ViewerSessionCookie); err == nil && sessionID != "" && len(sessionID) <= 64 {
+		return sessionID
+	}
+
+	sessionID := GenerateRandomString(32)
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(ViewerSessionCookie, sessionID, 60*60*24*365, "/", "", false, true)
+	return sessionID
+}
+
+// GetOptionalUserID mengembalikan user ID jika request terautentikasi, nil jika anonim
+func GetOptionalUserID(ctx *gin.Context) *uint {
+	if _, exists := ctx.Get("userID"); !exists {
+		return nil
+	}
+
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil || userID == 0 {
+		return nil
+	}
+	return &userID
+}
+
+// IsAdminRequest mengecek apakah request berasal dari admin berdasarkan claims token
+func IsAdminRequest(ctx *gin.Context) bool {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		return false
+	}
+
+	mapClaims, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return false
+	}
+
+	isAdmin, _ := mapClaims["is_admin"].(bool)
+	return isAdmin
+}
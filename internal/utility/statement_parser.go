@@ -0,0 +1,387 @@
+package utility
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsedTransaction adalah satu baris hasil parsing file import. Amount selalu positif,
+// arah uang ditentukan oleh Type (income/expense).
+type ParsedTransaction struct {
+	Date        time.Time
+	Amount      float64
+	Type        string
+	Description string
+	Error       string // diisi jika baris tidak bisa diparsing
+}
+
+// CSVMapping menentukan kolom CSV yang dipakai untuk setiap field transaksi
+type CSVMapping struct {
+	DateColumn        string
+	AmountColumn      string
+	DescriptionColumn string
+	TypeColumn        string // opsional, jika kosong tanda amount menentukan tipe
+	DateFormat        string // YYYY-MM-DD, DD/MM/YYYY, MM/DD/YYYY, DD-MM-YYYY atau layout Go
+	DecimalSeparator  string // "." (default) atau ","
+	Delimiter         string // "," (default) atau ";"
+}
+
+var csvDateFormats = map[string]string{
+	"YYYY-MM-DD": "2006-01-02",
+	"DD/MM/YYYY": "02/01/2006",
+	"MM/DD/YYYY": "01/02/2006",
+	"DD-MM-YYYY": "02-01-2006",
+	"YYYY/MM/DD": "2006/01/02",
+}
+
+// ReadCSVPreview mengembalikan header dan beberapa baris pertama untuk langkah mapping kolom
+func ReadCSVPreview(r io.Reader, delimiter string, maxRows int) ([]string, [][]string, error) {
+	reader := newCSVReader(r, delimiter)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read csv header: %v", err)
+	}
+
+	var rows [][]string
+	for len(rows) < maxRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read csv: %v", err)
+		}
+		rows = append(rows, record)
+	}
+
+	return trimAll(header), rows, nil
+}
+
+// ParseCSV membaca file CSV dengan baris pertama sebagai header
+func ParseCSV(r io.Reader, mapping CSVMapping) ([]ParsedTransaction, error) {
+	reader := newCSVReader(r, mapping.Delimiter)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %v", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range trimAll(header) {
+		columns[strings.ToLower(name)] = i
+	}
+
+	column := func(name string, required bool) (int, error) {
+		if name == "" {
+			if required {
+				return -1, errors.New("column mapping is incomplete")
+			}
+			return -1, nil
+		}
+		index, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return -1, fmt.Errorf("column %q not found in csv header", name)
+		}
+		return index, nil
+	}
+
+	dateIndex, err := column(mapping.DateColumn, true)
+	if err != nil {
+		return nil, err
+	}
+	amountIndex, err := column(mapping.AmountColumn, true)
+	if err != nil {
+		return nil, err
+	}
+	descriptionIndex, err := column(mapping.DescriptionColumn, false)
+	if err != nil {
+		return nil, err
+	}
+	typeIndex, err := column(mapping.TypeColumn, false)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := mapping.DateFormat
+	if layout == "" {
+		layout = "YYYY-MM-DD"
+	}
+	if goLayout, ok := csvDateFormats[strings.ToUpper(layout)]; ok {
+		layout = goLayout
+	}
+
+	var results []ParsedTransaction
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %v", err)
+		}
+
+		value := func(index int) string {
+			if index < 0 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		parsed := ParsedTransaction{Description: value(descriptionIndex)}
+
+		date, err := time.Parse(layout, value(dateIndex))
+		if err != nil {
+			parsed.Error = fmt.Sprintf("invalid date %q", value(dateIndex))
+			results = append(results, parsed)
+			continue
+		}
+		parsed.Date = date
+
+		amount, err := ParseAmount(value(amountIndex), mapping.DecimalSeparator)
+		if err != nil {
+			parsed.Error = fmt.Sprintf("invalid amount %q", value(amountIndex))
+			results = append(results, parsed)
+			continue
+		}
+
+		parsed.Type = typeFromAmount(amount)
+		if typeIndex >= 0 {
+			switch strings.ToLower(value(typeIndex)) {
+			case "income", "credit", "cr", "in":
+				parsed.Type = "income"
+			case "expense", "debit", "db", "dr", "out":
+				parsed.Type = "expense"
+			}
+		}
+		parsed.Amount = math.Abs(amount)
+
+		results = append(results, parsed)
+	}
+
+	return results, nil
+}
+
+// ParseOFX membaca blok <STMTTRN> dari file OFX, baik format SGML (v1) maupun XML (v2)
+func ParseOFX(r io.Reader) ([]ParsedTransaction, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ofx: %v", err)
+	}
+
+	var results []ParsedTransaction
+	var current map[string]string
+
+	// setiap token berbentuk "TAG>value" setelah di-split dengan "<"
+	for _, token := range strings.Split(string(content), "<") {
+		end := strings.Index(token, ">")
+		if end < 0 {
+			continue
+		}
+		tag := strings.ToUpper(strings.TrimSpace(token[:end]))
+		value := html.UnescapeString(strings.TrimSpace(token[end+1:]))
+
+		switch {
+		case tag == "STMTTRN":
+			current = map[string]string{}
+		case tag == "/STMTTRN":
+			if current != nil {
+				results = append(results, ofxTransaction(current))
+			}
+			current = nil
+		case current != nil && !strings.HasPrefix(tag, "/"):
+			current[tag] = value
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, errors.New("no transactions found in ofx file")
+	}
+
+	return results, nil
+}
+
+func ofxTransaction(fields map[string]string) ParsedTransaction {
+	parsed := ParsedTransaction{Description: fields["NAME"]}
+	if memo := fields["MEMO"]; memo != "" {
+		if parsed.Description == "" {
+			parsed.Description = memo
+		} else if memo != parsed.Description {
+			parsed.Description += " - " + memo
+		}
+	}
+
+	// DTPOSTED berformat YYYYMMDD[HHMMSS[.XXX]][TZ]
+	posted := fields["DTPOSTED"]
+	if len(posted) < 8 {
+		parsed.Error = fmt.Sprintf("invalid date %q", posted)
+		return parsed
+	}
+	date, err := time.Parse("20060102", posted[:8])
+	if err != nil {
+		parsed.Error = fmt.Sprintf("invalid date %q", posted)
+		return parsed
+	}
+	parsed.Date = date
+
+	amount, err := ParseAmount(fields["TRNAMT"], ".")
+	if err != nil {
+		parsed.Error = fmt.Sprintf("invalid amount %q", fields["TRNAMT"])
+		return parsed
+	}
+	parsed.Type = typeFromAmount(amount)
+	parsed.Amount = math.Abs(amount)
+
+	return parsed
+}
+
+var qifDateLayouts = []string{"01/02/2006", "1/2/2006", "01/02'06", "1/2'06", "01/02/06", "1/2/06", "2006-01-02", "02.01.2006"}
+
+// ParseQIF membaca file QIF. Setiap record diakhiri "^", field yang dipakai
+// D (tanggal), T/U (amount), P (payee) dan M (memo).
+func ParseQIF(r io.Reader) ([]ParsedTransaction, error) {
+	scanner := bufio.NewScanner(r)
+
+	var results []ParsedTransaction
+	fields := map[byte]string{}
+
+	flush := func() {
+		if len(fields) == 0 {
+			return
+		}
+		results = append(results, qifTransaction(fields))
+		fields = map[byte]string{}
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "!") {
+			continue
+		}
+		if line == "^" {
+			flush()
+			continue
+		}
+		if _, exists := fields[line[0]]; !exists {
+			fields[line[0]] = strings.TrimSpace(line[1:])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read qif: %v", err)
+	}
+	flush()
+
+	if len(results) == 0 {
+		return nil, errors.New("no transactions found in qif file")
+	}
+
+	return results, nil
+}
+
+func qifTransaction(fields map[byte]string) ParsedTransaction {
+	parsed := ParsedTransaction{Description: fields['P']}
+	if memo := fields['M']; memo != "" {
+		if parsed.Description == "" {
+			parsed.Description = memo
+		} else if memo != parsed.Description {
+			parsed.Description += " - " + memo
+		}
+	}
+
+	rawDate := strings.ReplaceAll(fields['D'], " ", "")
+	for _, layout := range qifDateLayouts {
+		if date, err := time.Parse(layout, rawDate); err == nil {
+			parsed.Date = date
+			break
+		}
+	}
+	if parsed.Date.IsZero() {
+		parsed.Error = fmt.Sprintf("invalid date %q", fields['D'])
+		return parsed
+	}
+
+	rawAmount := fields['T']
+	if rawAmount == "" {
+		rawAmount = fields['U']
+	}
+	amount, err := ParseAmount(rawAmount, ".")
+	if err != nil {
+		parsed.Error = fmt.Sprintf("invalid amount %q", rawAmount)
+		return parsed
+	}
+	parsed.Type = typeFromAmount(amount)
+	parsed.Amount = math.Abs(amount)
+
+	return parsed
+}
+
+// ParseAmount mengubah string nominal seperti "-1,234.50", "(1.234,50)" atau "Rp 10.000"
+// menjadi float. decimalSeparator adalah "." atau ",".
+func ParseAmount(value, decimalSeparator string) (float64, error) {
+	value = strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+
+	var builder strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			builder.WriteRune(r)
+		case r == '-':
+			negative = !negative
+		case string(r) == decimalSeparator || (decimalSeparator == "" && r == '.'):
+			builder.WriteRune('.')
+		}
+	}
+
+	if builder.Len() == 0 {
+		return 0, errors.New("empty amount")
+	}
+
+	amount, err := strconv.ParseFloat(builder.String(), 64)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return amount, nil
+}
+
+func typeFromAmount(amount float64) string {
+	if amount < 0 {
+		return "expense"
+	}
+	return "income"
+}
+
+func newCSVReader(r io.Reader, delimiter string) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if delimiter == ";" || delimiter == "\t" {
+		reader.Comma = rune(delimiter[0])
+	}
+	return reader
+}
+
+func trimAll(values []string) []string {
+	trimmed := make([]string, len(values))
+	for i, value := range values {
+		// hapus BOM yang sering ada di CSV hasil export Excel
+		trimmed[i] = strings.TrimSpace(strings.TrimPrefix(value, "\ufeff"))
+	}
+	return trimmed
+}
@@ -0,0 +1,57 @@
+package utility
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultTimezone = "UTC"
+	DefaultLocale   = "en"
+)
+
+// kolom date transaksi menyimpan tanggal kalender lokal user sebagai tengah malam UTC,
+// jadi semua perbandingan tanggal di database tidak bergantung pada timezone koneksi
+
+// LoadLocation mengembalikan timezone IANA, UTC jika nama kosong atau tidak dikenal
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func ValidateTimezone(name string) error {
+	if name == "" {
+		return errors.New("timezone cannot be empty")
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return errors.New("unknown timezone: " + name)
+	}
+	return nil
+}
+
+// LocalDate mengembalikan tanggal kalender t di loc sebagai tengah malam UTC
+func LocalDate(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ParseLocalDate menerima tanggal "2006-01-02" atau timestamp RFC3339. Timestamp diubah ke
+// tanggal kalender di loc, misalnya 2025-01-31T16:30:00Z untuk user WIB menjadi 2025-01-31
+// dan 2025-01-31T17:30:00Z menjadi 2025-02-01.
+func ParseLocalDate(value string, loc *time.Location) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return LocalDate(timestamp, loc), nil
+}
@@ -0,0 +1,264 @@
+package utility
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-electroshop/internal/payload/response"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+	ExportFormatJSON = "json"
+)
+
+// TransactionExportWriter menulis transaksi satu per satu langsung ke output tanpa menampung
+// seluruh hasil export di memori
+type TransactionExportWriter interface {
+	Write(tx response.TransactionResponse) error
+	// Close menulis summary (jika formatnya mendukung) lalu mem-flush sisa output
+	Close(summary response.TransactionSummary) error
+	// Abort membebaskan resource writer jika export gagal di tengah jalan, tidak melakukan
+	// apa-apa setelah Close
+	Abort()
+}
+
+func NewTransactionExportWriter(format string, w io.Writer) (TransactionExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return newCSVExportWriter(w)
+	case ExportFormatXLSX:
+		return newXLSXExportWriter(w)
+	case ExportFormatJSON:
+		return &jsonExportWriter{w: bufio.NewWriter(w)}, nil
+	}
+	return nil, errors.New("unsupported export format: " + format)
+}
+
+func TransactionExportContentType(format string) string {
+	switch format {
+	case ExportFormatCSV:
+		return "text/csv"
+	case ExportFormatJSON:
+		return "application/json"
+	}
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+var transactionExportHeaders = []string{"Date", "Type", "Category", "Amount", "Currency", "Account", "Description", "Tags", "Attachments"}
+
+type transactionExportLine struct {
+	Category    string
+	Amount      float64
+	Description string
+}
+
+// exportLines memecah transaksi yang di-split menjadi satu baris per kategori
+func exportLines(tx response.TransactionResponse) []transactionExportLine {
+	if len(tx.Splits) == 0 {
+		return []transactionExportLine{{Category: tx.Category, Amount: tx.Amount, Description: tx.Description}}
+	}
+
+	lines := make([]transactionExportLine, len(tx.Splits))
+	for i, split := range tx.Splits {
+		description := tx.Description
+		if split.Description != "" && split.Description != tx.Description {
+			description = fmt.Sprintf("%s (%s)", tx.Description, split.Description)
+		}
+		lines[i] = transactionExportLine{Category: split.Category, Amount: split.Amount, Description: description}
+	}
+	return lines
+}
+
+// CSV, tanpa summary supaya file tetap bisa di-import ulang
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer) (*csvExportWriter, error) {
+	writer := &csvExportWriter{w: csv.NewWriter(w)}
+	if err := writer.w.Write(transactionExportHeaders); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (c *csvExportWriter) Write(tx response.TransactionResponse) error {
+	for _, line := range exportLines(tx) {
+		if err := c.w.Write([]string{
+			tx.Date.Format("2006-01-02"),
+			tx.Type,
+			line.Category,
+			strconv.FormatFloat(line.Amount, 'f', 2, 64),
+			tx.Currency,
+			tx.Account,
+			line.Description,
+			strings.Join(tx.Tags, ","),
+			strconv.Itoa(len(tx.Attachments)),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvExportWriter) Close(summary response.TransactionSummary) error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvExportWriter) Abort() {}
+
+// XLSX memakai StreamWriter excelize yang memindahkan baris ke file sementara saat datanya
+// besar, summary ditulis di bawah data seperti export sebelumnya
+type xlsxExportWriter struct {
+	out         io.Writer
+	file        *excelize.File
+	stream      *excelize.StreamWriter
+	amountStyle int
+	row         int
+	closed      bool
+}
+
+func newXLSXExportWriter(w io.Writer) (*xlsxExportWriter, error) {
+	file := excelize.NewFile()
+	sheet := "Transactions"
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	amountStyle, err := file.NewStyle(&excelize.Style{NumFmt: 4}) // #,##0.00
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// lebar kolom harus diatur sebelum baris pertama ditulis
+	if err := stream.SetColWidth(3, 3, 20); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := stream.SetColWidth(7, 7, 40); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	header := make([]interface{}, len(transactionExportHeaders))
+	for i, title := range transactionExportHeaders {
+		header[i] = title
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &xlsxExportWriter{out: w, file: file, stream: stream, amountStyle: amountStyle, row: 1}, nil
+}
+
+func (x *xlsxExportWriter) Write(tx response.TransactionResponse) error {
+	for _, line := range exportLines(tx) {
+		x.row++
+		if err := x.stream.SetRow(fmt.Sprintf("A%d", x.row), []interface{}{
+			tx.Date.Format("2006-01-02"),
+			tx.Type,
+			line.Category,
+			excelize.Cell{StyleID: x.amountStyle, Value: line.Amount},
+			tx.Currency,
+			tx.Account,
+			line.Description,
+			strings.Join(tx.Tags, ","),
+			len(tx.Attachments),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *xlsxExportWriter) Close(summary response.TransactionSummary) error {
+	defer x.Abort()
+
+	// summary dalam base currency user
+	rows := [][]interface{}{
+		{"Summary", "Total Pemasukan", excelize.Cell{StyleID: x.amountStyle, Value: summary.TotalIncome}},
+		{nil, "Total Pengeluaran", excelize.Cell{StyleID: x.amountStyle, Value: summary.TotalExpense}},
+		{nil, "Saldo", excelize.Cell{StyleID: x.amountStyle, Value: summary.Balance}},
+	}
+	for i, values := range rows {
+		if err := x.stream.SetRow(fmt.Sprintf("A%d", x.row+3+i), values); err != nil {
+			return err
+		}
+	}
+
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
+
+// Abort menghapus file sementara StreamWriter
+func (x *xlsxExportWriter) Abort() {
+	if x.closed {
+		return
+	}
+	x.closed = true
+	x.file.Close()
+}
+
+// JSON berbentuk {"transactions": [...], "summary": {...}}, transaksi di-encode satu per satu
+type jsonExportWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func (j *jsonExportWriter) Write(tx response.TransactionResponse) error {
+	prefix := ","
+	if j.count == 0 {
+		prefix = `{"transactions":[`
+	}
+	j.count++
+
+	if _, err := j.w.WriteString(prefix); err != nil {
+		return err
+	}
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonExportWriter) Close(summary response.TransactionSummary) error {
+	if j.count == 0 {
+		if _, err := j.w.WriteString(`{"transactions":[`); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(j.w, `],"summary":%s}`, data); err != nil {
+		return err
+	}
+	return j.w.Flush()
+}
+
+func (j *jsonExportWriter) Abort() {}
@@ -5,11 +5,42 @@ import (
 	"go-electroshop/internal/payload/entity"
 	"go-electroshop/internal/payload/request"
 	"go-electroshop/internal/payload/response"
+	"regexp"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// TransactionSearchVectorSQL adalah dokumen full-text deskripsi transaksi. Ekspresinya harus
+// sama persis dengan index idx_transactions_description_search supaya index terpakai.
+const TransactionSearchVectorSQL = "to_tsvector('simple', coalesce(description, ''))"
+
+// transaksi cocok jika kata dicari ada di deskripsi, nama kategori atau nama kategori split-nya
+const transactionSearchSQL = "(" + TransactionSearchVectorSQL + " @@ to_tsquery('simple', ?)" +
+	" OR EXISTS (SELECT 1 FROM categories WHERE categories.id = transactions.category_id" +
+	" AND to_tsvector('simple', categories.name) @@ to_tsquery('simple', ?))" +
+	" OR EXISTS (SELECT 1 FROM transaction_splits JOIN categories ON categories.id = transaction_splits.category_id" +
+	" WHERE transaction_splits.transaction_id = transactions.id AND to_tsvector('simple', categories.name) @@ to_tsquery('simple', ?)))"
+
+// batas jumlah kata yang dipakai dari query pencarian
+const maxSearchTerms = 8
+
+// searchTermPattern mengambil kata dari query pencarian, karakter lain dibuang supaya input
+// user tidak bisa membentuk operator tsquery
+var searchTermPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// SearchTSQuery mengubah teks pencarian menjadi tsquery awalan kata, "kopi sus" menjadi
+// "kopi:* & sus:*". Kosong jika tidak ada kata yang bisa dicari.
+func SearchTSQuery(q string) string {
+	terms := searchTermPattern.FindAllString(strings.ToLower(q), maxSearchTerms)
+	for i := range terms {
+		terms[i] += ":*"
+	}
+	return strings.Join(terms, " & ")
+}
+
 type TransactionUtil struct {
 	DB *gorm.DB
 }
@@ -35,14 +66,74 @@ func (u *TransactionUtil) BuildFilterQuery(query *gorm.DB, filter request.Transa
 		newQuery = newQuery.Where("category_id = ?", filter.CategoryID)
 	}
 
+	// filter account
+	if filter.AccountID != 0 {
+		newQuery = newQuery.Where("account_id = ?", filter.AccountID)
+	}
+
 	// filter tipe transaksi
 	if filter.Type != "" {
 		newQuery = newQuery.Where("type = ?", filter.Type)
 	}
 
+	// filter tag, transaksi harus memiliki semua tag yang diminta
+	if filter.Tags != "" {
+		var tags []string
+		for _, tag := range strings.Split(filter.Tags, ",") {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		if len(tags) > 0 {
+			newQuery = newQuery.Where("id IN (?)", query.Session(&gorm.Session{NewDB: true}).Table("transaction_tags").
+				Select("transaction_tags.transaction_id").
+				Joins("JOIN tags ON tags.id = transaction_tags.tag_id").
+				Where("tags.name IN ?", tags).
+				Group("transaction_tags.transaction_id").
+				Having("COUNT(DISTINCT tags.id) = ?", len(tags)))
+		}
+	}
+
+	// pencarian teks
+	if tsQuery := SearchTSQuery(filter.Q); tsQuery != "" {
+		newQuery = newQuery.Where(transactionSearchSQL, tsQuery, tsQuery, tsQuery)
+	}
+
+	// filter rentang amount
+	if filter.MinAmount != nil {
+		newQuery = newQuery.Where("amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		newQuery = newQuery.Where("amount <= ?", *filter.MaxAmount)
+	}
+
 	return newQuery
 }
 
+// ApplySort mengurutkan list transaksi sesuai filter.Sort. Amount diurutkan dalam base currency
+// supaya transaksi beda mata uang bisa dibandingkan.
+func (u *TransactionUtil) ApplySort(query *gorm.DB, filter request.TransactionFilter) *gorm.DB {
+	switch filter.Sort {
+	case "date_asc":
+		return query.Order("date ASC")
+	case "amount_desc":
+		return query.Order(TransactionBaseAmountSQL + " DESC, date DESC")
+	case "amount_asc":
+		return query.Order(TransactionBaseAmountSQL + " ASC, date DESC")
+	case "relevance":
+		if tsQuery := SearchTSQuery(filter.Q); tsQuery != "" {
+			return query.Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:                "ts_rank(" + TransactionSearchVectorSQL + ", to_tsquery('simple', ?)) DESC, date DESC",
+				Vars:               []interface{}{tsQuery},
+				WithoutParentheses: true,
+			}})
+		}
+	}
+	return query.Order("date DESC")
+}
+
+// CalculateTransactionSummary menjumlahkan transaksi hasil filter dalam base currency user
+// dengan kurs pada tanggal masing-masing transaksi
 func (u *TransactionUtil) CalculateTransactionSummary(baseQuery *gorm.DB, filter request.TransactionFilter) (*response.TransactionSummary, error) {
 	var totalIncome, totalExpense float64
 
@@ -50,7 +141,7 @@ func (u *TransactionUtil) CalculateTransactionSummary(baseQuery *gorm.DB, filter
 	incomeQuery := baseQuery.Session(&gorm.Session{})
 	if err := incomeQuery.Model(&entity.Transaction{}).
 		Where("type = ?", "income").
-		Select("COALESCE(SUM(amount), 0)").
+		Select("COALESCE(SUM(" + TransactionBaseAmountSQL + "), 0)").
 		Scan(&totalIncome).Error; err != nil {
 		return nil, fmt.Errorf("failed to calculate total income: %v", err)
 	}
@@ -59,7 +150,7 @@ func (u *TransactionUtil) CalculateTransactionSummary(baseQuery *gorm.DB, filter
 	expenseQuery := baseQuery.Session(&gorm.Session{})
 	if err := expenseQuery.Model(&entity.Transaction{}).
 		Where("type = ?", "expense").
-		Select("COALESCE(SUM(amount), 0)").
+		Select("COALESCE(SUM(" + TransactionBaseAmountSQL + "), 0)").
 		Scan(&totalExpense).Error; err != nil {
 		return nil, fmt.Errorf("failed to calculate total expense: %v", err)
 	}
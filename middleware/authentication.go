@@ -46,3 +46,29 @@ func Authentication() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// OptionalAuthentication mengisi info user ke context jika token valid,
+// tapi tetap melanjutkan request anonim
+func OptionalAuthentication() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authHeader := ctx.GetHeader("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			ctx.Next()
+			return
+		}
+
+		token, err := utility.ParseJWT(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil || !token.Valid {
+			ctx.Next()
+			return
+		}
+
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			ctx.Set("userID", claims["sub"])
+			ctx.Set("username", claims["username"])
+			ctx.Set("claims", claims)
+		}
+
+		ctx.Next()
+	}
+}